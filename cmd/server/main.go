@@ -10,17 +10,39 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/dlfelps/tinder-go-claude/internal/handlers"
+	"github.com/dlfelps/tinder-go-claude/internal/logger"
+	"github.com/dlfelps/tinder-go-claude/internal/models"
 	"github.com/dlfelps/tinder-go-claude/internal/services"
 	"github.com/dlfelps/tinder-go-claude/internal/store"
 )
 
 func main() {
+	// Record the start time before anything else, so the health check's
+	// uptime covers the whole life of the process.
+	startedAt := time.Now()
+
+	// Configure logging first, so every later line uses the chosen format.
+	logFormat, err := logger.ParseFormat(os.Getenv("LOG_FORMAT"))
+	if err != nil {
+		log.Fatalf("Invalid LOG_FORMAT: %v", err)
+	}
+	logger.Setup(os.Stderr, logFormat)
+
 	// -----------------------------------------------------------------------
 	// Dependency initialization
 	// -----------------------------------------------------------------------
@@ -30,15 +52,142 @@ func main() {
 
 	// Get the shared in-memory store (singleton).
 	dataStore := store.GetStore()
+	if raw := os.Getenv("MAX_SWIPE_LOG_SIZE"); raw != "" {
+		maxSwipes, err := strconv.Atoi(raw)
+		if err != nil {
+			log.Fatalf("Invalid MAX_SWIPE_LOG_SIZE %q: %v", raw, err)
+		}
+		dataStore.SetMaxSwipes(maxSwipes)
+	}
+	if raw := os.Getenv("USER_CACHE_SIZE"); raw != "" {
+		size, err := strconv.Atoi(raw)
+		if err != nil {
+			log.Fatalf("Invalid USER_CACHE_SIZE %q: %v", raw, err)
+		}
+		dataStore.SetUserCacheSize(size)
+	}
+
+	// DATA_FILE keeps data across restarts: it is loaded now and saved on
+	// shutdown. A missing file just means a fresh, empty store.
+	dataFile := os.Getenv("DATA_FILE")
+	if dataFile != "" {
+		if err := dataStore.LoadFromFile(dataFile); err != nil {
+			log.Fatalf("Failed to load DATA_FILE: %v", err)
+		}
+		slog.Info("loaded store", "file", dataFile, "users", len(dataStore.GetAllUsers()))
+	}
 
 	// Create services with their dependencies.
 	feedService := services.NewFeedService(dataStore)
+	feedService.SetZoneAliases(parseAliasPairs("ZONE_ALIASES", os.Getenv("ZONE_ALIASES")))
+	adjacency, err := loadZoneAdjacency(os.Getenv("ZONE_ADJACENCY"), os.Getenv("ZONE_ADJACENCY_FILE"))
+	if err != nil {
+		log.Fatalf("Invalid zone adjacency: %v", err)
+	}
+	feedService.SetZoneAdjacency(adjacency)
+	if raw := os.Getenv("FEED_EXPOSURE_DEMOTION"); raw != "" {
+		threshold, err := strconv.Atoi(raw)
+		if err != nil {
+			log.Fatalf("Invalid FEED_EXPOSURE_DEMOTION %q: %v", raw, err)
+		}
+		feedService.SetExposureDemotion(threshold)
+	}
+	if raw := os.Getenv("FEED_ONLINE_WINDOW"); raw != "" {
+		window, err := time.ParseDuration(raw)
+		if err != nil {
+			log.Fatalf("Invalid FEED_ONLINE_WINDOW %q: %v", raw, err)
+		}
+		feedService.SetOnlineWindow(window)
+	}
+	if raw := os.Getenv("PASS_EXPIRY"); raw != "" {
+		expiry, err := time.ParseDuration(raw)
+		if err != nil {
+			log.Fatalf("Invalid PASS_EXPIRY %q: %v", raw, err)
+		}
+		feedService.SetPassExpiry(expiry)
+	}
+	if raw := os.Getenv("MAX_FEED_CANDIDATES"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil {
+			log.Fatalf("Invalid MAX_FEED_CANDIDATES %q: %v", raw, err)
+		}
+		feedService.SetMaxFeedCandidates(limit)
+	}
 	swipeService := services.NewSwipeService(dataStore)
+	if raw := os.Getenv("SWIPE_DAILY_LIMIT"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil {
+			log.Fatalf("Invalid SWIPE_DAILY_LIMIT %q: %v", raw, err)
+		}
+		swipeService.SetDailySwipeLimit(limit)
+	}
+	if raw := os.Getenv("MAX_ACTIVE_LIKES"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil {
+			log.Fatalf("Invalid MAX_ACTIVE_LIKES %q: %v", raw, err)
+		}
+		swipeService.SetMaxActiveLikes(limit)
+	}
+	// WEBHOOK_URL, when set, receives a POST for every new match. The
+	// deferred Close runs after the server has shut down, so matches still
+	// queued then are sent before the process exits.
+	if raw := os.Getenv("WEBHOOK_URL"); raw != "" {
+		webhook, err := services.NewMatchWebhook(raw, dataStore)
+		if err != nil {
+			log.Fatalf("Invalid WEBHOOK_URL: %v", err)
+		}
+		defer webhook.Close()
+		swipeService.OnMatch(webhook.Notify)
+	}
+	matchService := services.NewMatchService(dataStore)
+	simulationService := services.NewSimulationService(dataStore, swipeService)
+	notificationService := services.NewNotificationService(dataStore)
+	analyticsService := services.NewAnalyticsService(dataStore)
 
 	// Create handlers with their dependencies.
+	handlers.SetOmitNullErrorData(envBool("ERROR_OMIT_NULL_DATA", false))
+	handlers.SetRequireJSONContentType(envBool("REQUIRE_JSON_CONTENT_TYPE", false))
 	userHandler := handlers.NewUserHandler(dataStore)
+	userHandler.SetGenderSynonyms(parseAliasPairs("GENDER_SYNONYMS", os.Getenv("GENDER_SYNONYMS")))
 	feedHandler := handlers.NewFeedHandler(feedService)
-	swipeHandler := handlers.NewSwipeHandler(swipeService, dataStore)
+	swipeHandler := handlers.NewSwipeHandler(swipeService, matchService, dataStore)
+	swipeHandler.SetLikeBackSuggestion(envBool("SWIPE_LIKE_BACK_SUGGESTION", false))
+	actionAliases, err := models.NewSwipeActionAliases(parseAliasPairs("SWIPE_ACTION_ALIASES", os.Getenv("SWIPE_ACTION_ALIASES")))
+	if err != nil {
+		log.Fatalf("Invalid SWIPE_ACTION_ALIASES: %v", err)
+	}
+	swipeHandler.SetActionAliases(actionAliases)
+	if raw := os.Getenv("ALLOW_MATCH_UNDO"); raw != "" {
+		allowed, err := strconv.ParseBool(raw)
+		if err != nil {
+			log.Fatalf("Invalid ALLOW_MATCH_UNDO %q: %v", raw, err)
+		}
+		swipeHandler.SetAllowMatchUndo(allowed)
+	}
+	matchHandler := handlers.NewMatchHandler(dataStore)
+	blockHandler := handlers.NewBlockHandler(dataStore)
+	reportHandler := handlers.NewReportHandler(dataStore)
+	zoneHandler := handlers.NewZoneHandler(dataStore)
+	notificationHandler := handlers.NewNotificationHandler(notificationService)
+	analyticsHandler := handlers.NewAnalyticsHandler(analyticsService)
+	statsHandler := handlers.NewStatsHandler(dataStore)
+	healthHandler := handlers.NewHealthHandler(dataStore, startedAt)
+	metrics := handlers.NewMetrics()
+
+	// Read-only mode blocks writes during maintenance windows. It can start
+	// enabled via READ_ONLY=true and be toggled at runtime by an admin.
+	readOnly := envBool("READ_ONLY", false)
+	readOnlyGuard := handlers.NewReadOnlyGuard(readOnly)
+	adminHandler := handlers.NewAdminHandler(simulationService, analyticsService, notificationService, dataStore, readOnlyGuard)
+
+	// Admin endpoints require this token in the X-Admin-Token header. When
+	// ADMIN_TOKEN is unset, admin endpoints reject every request.
+	adminToken := os.Getenv("ADMIN_TOKEN")
+
+	// Debug endpoints, such as the demo data seeder, only respond when
+	// ENABLE_DEBUG is true; otherwise they return 404.
+	debugEnabled := envBool("ENABLE_DEBUG", false)
+	debugHandler := handlers.NewDebugHandler(simulationService)
 
 	// -----------------------------------------------------------------------
 	// Router setup
@@ -54,18 +203,77 @@ func main() {
 	// Path parameters use {name} syntax and are accessed via r.PathValue("name").
 
 	// Health check — GET /
-	mux.HandleFunc("GET /", handlers.HealthCheck)
+	mux.HandleFunc("GET /", healthHandler.HealthCheck)
+
+	// API description — GET /openapi.json
+	mux.HandleFunc("GET /openapi.json", handlers.GetOpenAPISpec)
+
+	// Aggregate counts — GET /stats
+	mux.HandleFunc("GET /stats", statsHandler.GetStats)
+
+	// Request counters and latency histograms — GET /metrics
+	mux.HandleFunc("GET /metrics", metrics.GetMetrics)
 
 	// User endpoints
-	mux.HandleFunc("POST /users/", userHandler.CreateUser)    // Create user
-	mux.HandleFunc("GET /users/{id}", userHandler.GetUser)     // Get user by ID
+	mux.HandleFunc("POST /users/", userHandler.CreateUser)                           // Create user
+	mux.HandleFunc("GET /users", userHandler.ListUsers)                              // List users
+	mux.HandleFunc("POST /users/batch", userHandler.BatchGetUsers)                   // Batch lookup
+	mux.HandleFunc("GET /users/{id}", userHandler.GetUser)                           // Get user by ID
+	mux.HandleFunc("PUT /users/{id}", userHandler.UpdateUser)                        // Update profile
+	mux.HandleFunc("DELETE /users/{id}", userHandler.DeleteUser)                     // Delete user (cascades)
+	mux.HandleFunc("GET /users/{id}/export", userHandler.ExportUser)                 // Export user data
+	mux.HandleFunc("GET /users/{id}/throughput", analyticsHandler.GetUserThroughput) // Swipe stats
+	mux.HandleFunc("PUT /users/{id}/preferences", userHandler.UpdatePreferences)     // Notification preferences
+	mux.HandleFunc("GET /users/{id}/zone-mates", zoneHandler.GetZoneMates)           // Same-zone users
+	mux.HandleFunc("POST /boost", userHandler.Boost)                                 // Boost in feeds
 
-	// Feed endpoint
-	mux.HandleFunc("GET /feed", feedHandler.GetFeed) // Get discovery feed
+	// Feed endpoints
+	mux.HandleFunc("GET /feed", feedHandler.GetFeed)         // Get discovery feed
+	mux.HandleFunc("GET /feed/count", feedHandler.CountFeed) // Count feed candidates
 
 	// Swipe and match endpoints
-	mux.HandleFunc("POST /swipe", swipeHandler.CreateSwipe)  // Record a swipe
-	mux.HandleFunc("GET /matches", swipeHandler.GetMatches)  // List matches
+	mux.HandleFunc("POST /swipe", swipeHandler.CreateSwipe)               // Record a swipe
+	mux.HandleFunc("POST /swipe/undo", swipeHandler.UndoSwipe)            // Take back the last swipe
+	mux.HandleFunc("GET /swipes", swipeHandler.GetSwipes)                 // Swipe history
+	mux.HandleFunc("POST /block", blockHandler.CreateBlock)               // Block a user
+	mux.HandleFunc("POST /reports", reportHandler.CreateReport)           // Report a user
+	mux.HandleFunc("GET /matches", swipeHandler.GetMatches)               // List matches
+	mux.HandleFunc("GET /matches/check", matchHandler.CheckMatch)         // Match between two users
+	mux.HandleFunc("GET /matches/trail", matchHandler.GetMatchTrail)      // Match swipe trail
+	mux.HandleFunc("GET /matches/recent", matchHandler.GetRecentMatches)  // Anonymized match ticker
+	mux.HandleFunc("GET /matches/{id}", matchHandler.GetMatch)            // Get match by ID
+	mux.HandleFunc("DELETE /matches/{id}", matchHandler.DeleteMatch)      // Unmatch
+	mux.HandleFunc("POST /matches/{id}/seen", matchHandler.MarkMatchSeen) // Mark match seen
+
+	// Notification endpoints
+	mux.HandleFunc("GET /notifications/matches", notificationHandler.GetMatchEvents) // Poll new matches
+
+	// Zone endpoints
+	mux.HandleFunc("GET /zones/{zone_id}/active", zoneHandler.GetActiveUsers) // Recently active users
+
+	// Admin endpoints — each is wrapped by RequireAdmin.
+	mux.HandleFunc("POST /admin/simulate", handlers.RequireAdmin(adminToken, adminHandler.Simulate))              // Load simulation
+	mux.HandleFunc("POST /admin/read-only", handlers.RequireAdmin(adminToken, adminHandler.SetReadOnly))          // Toggle read-only mode
+	mux.HandleFunc("GET /admin/analytics/swipes", handlers.RequireAdmin(adminToken, adminHandler.SwipeAnalytics)) // Swipe rate
+	mux.HandleFunc("GET /admin/digest", handlers.RequireAdmin(adminToken, adminHandler.Digest))                   // Mailer digest
+	mux.HandleFunc("GET /admin/validate", handlers.RequireAdmin(adminToken, adminHandler.Validate))               // Integrity check
+	mux.HandleFunc("GET /admin/unseen-users", handlers.RequireAdmin(adminToken, adminHandler.UnseenUsers))        // Never-swiped users
+	mux.HandleFunc("GET /reports", handlers.RequireAdmin(adminToken, reportHandler.ListReports))                  // Abuse reports
+	mux.HandleFunc("DELETE /swipe", handlers.RequireAdmin(adminToken, swipeHandler.DeleteSwipe))                  // Remove a swipe
+
+	// Debug endpoints — each is wrapped by RequireDebug.
+	mux.HandleFunc("POST /debug/seed", handlers.RequireDebug(debugEnabled, debugHandler.Seed)) // Demo data
+
+	// Wrap the router with the read-only guard. The toggle endpoint is exempt
+	// so read-only mode can always be switched off again, and the batch user
+	// lookup because it only reads, despite being a POST.
+	handler := readOnlyGuard.Middleware(mux, "/admin/read-only", "/users/batch")
+
+	// Turn a panic anywhere below into a 500 error envelope.
+	handler = handlers.RecoverMiddleware(handler)
+
+	// Outermost, count and time every request, including ones that panic.
+	handler = metrics.Middleware(handler)
 
 	// -----------------------------------------------------------------------
 	// Server startup
@@ -78,12 +286,123 @@ func main() {
 	}
 
 	addr := fmt.Sprintf(":%s", port)
-	log.Printf("Tinder-Claude API server starting on http://localhost%s", addr)
+	// The timeouts stop slow or idle clients from holding connections open
+	// forever: ReadHeaderTimeout and ReadTimeout bound how long a client may
+	// take to send its headers and its whole request (slowloris-style
+	// attacks trickle these in), and IdleTimeout closes keep-alive
+	// connections nobody is using.
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       15 * time.Second,
+		IdleTimeout:       60 * time.Second,
+	}
+
+	// ListenAndServe blocks until the server stops, so it runs in its own
+	// goroutine while main waits for a stop signal. When Shutdown is called
+	// it returns http.ErrServerClosed, which is the normal way out rather
+	// than a failure.
+	go func() {
+		slog.Info("Tinder-Claude API server starting", "url", "http://localhost"+addr)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("Server failed to start: %v", err)
+		}
+	}()
+
+	// -----------------------------------------------------------------------
+	// Graceful shutdown
+	// -----------------------------------------------------------------------
+	// signal.NotifyContext returns a context that is cancelled on SIGINT
+	// (Ctrl+C) or SIGTERM (sent by `docker stop` and Kubernetes), instead of
+	// the process being killed outright.
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	<-ctx.Done()
+	slog.Info("shutting down; waiting for in-flight requests", "timeout", shutdownTimeout.String())
+
+	// Shutdown stops accepting connections and waits for active requests to
+	// finish, or for the timeout to expire.
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		slog.Warn("shutdown did not complete cleanly", "error", err)
+	}
+
+	// Save only after requests have drained, so no late write is lost.
+	if dataFile != "" {
+		if err := dataStore.SaveToFile(dataFile); err != nil {
+			log.Fatalf("Failed to save DATA_FILE: %v", err)
+		}
+		slog.Info("saved store", "file", dataFile)
+	}
+	slog.Info("server stopped")
+}
+
+// shutdownTimeout is how long in-flight requests get to finish after a stop
+// signal before the server closes their connections.
+const shutdownTimeout = 10 * time.Second
+
+// envBool reads a boolean environment variable, returning fallback when it
+// is unset. Anything strconv.ParseBool doesn't accept, such as "yes", stops
+// the server: guessing could leave a safety switch like READ_ONLY off when
+// the operator meant it to be on.
+func envBool(name string, fallback bool) bool {
+	raw := os.Getenv(name)
+	if raw == "" {
+		return fallback
+	}
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		log.Fatalf("Invalid %s %q: %v", name, raw, err)
+	}
+	return value
+}
+
+// parseAliasPairs parses a comma-separated list of alias=value pairs from
+// the named environment variable, such as "nyc=new-york,ny=new-york".
+// Malformed entries are logged and skipped.
+func parseAliasPairs(envName, raw string) map[string]string {
+	aliases := make(map[string]string)
+	for _, pair := range strings.Split(raw, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		alias, value, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(alias) == "" || strings.TrimSpace(value) == "" {
+			slog.Warn("ignoring malformed entry", "variable", envName, "entry", pair)
+			continue
+		}
+		aliases[alias] = value
+	}
+	return aliases
+}
+
+// loadZoneAdjacency reads the zone adjacency map, a JSON object such as
+// {"zone-a": ["zone-b", "zone-c"]}, either inline from ZONE_ADJACENCY or
+// from the file named by ZONE_ADJACENCY_FILE. Setting both is an error, so
+// it's never unclear which one applies. With neither set it returns nil,
+// which keeps feeds to a single zone.
+func loadZoneAdjacency(inline, path string) (map[string][]string, error) {
+	var raw []byte
+	switch {
+	case inline != "" && path != "":
+		return nil, errors.New("set ZONE_ADJACENCY or ZONE_ADJACENCY_FILE, not both")
+	case inline != "":
+		raw = []byte(inline)
+	case path != "":
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		raw = data
+	default:
+		return nil, nil
+	}
 
-	// http.ListenAndServe starts the HTTP server. It blocks (runs forever)
-	// until the server encounters a fatal error. If it returns an error,
-	// we log it and exit. This is equivalent to uvicorn.run() in FastAPI.
-	if err := http.ListenAndServe(addr, mux); err != nil {
-		log.Fatalf("Server failed to start: %v", err)
+	var adjacency map[string][]string
+	if err := json.Unmarshal(raw, &adjacency); err != nil {
+		return nil, fmt.Errorf("expected a JSON object of zone ID to neighbor list: %w", err)
 	}
+	return adjacency, nil
 }
@@ -0,0 +1,190 @@
+// This file contains HTTP handlers for admin-only endpoints. Every route in
+// this file must be registered behind RequireAdmin:
+//   - POST /admin/simulate — Generate synthetic users and swipes for load testing
+//   - POST /admin/read-only — Turn read-only mode on or off
+//   - GET  /admin/analytics/swipes?bucket=<duration> — Swipe counts over time
+//   - GET  /admin/digest?since=<timestamp> — Per-user matches and likes for a mailer
+//   - GET  /admin/validate — Scan the store for integrity problems
+//   - GET  /admin/unseen-users — Users nobody has swiped on yet
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/dlfelps/tinder-go-claude/internal/services"
+	"github.com/dlfelps/tinder-go-claude/internal/store"
+)
+
+// AdminHandler groups admin and operational HTTP handlers.
+type AdminHandler struct {
+	simulationService *services.SimulationService
+	analyticsService  *services.AnalyticsService
+	notifications     *services.NotificationService
+	store             *store.InMemoryStore
+	readOnly          *ReadOnlyGuard
+}
+
+// NewAdminHandler creates a new AdminHandler with the given services, the
+// store (for integrity checks), and the read-only guard it controls.
+func NewAdminHandler(sim *services.SimulationService, as *services.AnalyticsService, ns *services.NotificationService, s *store.InMemoryStore, readOnly *ReadOnlyGuard) *AdminHandler {
+	return &AdminHandler{
+		simulationService: sim,
+		analyticsService:  as,
+		notifications:     ns,
+		store:             s,
+		readOnly:          readOnly,
+	}
+}
+
+// simulateRequest is the JSON body accepted by POST /admin/simulate.
+// It is unexported because only this handler uses it.
+type simulateRequest struct {
+	Users  int `json:"users"`
+	Swipes int `json:"swipes"`
+}
+
+// Simulate handles POST /admin/simulate — generates N users and M random
+// swipes through the real services and reports the outcome and timing.
+func (h *AdminHandler) Simulate(w http.ResponseWriter, r *http.Request) {
+	var req simulateRequest
+	if !decodeJSONBody(w, r, &req) {
+		return
+	}
+
+	result, err := h.simulationService.Simulate(req.Users, req.Swipes)
+	if err != nil {
+		var validationErr *services.ValidationError
+		if errors.As(err, &validationErr) {
+			writeError(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		writeInternalError(w, err)
+		return
+	}
+
+	writeSuccess(w, http.StatusOK, result, nil)
+}
+
+// readOnlyRequest is the JSON body accepted by POST /admin/read-only. A
+// pointer lets us tell a missing field apart from an explicit false.
+type readOnlyRequest struct {
+	Enabled *bool `json:"enabled"`
+}
+
+// SetReadOnly handles POST /admin/read-only — toggles read-only mode and
+// returns the new state.
+func (h *AdminHandler) SetReadOnly(w http.ResponseWriter, r *http.Request) {
+	var req readOnlyRequest
+	if !decodeJSONBody(w, r, &req) {
+		return
+	}
+	if req.Enabled == nil {
+		writeError(w, http.StatusUnprocessableEntity, "enabled is required")
+		return
+	}
+
+	h.readOnly.SetEnabled(*req.Enabled)
+	writeSuccess(w, http.StatusOK, map[string]bool{"read_only": h.readOnly.Enabled()}, nil)
+}
+
+// defaultSwipeBucket is used when GET /admin/analytics/swipes omits "bucket".
+const defaultSwipeBucket = time.Hour
+
+// SwipeAnalytics handles GET /admin/analytics/swipes?bucket=1h — returns
+// swipe counts grouped into time windows of the requested size.
+func (h *AdminHandler) SwipeAnalytics(w http.ResponseWriter, r *http.Request) {
+	bucket := defaultSwipeBucket
+	if raw := r.URL.Query().Get("bucket"); raw != "" {
+		d, err := time.ParseDuration(raw)
+		if err != nil || d <= 0 {
+			writeError(w, http.StatusUnprocessableEntity, "bucket must be a positive duration such as 1h or 15m")
+			return
+		}
+		bucket = d
+	}
+
+	buckets, err := h.analyticsService.SwipeRate(bucket)
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+
+	writeSuccess(w, http.StatusOK, buckets, map[string]any{
+		"count":  len(buckets),
+		"bucket": bucket.String(),
+	})
+}
+
+// Digest handles GET /admin/digest?since=<RFC 3339 timestamp> — returns, per
+// user, the matches and likes they received since the given time, for a
+// downstream job that sends summary emails.
+func (h *AdminHandler) Digest(w http.ResponseWriter, r *http.Request) {
+	raw := r.URL.Query().Get("since")
+	if raw == "" {
+		writeError(w, http.StatusUnprocessableEntity, "since query parameter is required")
+		return
+	}
+	since, err := time.Parse(time.RFC3339Nano, raw)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, "since must be an RFC 3339 timestamp")
+		return
+	}
+
+	digests := h.notifications.Digest(since)
+	writeSuccess(w, http.StatusOK, digests, map[string]any{
+		"count": len(digests),
+		"since": since,
+	})
+}
+
+// Validate handles GET /admin/validate — runs the store's integrity checks.
+// It always returns 200: finding problems is a successful check, and
+// meta.valid tells the caller whether any were found.
+func (h *AdminHandler) Validate(w http.ResponseWriter, r *http.Request) {
+	issues := h.store.Validate()
+	writeSuccess(w, http.StatusOK, issues, map[string]any{
+		"count": len(issues),
+		"valid": len(issues) == 0,
+	})
+}
+
+// Page sizes for GET /admin/unseen-users.
+const (
+	defaultUnseenUsersPageSize = 20
+	maxUnseenUsersPageSize     = 100
+)
+
+// UnseenUsers handles GET /admin/unseen-users?limit=<n>&offset=<n> — returns
+// one page of users who have no incoming swipes, so ops can see who the feed
+// isn't surfacing. The users who have waited longest come first (by
+// joined_at, then ID to break ties). meta.total is the number of unseen
+// users.
+func (h *AdminHandler) UnseenUsers(w http.ResponseWriter, r *http.Request) {
+	limit, err := queryInt(r, "limit", defaultUnseenUsersPageSize)
+	if err != nil || limit < 1 || limit > maxUnseenUsersPageSize {
+		writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("limit must be an integer between 1 and %d", maxUnseenUsersPageSize))
+		return
+	}
+	offset, err := queryInt(r, "offset", 0)
+	if err != nil || offset < 0 {
+		writeError(w, http.StatusUnprocessableEntity, "offset must be a non-negative integer")
+		return
+	}
+
+	users := h.store.GetUnswipedUsers()
+	sort.Slice(users, func(i, j int) bool {
+		if !users[i].JoinedAt.Equal(users[j].JoinedAt) {
+			return users[i].JoinedAt.Before(users[j].JoinedAt)
+		}
+		return bytes.Compare(users[i].ID[:], users[j].ID[:]) < 0
+	})
+
+	total := len(users)
+	page := users[min(offset, total):min(offset+limit, total)]
+	writePaginated(w, page, total, limit, offset)
+}
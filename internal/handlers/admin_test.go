@@ -0,0 +1,318 @@
+// This file contains tests for the admin endpoints and the RequireAdmin guard.
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/dlfelps/tinder-go-claude/internal/models"
+	"github.com/dlfelps/tinder-go-claude/internal/store"
+	"github.com/google/uuid"
+)
+
+func TestRequireAdmin_RejectsMissingOrWrongToken(t *testing.T) {
+	mux := setupTestRouter(t)
+	body := map[string]int{"users": 2, "swipes": 1}
+
+	rr := doRequest(t, mux, "POST", "/admin/simulate", body)
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("missing token: got %d, want %d", rr.Code, http.StatusForbidden)
+	}
+
+	rr = doRequestWithHeaders(t, mux, "POST", "/admin/simulate", body, map[string]string{
+		AdminTokenHeader: "wrong",
+	})
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("wrong token: got %d, want %d", rr.Code, http.StatusForbidden)
+	}
+}
+
+func TestRequireAdmin_EmptyTokenDisablesEndpoint(t *testing.T) {
+	called := false
+	handler := RequireAdmin("", func(w http.ResponseWriter, r *http.Request) { called = true })
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /admin/thing", handler)
+
+	rr := doRequestWithHeaders(t, mux, "GET", "/admin/thing", nil, map[string]string{AdminTokenHeader: ""})
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("status: got %d, want %d", rr.Code, http.StatusForbidden)
+	}
+	if called {
+		t.Error("wrapped handler should not run when no admin token is configured")
+	}
+}
+
+func TestSimulate_ReportsConsistentCounts(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	rr := doAdminRequest(t, mux, "POST", "/admin/simulate", map[string]int{"users": 5, "swipes": 40})
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want %d (body: %s)", rr.Code, http.StatusOK, rr.Body.String())
+	}
+
+	data := parseResponse(t, rr).Data.(map[string]interface{})
+	likes := int(data["likes"].(float64))
+	passes := int(data["passes"].(float64))
+	matches := int(data["matches"].(float64))
+
+	if int(data["users_created"].(float64)) != 5 {
+		t.Errorf("users_created: got %v, want 5", data["users_created"])
+	}
+	if likes+passes != 40 {
+		t.Errorf("likes + passes: got %d, want 40", likes+passes)
+	}
+	if matches > likes {
+		t.Errorf("matches (%d) exceed likes (%d)", matches, likes)
+	}
+	if _, ok := data["duration_ms"]; !ok {
+		t.Error("expected duration_ms in response")
+	}
+}
+
+func TestSimulate_InvalidInput(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	rr := doAdminRequest(t, mux, "POST", "/admin/simulate", map[string]int{"users": 1, "swipes": 1})
+	if rr.Code != http.StatusUnprocessableEntity {
+		t.Errorf("status: got %d, want %d", rr.Code, http.StatusUnprocessableEntity)
+	}
+}
+
+func TestReadOnlyMode_BlocksWritesButAllowsReads(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+	bobID, _ := createTestUser(t, mux, "Bob", "male", "zone-a", 30)
+
+	rr := doAdminRequest(t, mux, "POST", "/admin/read-only", map[string]bool{"enabled": true})
+	if rr.Code != http.StatusOK {
+		t.Fatalf("enable read-only: got %d, want %d", rr.Code, http.StatusOK)
+	}
+
+	// Reads keep working.
+	rr = doRequest(t, mux, "GET", fmt.Sprintf("/feed?user_id=%s", aliceID), nil)
+	if rr.Code != http.StatusOK {
+		t.Errorf("GET /feed: got %d, want %d", rr.Code, http.StatusOK)
+	}
+
+	// Writes are refused.
+	rr = doRequest(t, mux, "POST", "/swipe", models.CreateSwipeRequest{
+		SwiperID: aliceID.String(), SwipedID: bobID.String(), Action: "LIKE",
+	})
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Errorf("POST /swipe: got %d, want %d", rr.Code, http.StatusServiceUnavailable)
+	}
+	rr = doRequest(t, mux, "POST", "/users/", models.CreateUserRequest{
+		Name: "Carol", Age: 25, Gender: "female", ZoneID: "zone-a",
+	})
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Errorf("POST /users/: got %d, want %d", rr.Code, http.StatusServiceUnavailable)
+	}
+
+	// The toggle itself stays reachable, so the mode can be switched off.
+	rr = doAdminRequest(t, mux, "POST", "/admin/read-only", map[string]bool{"enabled": false})
+	if rr.Code != http.StatusOK {
+		t.Fatalf("disable read-only: got %d, want %d", rr.Code, http.StatusOK)
+	}
+	rr = doRequest(t, mux, "POST", "/swipe", models.CreateSwipeRequest{
+		SwiperID: aliceID.String(), SwipedID: bobID.String(), Action: "LIKE",
+	})
+	if rr.Code != http.StatusCreated {
+		t.Errorf("POST /swipe after disabling: got %d, want %d", rr.Code, http.StatusCreated)
+	}
+}
+
+func TestReadOnlyMode_AllowsBatchLookup(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+
+	rr := doAdminRequest(t, mux, "POST", "/admin/read-only", map[string]bool{"enabled": true})
+	if rr.Code != http.StatusOK {
+		t.Fatalf("enable read-only: got %d, want %d", rr.Code, http.StatusOK)
+	}
+
+	// POST /users/batch only reads, so it keeps working like a GET.
+	rr = doRequest(t, mux, "POST", "/users/batch", models.BatchUsersRequest{IDs: []string{aliceID.String()}})
+	if rr.Code != http.StatusOK {
+		t.Errorf("POST /users/batch: got %d, want %d", rr.Code, http.StatusOK)
+	}
+}
+
+func TestSetReadOnly_RequiresEnabledField(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	rr := doAdminRequest(t, mux, "POST", "/admin/read-only", map[string]string{})
+	if rr.Code != http.StatusUnprocessableEntity {
+		t.Errorf("status: got %d, want %d", rr.Code, http.StatusUnprocessableEntity)
+	}
+}
+
+func TestSwipeAnalytics_ReturnsBuckets(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	alice, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 25)
+	bob, _ := createTestUser(t, mux, "Bob", "male", "zone-a", 27)
+	doRequest(t, mux, "POST", "/swipe", models.CreateSwipeRequest{
+		SwiperID: alice.String(), SwipedID: bob.String(), Action: "LIKE",
+	})
+	doRequest(t, mux, "POST", "/swipe", models.CreateSwipeRequest{
+		SwiperID: bob.String(), SwipedID: alice.String(), Action: "PASS",
+	})
+
+	rr := doAdminRequest(t, mux, "GET", "/admin/analytics/swipes?bucket=24h", nil)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want %d", rr.Code, http.StatusOK)
+	}
+
+	resp := parseResponse(t, rr)
+	data, ok := resp.Data.([]interface{})
+	if !ok {
+		t.Fatal("expected data to be an array")
+	}
+
+	// Both swipes happen moments apart; unless the test straddles midnight
+	// UTC they share a bucket, so check the total rather than the layout.
+	total := 0
+	for _, entry := range data {
+		total += int(entry.(map[string]interface{})["count"].(float64))
+	}
+	if total != 2 {
+		t.Errorf("expected 2 swipes across buckets, got %d", total)
+	}
+	if resp.Meta["bucket"] != "24h0m0s" {
+		t.Errorf("expected meta.bucket=24h0m0s, got %v", resp.Meta["bucket"])
+	}
+}
+
+func TestSwipeAnalytics_InvalidBucket(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	for _, bucket := range []string{"hourly", "0s", "-1h"} {
+		t.Run(bucket, func(t *testing.T) {
+			rr := doAdminRequest(t, mux, "GET", "/admin/analytics/swipes?bucket="+bucket, nil)
+			if rr.Code != http.StatusUnprocessableEntity {
+				t.Errorf("status: got %d, want %d", rr.Code, http.StatusUnprocessableEntity)
+			}
+		})
+	}
+}
+
+func TestAdminDigest(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+	bobID, _ := createTestUser(t, mux, "Bob", "male", "zone-a", 30)
+	since := time.Now().UTC().Add(-time.Minute).Format(time.RFC3339Nano)
+
+	doRequest(t, mux, "POST", "/swipe", models.CreateSwipeRequest{
+		SwiperID: bobID.String(), SwipedID: aliceID.String(), Action: "LIKE",
+	})
+
+	rr := doAdminRequest(t, mux, "GET", "/admin/digest?since="+url.QueryEscape(since), nil)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want %d", rr.Code, http.StatusOK)
+	}
+	data := parseResponse(t, rr).Data.([]interface{})
+	if len(data) != 1 {
+		t.Fatalf("expected 1 digest, got %d", len(data))
+	}
+	digest := data[0].(map[string]interface{})
+	if digest["user_id"] != aliceID.String() {
+		t.Errorf("expected digest for Alice, got %v", digest["user_id"])
+	}
+	if likes := digest["likes_received"].([]interface{}); len(likes) != 1 {
+		t.Errorf("expected 1 like, got %d", len(likes))
+	}
+}
+
+func TestAdminDigest_InvalidSince(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	for _, query := range []string{"", "?since=yesterday"} {
+		rr := doAdminRequest(t, mux, "GET", "/admin/digest"+query, nil)
+		if rr.Code != http.StatusUnprocessableEntity {
+			t.Errorf("%q: got %d, want %d", query, rr.Code, http.StatusUnprocessableEntity)
+		}
+	}
+}
+
+func TestAdminValidate(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+	bobID, _ := createTestUser(t, mux, "Bob", "male", "zone-a", 30)
+	createTestMatch(t, mux, aliceID, bobID)
+
+	rr := doAdminRequest(t, mux, "GET", "/admin/validate", nil)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want %d", rr.Code, http.StatusOK)
+	}
+	resp := parseResponse(t, rr)
+	if resp.Meta["valid"] != true {
+		t.Errorf("expected a clean store, got issues %v", resp.Data)
+	}
+
+	// Inject a match with a user who doesn't exist.
+	store.GetStore().AddMatch(models.Match{User1ID: aliceID, User2ID: uuid.New(), Timestamp: time.Now().UTC()})
+
+	resp = parseResponse(t, doAdminRequest(t, mux, "GET", "/admin/validate", nil))
+	if resp.Meta["valid"] != false {
+		t.Error("expected meta.valid=false after injecting an orphaned match")
+	}
+	if issues := resp.Data.([]interface{}); len(issues) != 1 {
+		t.Errorf("expected 1 issue, got %v", issues)
+	}
+}
+
+func TestAdminUnseenUsers(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+	bobID, _ := createTestUser(t, mux, "Bob", "male", "zone-a", 30)
+
+	// Alice passes on Bob, so Bob has an incoming swipe and Alice doesn't.
+	rr := doRequest(t, mux, "POST", "/swipe", models.CreateSwipeRequest{
+		SwiperID: aliceID.String(), SwipedID: bobID.String(), Action: "PASS",
+	})
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("swipe status: got %d, want %d", rr.Code, http.StatusCreated)
+	}
+	carolID, _ := createTestUser(t, mux, "Carol", "female", "zone-a", 27)
+
+	if rr := doRequest(t, mux, "GET", "/admin/unseen-users", nil); rr.Code != http.StatusForbidden {
+		t.Errorf("without token: got %d, want %d", rr.Code, http.StatusForbidden)
+	}
+
+	rr = doAdminRequest(t, mux, "GET", "/admin/unseen-users", nil)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want %d", rr.Code, http.StatusOK)
+	}
+	resp := parseResponse(t, rr)
+	if resp.Meta["total"] != float64(2) {
+		t.Errorf("meta.total: got %v, want 2", resp.Meta["total"])
+	}
+	var ids []string
+	for _, item := range resp.Data.([]interface{}) {
+		ids = append(ids, item.(map[string]interface{})["id"].(string))
+	}
+	// Alice joined first, so she has waited longest.
+	if len(ids) != 2 || ids[0] != aliceID.String() || ids[1] != carolID.String() {
+		t.Errorf("unseen users: got %v, want [Alice Carol]", ids)
+	}
+
+	resp = parseResponse(t, doAdminRequest(t, mux, "GET", "/admin/unseen-users?limit=1&offset=1", nil))
+	if page := resp.Data.([]interface{}); len(page) != 1 || page[0].(map[string]interface{})["id"] != carolID.String() {
+		t.Errorf("second page: got %v, want only Carol", page)
+	}
+
+	for _, query := range []string{"limit=0", "limit=101", "offset=-1", "limit=x"} {
+		if rr := doAdminRequest(t, mux, "GET", "/admin/unseen-users?"+query, nil); rr.Code != http.StatusUnprocessableEntity {
+			t.Errorf("%s: got %d, want %d", query, rr.Code, http.StatusUnprocessableEntity)
+		}
+	}
+}
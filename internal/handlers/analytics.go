@@ -0,0 +1,48 @@
+// This file contains HTTP handlers for per-user analytics:
+//   - GET /users/{id}/throughput — Daily swipe counts and match rate
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/dlfelps/tinder-go-claude/internal/services"
+	"github.com/google/uuid"
+)
+
+// AnalyticsHandler serves a user's own engagement stats. Store-wide reports
+// live on AdminHandler instead, behind the admin token.
+type AnalyticsHandler struct {
+	analyticsService *services.AnalyticsService
+}
+
+// NewAnalyticsHandler creates a new AnalyticsHandler with the given service.
+func NewAnalyticsHandler(as *services.AnalyticsService) *AnalyticsHandler {
+	return &AnalyticsHandler{analyticsService: as}
+}
+
+// GetUserThroughput handles GET /users/{id}/throughput — returns the user's
+// swipes per day for the last week and their all-time match rate.
+func (h *AnalyticsHandler) GetUserThroughput(w http.ResponseWriter, r *http.Request) {
+	// As with GET /users/{id}, an ID that isn't a UUID can't name a user.
+	userID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, "user not found")
+		return
+	}
+
+	throughput, err := h.analyticsService.UserThroughput(userID)
+	if err != nil {
+		var notFoundErr *services.NotFoundError
+		if errors.As(err, &notFoundErr) {
+			writeError(w, http.StatusNotFound, "user not found")
+			return
+		}
+		writeInternalError(w, err)
+		return
+	}
+
+	writeSuccess(w, http.StatusOK, throughput, map[string]any{
+		"days": services.ThroughputDays,
+	})
+}
@@ -0,0 +1,65 @@
+// This file contains integration tests for the per-user analytics endpoint.
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/dlfelps/tinder-go-claude/internal/models"
+	"github.com/google/uuid"
+)
+
+func TestGetUserThroughput(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+	bobID, _ := createTestUser(t, mux, "Bob", "male", "zone-a", 30)
+	carolID, _ := createTestUser(t, mux, "Carol", "female", "zone-a", 27)
+
+	for _, swipe := range []models.CreateSwipeRequest{
+		{SwiperID: aliceID.String(), SwipedID: bobID.String(), Action: "LIKE"},
+		{SwiperID: aliceID.String(), SwipedID: carolID.String(), Action: "LIKE"},
+		{SwiperID: bobID.String(), SwipedID: aliceID.String(), Action: "LIKE"},
+	} {
+		if rr := doRequest(t, mux, "POST", "/swipe", swipe); rr.Code != http.StatusCreated {
+			t.Fatalf("swipe failed: status %d", rr.Code)
+		}
+	}
+
+	rr := doRequest(t, mux, "GET", "/users/"+aliceID.String()+"/throughput", nil)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want %d", rr.Code, http.StatusOK)
+	}
+
+	data, ok := parseResponse(t, rr).Data.(map[string]interface{})
+	if !ok {
+		t.Fatal("expected data to be an object")
+	}
+	daily, _ := data["daily"].([]interface{})
+	if len(daily) != 7 {
+		t.Fatalf("expected 7 daily buckets, got %d", len(daily))
+	}
+	// Summed rather than read from the last bucket, so a run that
+	// straddles midnight UTC still passes.
+	var total float64
+	for _, day := range daily {
+		total += day.(map[string]interface{})["count"].(float64)
+	}
+	if total != 2 {
+		t.Errorf("expected 2 swipes across the week, got %v", total)
+	}
+	if data["likes_sent"] != float64(2) || data["matches"] != float64(1) || data["match_rate"] != 0.5 {
+		t.Errorf("unexpected totals: %v", data)
+	}
+}
+
+func TestGetUserThroughput_NotFound(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	for _, id := range []string{uuid.New().String(), "not-a-uuid"} {
+		rr := doRequest(t, mux, "GET", "/users/"+id+"/throughput", nil)
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("%s: status got %d, want %d", id, rr.Code, http.StatusNotFound)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+// This file contains HTTP handlers for blocking:
+//   - POST /block — Block another user
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/dlfelps/tinder-go-claude/internal/models"
+	"github.com/dlfelps/tinder-go-claude/internal/store"
+)
+
+// BlockHandler handles requests to block users.
+type BlockHandler struct {
+	store *store.InMemoryStore
+}
+
+// NewBlockHandler creates a new BlockHandler with the given store.
+func NewBlockHandler(s *store.InMemoryStore) *BlockHandler {
+	return &BlockHandler{store: s}
+}
+
+// CreateBlock handles POST /block — blocks blocked_id on behalf of
+// blocker_id. From then on neither user appears in the other's feed or
+// zone-mates list, swipes between them are refused with 403, and any match
+// between them is removed.
+//
+// Blocking someone twice is harmless: the first block is returned with 200
+// instead of 201.
+func (h *BlockHandler) CreateBlock(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateBlockRequest
+	if !decodeJSONBody(w, r, &req) {
+		return
+	}
+
+	blockerID, blockedID, errs := req.Validate()
+	if len(errs) > 0 {
+		writeError(w, http.StatusUnprocessableEntity, errs...)
+		return
+	}
+
+	if _, exists := h.store.GetUser(blockerID); !exists {
+		writeError(w, http.StatusNotFound, "blocker user not found")
+		return
+	}
+	if _, exists := h.store.GetUser(blockedID); !exists {
+		writeError(w, http.StatusNotFound, "blocked user not found")
+		return
+	}
+
+	block, created := h.store.AddBlock(models.Block{
+		BlockerID: blockerID,
+		BlockedID: blockedID,
+		Timestamp: time.Now().UTC(),
+	})
+	if !created {
+		writeSuccess(w, http.StatusOK, block, nil)
+		return
+	}
+	writeSuccess(w, http.StatusCreated, block, nil)
+}
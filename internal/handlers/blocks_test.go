@@ -0,0 +1,141 @@
+// This file contains tests for blocking: a block hides both users from each
+// other, stops swipes between them, and ends any match.
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/dlfelps/tinder-go-claude/internal/models"
+	"github.com/google/uuid"
+)
+
+// blockUser posts a block through the API and returns the response status.
+func blockUser(t *testing.T, mux http.Handler, blocker, blocked uuid.UUID) int {
+	t.Helper()
+	rr := doRequest(t, mux, "POST", "/block", models.CreateBlockRequest{
+		BlockerID: blocker.String(), BlockedID: blocked.String(),
+	})
+	return rr.Code
+}
+
+func TestCreateBlock_MutualInvisibility(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+	bobID, _ := createTestUser(t, mux, "Bob", "male", "zone-a", 30)
+	createTestUser(t, mux, "Carol", "female", "zone-a", 27)
+
+	if code := blockUser(t, mux, aliceID, bobID); code != http.StatusCreated {
+		t.Fatalf("status: got %d, want %d", code, http.StatusCreated)
+	}
+
+	// Neither the blocker nor the blocked user sees the other, in the feed
+	// or among zone mates; everyone else is unaffected.
+	tests := []struct {
+		name   string
+		userID uuid.UUID
+		want   []string
+	}{
+		{"blocker", aliceID, []string{"Carol"}},
+		{"blocked", bobID, []string{"Carol"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name+" feed", func(t *testing.T) {
+			rr := doRequest(t, mux, "GET", fmt.Sprintf("/feed?user_id=%s", tt.userID), nil)
+			if got := zoneMateNames(t, parseResponse(t, rr)); !slices.Equal(got, tt.want) {
+				t.Errorf("feed: got %v, want %v", got, tt.want)
+			}
+		})
+		t.Run(tt.name+" zone mates", func(t *testing.T) {
+			rr := doRequest(t, mux, "GET", fmt.Sprintf("/users/%s/zone-mates", tt.userID), nil)
+			if got := zoneMateNames(t, parseResponse(t, rr)); !slices.Equal(got, tt.want) {
+				t.Errorf("zone mates: got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateBlock_SwipesRejected(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+	bobID, _ := createTestUser(t, mux, "Bob", "male", "zone-a", 30)
+	blockUser(t, mux, aliceID, bobID)
+
+	for _, pair := range [][2]uuid.UUID{{aliceID, bobID}, {bobID, aliceID}} {
+		rr := doRequest(t, mux, "POST", "/swipe", models.CreateSwipeRequest{
+			SwiperID: pair[0].String(), SwipedID: pair[1].String(), Action: "LIKE",
+		})
+		if rr.Code != http.StatusForbidden {
+			t.Errorf("swipe %s -> %s: got %d, want %d", pair[0], pair[1], rr.Code, http.StatusForbidden)
+		}
+	}
+}
+
+func TestCreateBlock_RemovesMatch(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+	bobID, _ := createTestUser(t, mux, "Bob", "male", "zone-a", 30)
+	matchID := createTestMatch(t, mux, aliceID, bobID)
+
+	// Bob blocks Alice; the match is gone for both of them.
+	blockUser(t, mux, bobID, aliceID)
+
+	if rr := doRequest(t, mux, "GET", "/matches/"+matchID, nil); rr.Code != http.StatusNotFound {
+		t.Errorf("GET match after block: got %d, want %d", rr.Code, http.StatusNotFound)
+	}
+	for _, id := range []uuid.UUID{aliceID, bobID} {
+		rr := doRequest(t, mux, "GET", fmt.Sprintf("/matches?user_id=%s", id), nil)
+		if data := parseResponse(t, rr).Data.([]interface{}); len(data) != 0 {
+			t.Errorf("expected no matches for %s after block, got %d", id, len(data))
+		}
+	}
+}
+
+func TestCreateBlock_RepeatReturnsOriginal(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+	bobID, _ := createTestUser(t, mux, "Bob", "male", "zone-a", 30)
+
+	first := doRequest(t, mux, "POST", "/block", models.CreateBlockRequest{BlockerID: aliceID.String(), BlockedID: bobID.String()})
+	second := doRequest(t, mux, "POST", "/block", models.CreateBlockRequest{BlockerID: aliceID.String(), BlockedID: bobID.String()})
+	if first.Code != http.StatusCreated || second.Code != http.StatusOK {
+		t.Fatalf("statuses: got %d then %d, want %d then %d", first.Code, second.Code, http.StatusCreated, http.StatusOK)
+	}
+
+	firstAt := parseResponse(t, first).Data.(map[string]interface{})["timestamp"]
+	secondAt := parseResponse(t, second).Data.(map[string]interface{})["timestamp"]
+	if firstAt != secondAt {
+		t.Errorf("expected the original block back, got timestamps %v and %v", firstAt, secondAt)
+	}
+}
+
+func TestCreateBlock_Errors(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+
+	tests := []struct {
+		name string
+		body models.CreateBlockRequest
+		want int
+	}{
+		{"invalid blocker", models.CreateBlockRequest{BlockerID: "x", BlockedID: aliceID.String()}, http.StatusUnprocessableEntity},
+		{"invalid blocked", models.CreateBlockRequest{BlockerID: aliceID.String(), BlockedID: "x"}, http.StatusUnprocessableEntity},
+		{"self", models.CreateBlockRequest{BlockerID: aliceID.String(), BlockedID: aliceID.String()}, http.StatusUnprocessableEntity},
+		{"unknown blocker", models.CreateBlockRequest{BlockerID: uuid.New().String(), BlockedID: aliceID.String()}, http.StatusNotFound},
+		{"unknown blocked", models.CreateBlockRequest{BlockerID: aliceID.String(), BlockedID: uuid.New().String()}, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rr := doRequest(t, mux, "POST", "/block", tt.body); rr.Code != tt.want {
+				t.Errorf("status: got %d, want %d", rr.Code, tt.want)
+			}
+		})
+	}
+}
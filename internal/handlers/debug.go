@@ -0,0 +1,66 @@
+// This file contains development-only HTTP handlers. Every route in this
+// file must be registered behind RequireDebug:
+//   - POST /debug/seed — Generate demo users and swipes
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/dlfelps/tinder-go-claude/internal/services"
+)
+
+// Defaults for POST /debug/seed when a query parameter is omitted.
+const (
+	defaultSeedUsers  = 20
+	defaultSeedZones  = 3
+	defaultSeedSwipes = 0
+)
+
+// DebugHandler groups handlers that help during development, such as
+// seeding demo data. They are off in production; see RequireDebug.
+type DebugHandler struct {
+	simulationService *services.SimulationService
+}
+
+// NewDebugHandler creates a new DebugHandler with the given simulation
+// service, which generates the demo data.
+func NewDebugHandler(sim *services.SimulationService) *DebugHandler {
+	return &DebugHandler{simulationService: sim}
+}
+
+// Seed handles POST /debug/seed?users=<n>&zones=<m>&swipes=<k> — creates n
+// demo users (default 20) across m zones (default 3), then k random swipes
+// between them (default 0), and returns the new user IDs and zones. It
+// saves a new developer from creating users one POST at a time.
+func (h *DebugHandler) Seed(w http.ResponseWriter, r *http.Request) {
+	users, err := queryInt(r, "users", defaultSeedUsers)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("users must be an integer between 1 and %d", services.MaxSeedUsers))
+		return
+	}
+	zones, err := queryInt(r, "zones", defaultSeedZones)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("zones must be an integer between 1 and %d", services.MaxSeedZones))
+		return
+	}
+	swipes, err := queryInt(r, "swipes", defaultSeedSwipes)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("swipes must be an integer between 0 and %d", services.MaxSeedSwipes))
+		return
+	}
+
+	result, err := h.simulationService.Seed(users, zones, swipes)
+	if err != nil {
+		var validationErr *services.ValidationError
+		if errors.As(err, &validationErr) {
+			writeError(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		writeInternalError(w, err)
+		return
+	}
+
+	writeSuccess(w, http.StatusCreated, result, map[string]any{"count": len(result.UserIDs)})
+}
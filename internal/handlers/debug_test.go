@@ -0,0 +1,81 @@
+// This file contains tests for the debug endpoints and the RequireDebug guard.
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/dlfelps/tinder-go-claude/internal/services"
+	"github.com/dlfelps/tinder-go-claude/internal/store"
+)
+
+func TestSeed_CreatesUsersAndSwipes(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	rr := doRequest(t, mux, "POST", "/debug/seed?users=10&zones=2&swipes=5", nil)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("status: got %d, want %d: %s", rr.Code, http.StatusCreated, rr.Body.String())
+	}
+
+	resp := parseResponse(t, rr)
+	data := resp.Data.(map[string]interface{})
+	if got := len(data["user_ids"].([]interface{})); got != 10 {
+		t.Errorf("user_ids: got %d, want 10", got)
+	}
+	if got := len(data["zones"].([]interface{})); got != 2 {
+		t.Errorf("zones: got %d, want 2", got)
+	}
+	if got := data["swipes"].(float64); got != 5 {
+		t.Errorf("swipes: got %v, want 5", got)
+	}
+
+	stats := store.GetStore().Stats()
+	if stats.TotalUsers != 10 {
+		t.Errorf("users in store: got %d, want 10", stats.TotalUsers)
+	}
+	if stats.TotalSwipes != 5 {
+		t.Errorf("swipes in store: got %d, want 5", stats.TotalSwipes)
+	}
+}
+
+func TestSeed_InvalidInput(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{"non-integer users", "users=many"},
+		{"zero zones", "zones=0"},
+		{"too many swipes", "swipes=100000"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rr := doRequest(t, mux, "POST", "/debug/seed?"+tc.query, nil)
+			if rr.Code != http.StatusUnprocessableEntity {
+				t.Errorf("status: got %d, want %d", rr.Code, http.StatusUnprocessableEntity)
+			}
+		})
+	}
+	if got := store.GetStore().Stats().TotalUsers; got != 0 {
+		t.Errorf("rejected seeds should create no users, store has %d", got)
+	}
+}
+
+func TestRequireDebug_DisabledHidesEndpoint(t *testing.T) {
+	s := store.GetStore()
+	s.Reset()
+	debugHandler := NewDebugHandler(services.NewSimulationService(s, services.NewSwipeService(s)))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /debug/seed", RequireDebug(false, debugHandler.Seed))
+
+	rr := doRequest(t, mux, "POST", "/debug/seed?users=5", nil)
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("status: got %d, want %d", rr.Code, http.StatusNotFound)
+	}
+	if got := s.Stats().TotalUsers; got != 0 {
+		t.Errorf("disabled endpoint should create no users, store has %d", got)
+	}
+}
@@ -1,10 +1,18 @@
-// This file contains the HTTP handler for the discovery feed endpoint:
+// This file contains the HTTP handlers for the discovery feed endpoints:
 //   - GET /feed?user_id=<uuid> — Get a filtered discovery feed for a user
+//     (mode=network for second-degree connections)
+//   - GET /feed/count?user_id=<uuid> — Count the feed without fetching it
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 
+	"github.com/dlfelps/tinder-go-claude/internal/models"
 	"github.com/dlfelps/tinder-go-claude/internal/services"
 	"github.com/google/uuid"
 )
@@ -19,42 +27,288 @@ func NewFeedHandler(fs *services.FeedService) *FeedHandler {
 	return &FeedHandler{feedService: fs}
 }
 
-// GetFeed handles GET /feed?user_id=<uuid> — returns a personalized
-// discovery feed for the given user.
+// GetFeed handles GET /feed?user_id=<uuid>&limit=<n>&offset=<n> — returns
+// one page of a personalized discovery feed for the given user (20 by
+// default). Adding a cursor parameter switches to cursor-based paging (see
+// getFeedPage), and mode=network switches to the second-degree feed (see
+// getNetworkFeed).
 //
 // Query parameters in Go are accessed through r.URL.Query(), which returns
 // a url.Values (essentially a map[string][]string). This is different from
 // FastAPI where query parameters are declared as function arguments.
 func (h *FeedHandler) GetFeed(w http.ResponseWriter, r *http.Request) {
-	// Step 1: Extract the user_id query parameter.
-	// r.URL.Query().Get() returns an empty string if the parameter is missing.
-	userIDStr := r.URL.Query().Get("user_id")
-	if userIDStr == "" {
-		writeError(w, http.StatusUnprocessableEntity, "user_id query parameter is required")
+	// Steps 1 and 2: Extract the user_id query parameter and parse it as a
+	// UUID. queryUUID's error message says which of the two went wrong.
+	userID, err := queryUUID(r, "user_id")
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
 		return
 	}
 
-	// Step 2: Parse the user_id as a UUID.
-	userID, err := uuid.Parse(userIDStr)
-	if err != nil {
-		writeError(w, http.StatusUnprocessableEntity, "user_id must be a valid UUID")
+	// The second-degree feed is a different pool of candidates, so it
+	// skips the feed settings below and only shares the paging parameters.
+	switch r.URL.Query().Get("mode") {
+	case "":
+	case feedModeNetwork:
+		h.getNetworkFeed(w, r, userID)
+		return
+	default:
+		writeError(w, http.StatusUnprocessableEntity, "mode must be "+feedModeNetwork)
+		return
+	}
+
+	// Step 3: Parse the optional feed settings.
+	opts, errs := parseFeedOptions(r.URL.Query())
+	if len(errs) > 0 {
+		writeError(w, http.StatusUnprocessableEntity, errs...)
 		return
 	}
 
-	// Step 3: Call the feed service to generate the filtered feed.
+	// A "cursor" parameter (empty for the first page) switches to
+	// cursor-based paging, which has its own response shape. A cursor
+	// already says where the page starts, so an offset can't be combined
+	// with it.
+	if r.URL.Query().Has("cursor") {
+		if r.URL.Query().Has("offset") {
+			writeError(w, http.StatusUnprocessableEntity, "offset cannot be combined with cursor")
+			return
+		}
+		h.getFeedPage(w, r, userID, opts)
+		return
+	}
+
+	// Step 4: Parse the page window. Offset paging suits clients that jump
+	// to a page number; it can skip or repeat a candidate if the feed
+	// changes between requests, which cursor paging avoids.
+	limit, err := queryInt(r, "limit", defaultFeedPageSize)
+	if err != nil || limit < 1 || limit > maxFeedPageSize {
+		writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("limit must be an integer between 1 and %d", maxFeedPageSize))
+		return
+	}
+	offset, err := queryInt(r, "offset", 0)
+	if err != nil || offset < 0 {
+		writeError(w, http.StatusUnprocessableEntity, "offset must be a non-negative integer")
+		return
+	}
+	opts.Offset = offset
+	opts.Limit = limit
+
+	// Step 5: Call the feed service to generate the filtered feed.
 	// The service handles all the business logic (zone filtering, self-exclusion,
 	// seen-state filtering). The handler just coordinates the HTTP layer.
-	feed, err := h.feedService.GetFeed(userID)
+	feed, total, err := h.feedService.GetFeed(userID, opts)
 	if err != nil {
 		// If the service returns an error, it means the user wasn't found.
 		writeError(w, http.StatusNotFound, err.Error())
 		return
 	}
 
-	// Step 4: Return the feed with a count in the metadata.
-	// The "count" meta field tells the client how many profiles are in the feed
-	// without requiring them to check the array length.
-	writeSuccess(w, http.StatusOK, feed, map[string]any{
-		"count": len(feed),
-	})
+	// Step 6: Return the page with paging details in the metadata.
+	// "total" is how many profiles passed the filters, across all pages.
+	writePaginated(w, feed, total, limit, offset)
+}
+
+// CountFeed handles GET /feed/count?user_id=<uuid> — returns how many
+// candidates the feed holds, for a "N people nearby" badge, without sending
+// any profiles. It accepts the same filter parameters as GetFeed (paging
+// parameters are ignored). With explain=true, meta.stages shows how many
+// users are left after each stage of the pipeline.
+func (h *FeedHandler) CountFeed(w http.ResponseWriter, r *http.Request) {
+	userID, err := queryUUID(r, "user_id")
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	opts, errs := parseFeedOptions(r.URL.Query())
+	var explain bool
+	if raw := r.URL.Query().Get("explain"); raw != "" {
+		if explain, err = strconv.ParseBool(raw); err != nil {
+			errs = append(errs, "explain must be true or false")
+		}
+	}
+	if len(errs) > 0 {
+		writeError(w, http.StatusUnprocessableEntity, errs...)
+		return
+	}
+
+	count, err := h.feedService.CountFeed(userID, opts)
+	if err != nil {
+		var notFoundErr *services.NotFoundError
+		if errors.As(err, &notFoundErr) {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		writeInternalError(w, err)
+		return
+	}
+
+	var meta map[string]any
+	if explain {
+		meta = map[string]any{"stages": count.Stages}
+	}
+	writeSuccess(w, http.StatusOK, map[string]int{"count": count.Total}, meta)
+}
+
+// Page sizes for both offset and cursor paging.
+const (
+	defaultFeedPageSize = 20
+	maxFeedPageSize     = 100
+)
+
+// getFeedPage serves GET /feed?user_id=<uuid>&cursor=<token>&limit=<n>.
+// Clients start with an empty cursor and pass meta.next_cursor back to get
+// the following page; next_cursor is absent on the last page.
+func (h *FeedHandler) getFeedPage(w http.ResponseWriter, r *http.Request, userID uuid.UUID, opts services.FeedOptions) {
+	limit, err := queryInt(r, "limit", defaultFeedPageSize)
+	if err != nil || limit < 1 || limit > maxFeedPageSize {
+		writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("limit must be an integer between 1 and %d", maxFeedPageSize))
+		return
+	}
+
+	page, err := h.feedService.GetFeedPage(userID, opts, r.URL.Query().Get("cursor"), limit)
+	if err != nil {
+		var notFoundErr *services.NotFoundError
+		var validationErr *services.ValidationError
+		switch {
+		case errors.As(err, &notFoundErr):
+			writeError(w, http.StatusNotFound, err.Error())
+		case errors.As(err, &validationErr):
+			writeError(w, http.StatusUnprocessableEntity, err.Error())
+		default:
+			writeInternalError(w, err)
+		}
+		return
+	}
+
+	meta := map[string]any{
+		"count": len(page.Entries),
+		"limit": limit,
+	}
+	if page.NextCursor != "" {
+		meta["next_cursor"] = page.NextCursor
+	}
+	writeSuccess(w, http.StatusOK, page.Entries, meta)
+}
+
+// feedModeNetwork is the /feed mode parameter value that selects the
+// second-degree feed.
+const feedModeNetwork = "network"
+
+// getNetworkFeed serves GET /feed?user_id=<uuid>&mode=network, the
+// second-degree feed: users matched with the requester's matches, from any
+// zone. It pages with limit and offset like the regular feed.
+func (h *FeedHandler) getNetworkFeed(w http.ResponseWriter, r *http.Request, userID uuid.UUID) {
+	if r.URL.Query().Has("cursor") {
+		writeError(w, http.StatusUnprocessableEntity, "cursor cannot be combined with mode=network")
+		return
+	}
+	limit, err := queryInt(r, "limit", defaultFeedPageSize)
+	if err != nil || limit < 1 || limit > maxFeedPageSize {
+		writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("limit must be an integer between 1 and %d", maxFeedPageSize))
+		return
+	}
+	offset, err := queryInt(r, "offset", 0)
+	if err != nil || offset < 0 {
+		writeError(w, http.StatusUnprocessableEntity, "offset must be a non-negative integer")
+		return
+	}
+
+	feed, err := h.feedService.GetNetworkFeed(userID)
+	if err != nil {
+		var notFoundErr *services.NotFoundError
+		if errors.As(err, &notFoundErr) {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		writeInternalError(w, err)
+		return
+	}
+
+	total := len(feed)
+	page := feed[min(offset, total):min(offset+limit, total)]
+	writePaginated(w, page, total, limit, offset)
+}
+
+// parseFeedOptions reads the optional /feed query parameters into a
+// services.FeedOptions. Like the request Validate methods, it collects every
+// problem instead of stopping at the first one.
+//
+// Supported parameters:
+//   - reasons=true — annotate each entry with why it is shown
+//   - exclude=<uuid>,<uuid> — omit these users from this response only
+//   - include_self=true — return only the requester's own profile (preview)
+//   - online_only=true — keep only candidates who are currently online
+//   - sort=interests|newest|age_asc|age_desc|distance|recent_activity|least_liked —
+//     order the feed (not with cursor paging); the default is by user ID
+//   - freshness_weight=0.3 — boost recently joined users, 0 (off) to 1
+//     (newest first); not with cursor paging
+//   - require_tags=hiking,music — keep only candidates with all these tags
+func parseFeedOptions(query url.Values) (services.FeedOptions, []string) {
+	var opts services.FeedOptions
+	var errs []string
+
+	if raw := query.Get("reasons"); raw != "" {
+		// strconv.ParseBool accepts 1, t, true, 0, f, false (and variants).
+		reasons, err := strconv.ParseBool(raw)
+		if err != nil {
+			errs = append(errs, "reasons must be true or false")
+		}
+		opts.IncludeReasons = reasons
+	}
+
+	if raw := query.Get("include_self"); raw != "" {
+		includeSelf, err := strconv.ParseBool(raw)
+		if err != nil {
+			errs = append(errs, "include_self must be true or false")
+		}
+		opts.IncludeSelf = includeSelf
+	}
+
+	if raw := query.Get("online_only"); raw != "" {
+		onlineOnly, err := strconv.ParseBool(raw)
+		if err != nil {
+			errs = append(errs, "online_only must be true or false")
+		}
+		opts.OnlineOnly = onlineOnly
+	}
+
+	if raw := query.Get("sort"); raw != "" {
+		opts.Sort = services.FeedSort(raw)
+		if !opts.Sort.IsValid() {
+			errs = append(errs, "sort must be one of "+strings.Join(services.FeedSortNames(), ", "))
+		}
+	}
+
+	if raw := query.Get("freshness_weight"); raw != "" {
+		weight, err := strconv.ParseFloat(raw, 64)
+		// Written as !(in range) so that NaN, which fails every
+		// comparison, is rejected too.
+		if err != nil || !(weight >= 0 && weight <= 1) {
+			errs = append(errs, "freshness_weight must be a number between 0 and 1")
+		}
+		opts.FreshnessWeight = weight
+	}
+
+	if raw := query.Get("require_tags"); raw != "" {
+		// Tags are compared in normalized form, so "Hiking" finds "hiking".
+		for _, part := range strings.Split(raw, ",") {
+			if tag := models.NormalizeTag(part); tag != "" {
+				opts.RequireTags = append(opts.RequireTags, tag)
+			}
+		}
+	}
+
+	if raw := query.Get("exclude"); raw != "" {
+		for _, part := range strings.Split(raw, ",") {
+			id, err := uuid.Parse(strings.TrimSpace(part))
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("exclude contains an invalid UUID: %q", part))
+				continue
+			}
+			opts.Exclude = append(opts.Exclude, id)
+		}
+	}
+
+	return opts, errs
 }
@@ -15,10 +15,17 @@ package handlers
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/dlfelps/tinder-go-claude/internal/models"
 	"github.com/dlfelps/tinder-go-claude/internal/services"
@@ -30,6 +37,9 @@ import (
 // Test helpers
 // ---------------------------------------------------------------------------
 
+// testAdminToken is the admin token configured on the test router.
+const testAdminToken = "test-admin-token"
+
 // setupTestRouter creates a fresh router with all endpoints registered and
 // the store reset. This is called before each test to ensure isolation.
 //
@@ -45,27 +55,91 @@ func setupTestRouter(t *testing.T) http.Handler {
 	// Wire up dependencies — same as in main.go.
 	feedService := services.NewFeedService(s)
 	swipeService := services.NewSwipeService(s)
+	matchService := services.NewMatchService(s)
+	simulationService := services.NewSimulationService(s, swipeService)
+	notificationService := services.NewNotificationService(s)
+	analyticsService := services.NewAnalyticsService(s)
 
 	userHandler := NewUserHandler(s)
 	feedHandler := NewFeedHandler(feedService)
-	swipeHandler := NewSwipeHandler(swipeService, s)
+	swipeHandler := NewSwipeHandler(swipeService, matchService, s)
+	matchHandler := NewMatchHandler(s)
+	blockHandler := NewBlockHandler(s)
+	reportHandler := NewReportHandler(s)
+	zoneHandler := NewZoneHandler(s)
+	notificationHandler := NewNotificationHandler(notificationService)
+	analyticsHandler := NewAnalyticsHandler(analyticsService)
+	statsHandler := NewStatsHandler(s)
+	healthHandler := NewHealthHandler(s, time.Now())
+	metrics := NewMetrics()
+	readOnlyGuard := NewReadOnlyGuard(false)
+	adminHandler := NewAdminHandler(simulationService, analyticsService, notificationService, s, readOnlyGuard)
+	debugHandler := NewDebugHandler(simulationService)
 
 	// Create a new mux with all routes registered.
 	mux := http.NewServeMux()
-	mux.HandleFunc("GET /", HealthCheck)
+	mux.HandleFunc("GET /", healthHandler.HealthCheck)
+	mux.HandleFunc("GET /openapi.json", GetOpenAPISpec)
+	mux.HandleFunc("GET /stats", statsHandler.GetStats)
+	mux.HandleFunc("GET /metrics", metrics.GetMetrics)
 	mux.HandleFunc("POST /users/", userHandler.CreateUser)
+	mux.HandleFunc("GET /users", userHandler.ListUsers)
+	mux.HandleFunc("POST /users/batch", userHandler.BatchGetUsers)
 	mux.HandleFunc("GET /users/{id}", userHandler.GetUser)
+	mux.HandleFunc("PUT /users/{id}", userHandler.UpdateUser)
+	mux.HandleFunc("DELETE /users/{id}", userHandler.DeleteUser)
+	mux.HandleFunc("GET /users/{id}/export", userHandler.ExportUser)
+	mux.HandleFunc("GET /users/{id}/throughput", analyticsHandler.GetUserThroughput)
+	mux.HandleFunc("PUT /users/{id}/preferences", userHandler.UpdatePreferences)
+	mux.HandleFunc("GET /users/{id}/zone-mates", zoneHandler.GetZoneMates)
+	mux.HandleFunc("POST /boost", userHandler.Boost)
 	mux.HandleFunc("GET /feed", feedHandler.GetFeed)
+	mux.HandleFunc("GET /feed/count", feedHandler.CountFeed)
 	mux.HandleFunc("POST /swipe", swipeHandler.CreateSwipe)
+	mux.HandleFunc("POST /swipe/undo", swipeHandler.UndoSwipe)
+	mux.HandleFunc("GET /swipes", swipeHandler.GetSwipes)
+	mux.HandleFunc("POST /block", blockHandler.CreateBlock)
+	mux.HandleFunc("POST /reports", reportHandler.CreateReport)
 	mux.HandleFunc("GET /matches", swipeHandler.GetMatches)
-
-	return mux
+	mux.HandleFunc("GET /matches/check", matchHandler.CheckMatch)
+	mux.HandleFunc("GET /matches/trail", matchHandler.GetMatchTrail)
+	mux.HandleFunc("GET /matches/recent", matchHandler.GetRecentMatches)
+	mux.HandleFunc("GET /matches/{id}", matchHandler.GetMatch)
+	mux.HandleFunc("DELETE /matches/{id}", matchHandler.DeleteMatch)
+	mux.HandleFunc("POST /matches/{id}/seen", matchHandler.MarkMatchSeen)
+	mux.HandleFunc("GET /notifications/matches", notificationHandler.GetMatchEvents)
+	mux.HandleFunc("GET /zones/{zone_id}/active", zoneHandler.GetActiveUsers)
+	mux.HandleFunc("POST /admin/simulate", RequireAdmin(testAdminToken, adminHandler.Simulate))
+	mux.HandleFunc("POST /admin/read-only", RequireAdmin(testAdminToken, adminHandler.SetReadOnly))
+	mux.HandleFunc("GET /admin/analytics/swipes", RequireAdmin(testAdminToken, adminHandler.SwipeAnalytics))
+	mux.HandleFunc("GET /admin/digest", RequireAdmin(testAdminToken, adminHandler.Digest))
+	mux.HandleFunc("GET /admin/validate", RequireAdmin(testAdminToken, adminHandler.Validate))
+	mux.HandleFunc("GET /admin/unseen-users", RequireAdmin(testAdminToken, adminHandler.UnseenUsers))
+	mux.HandleFunc("GET /reports", RequireAdmin(testAdminToken, reportHandler.ListReports))
+	mux.HandleFunc("DELETE /swipe", RequireAdmin(testAdminToken, swipeHandler.DeleteSwipe))
+	mux.HandleFunc("POST /debug/seed", RequireDebug(true, debugHandler.Seed))
+
+	return metrics.Middleware(RecoverMiddleware(readOnlyGuard.Middleware(mux, "/admin/read-only", "/users/batch")))
 }
 
 // doRequest is a helper that sends an HTTP request to the test router and
 // returns the response recorder. It handles JSON body encoding for POST requests.
 func doRequest(t *testing.T, mux http.Handler, method, path string, body interface{}) *httptest.ResponseRecorder {
 	t.Helper()
+	return doRequestWithHeaders(t, mux, method, path, body, nil)
+}
+
+// doAdminRequest is like doRequest but authenticates with the test admin token.
+func doAdminRequest(t *testing.T, mux http.Handler, method, path string, body interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	return doRequestWithHeaders(t, mux, method, path, body, map[string]string{
+		AdminTokenHeader: testAdminToken,
+	})
+}
+
+// doRequestWithHeaders is like doRequest but sets extra request headers.
+func doRequestWithHeaders(t *testing.T, mux http.Handler, method, path string, body interface{}, headers map[string]string) *httptest.ResponseRecorder {
+	t.Helper()
 
 	var reqBody *bytes.Buffer
 	if body != nil {
@@ -85,6 +159,9 @@ func doRequest(t *testing.T, mux http.Handler, method, path string, body interfa
 	// actually make a network call.
 	req := httptest.NewRequest(method, path, reqBody)
 	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
 
 	// httptest.NewRecorder captures the response written by the handler.
 	// It implements http.ResponseWriter so the handler writes to it normally.
@@ -157,6 +234,89 @@ func TestHealthCheck(t *testing.T) {
 	if data["status"] != "healthy" {
 		t.Errorf("status: got %v, want healthy", data["status"])
 	}
+	if data["store"] != "ready" {
+		t.Errorf("store: got %v, want ready", data["store"])
+	}
+	for _, field := range []string{"uptime", "users", "swipes", "matches"} {
+		if _, ok := data[field]; !ok {
+			t.Errorf("expected field %q in the response", field)
+		}
+	}
+}
+
+func TestHealthCheck_ReportsUptimeAndCounts(t *testing.T) {
+	s := store.GetStore()
+	s.Reset()
+	handler := NewHealthHandler(s, time.Now().Add(-90*time.Minute))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /", handler.HealthCheck)
+
+	alice, bob := uuid.New(), uuid.New()
+	for _, id := range []uuid.UUID{alice, bob} {
+		s.AddUser(models.User{ID: id, Name: "user", Age: 30, Gender: "other", ZoneID: "zone-a"})
+	}
+	now := time.Now().UTC()
+	s.AddSwipe(models.Swipe{SwiperID: alice, SwipedID: bob, Action: models.SwipeActionLike, Timestamp: now})
+	s.AddSwipe(models.Swipe{SwiperID: bob, SwipedID: alice, Action: models.SwipeActionLike, Timestamp: now})
+	s.AddMatch(models.Match{User1ID: alice, User2ID: bob, Timestamp: now})
+
+	rr := doRequest(t, mux, "GET", "/", nil)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want %d", rr.Code, http.StatusOK)
+	}
+	data, ok := parseResponse(t, rr).Data.(map[string]interface{})
+	if !ok {
+		t.Fatal("expected data to be an object")
+	}
+	if uptime, _ := data["uptime"].(string); !strings.HasPrefix(uptime, "1h30m") {
+		t.Errorf("uptime: got %v, want about 1h30m", data["uptime"])
+	}
+	// JSON numbers decode as float64.
+	for field, want := range map[string]float64{"users": 2, "swipes": 2, "matches": 1} {
+		if data[field] != want {
+			t.Errorf("%s: got %v, want %v", field, data[field], want)
+		}
+	}
+}
+
+func TestHealthCheck_StoreUnavailable(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /", NewHealthHandler(nil, time.Now()).HealthCheck)
+
+	rr := doRequest(t, mux, "GET", "/", nil)
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status: got %d, want %d", rr.Code, http.StatusServiceUnavailable)
+	}
+	data, _ := parseResponse(t, rr).Data.(map[string]interface{})
+	if data["status"] != "unhealthy" || data["store"] != "unavailable" {
+		t.Errorf("expected an unhealthy, unavailable store, got %v", data)
+	}
+}
+
+func TestGetOpenAPISpec(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	rr := doRequest(t, mux, "GET", "/openapi.json", nil)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want %d", rr.Code, http.StatusOK)
+	}
+
+	// The document is served bare, not inside the envelope.
+	var doc map[string]interface{}
+	if err := json.Unmarshal(rr.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if _, wrapped := doc["data"]; wrapped {
+		t.Error("expected the bare document, got an envelope")
+	}
+	if version, _ := doc["openapi"].(string); !strings.HasPrefix(version, "3.") {
+		t.Errorf("openapi: got %v, want 3.x", doc["openapi"])
+	}
+	paths, _ := doc["paths"].(map[string]interface{})
+	if _, ok := paths["/users/{id}"]; !ok {
+		t.Error("expected /users/{id} in paths")
+	}
 }
 
 // ---------------------------------------------------------------------------
@@ -200,12 +360,36 @@ func TestCreateUser_Success(t *testing.T) {
 	}
 }
 
+func TestCreateUser_IDsFromGenerator(t *testing.T) {
+	s := store.GetStore()
+	s.Reset()
+	userHandler := NewUserHandler(s)
+	userHandler.SetIDGenerator(&services.SequentialIDs{})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /users/", userHandler.CreateUser)
+
+	for _, want := range []string{
+		"00000000-0000-0000-0000-000000000001",
+		"00000000-0000-0000-0000-000000000002",
+	} {
+		id, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+		if id.String() != want {
+			t.Errorf("got ID %s, want %s", id, want)
+		}
+		if _, exists := s.GetUser(id); !exists {
+			t.Errorf("user %s not stored under its generated ID", id)
+		}
+	}
+}
+
 func TestCreateUser_ValidationErrors(t *testing.T) {
 	mux := setupTestRouter(t)
 
 	// Table-driven test: each case tests a different validation failure.
 	// This is a very common Go testing pattern — define a slice of test
 	// cases and loop over them. Each case runs as a subtest.
+	lat, badLon := 51.5, 200.0
 	tests := []struct {
 		name string
 		body models.CreateUserRequest
@@ -218,6 +402,14 @@ func TestCreateUser_ValidationErrors(t *testing.T) {
 			name: "invalid age",
 			body: models.CreateUserRequest{Name: "Bob", Age: 0, Gender: "male", ZoneID: "zone-a"},
 		},
+		{
+			name: "under 18",
+			body: models.CreateUserRequest{Name: "Bob", Age: 17, Gender: "male", ZoneID: "zone-a"},
+		},
+		{
+			name: "over 120",
+			body: models.CreateUserRequest{Name: "Bob", Age: 121, Gender: "male", ZoneID: "zone-a"},
+		},
 		{
 			name: "missing gender",
 			body: models.CreateUserRequest{Name: "Bob", Age: 25, ZoneID: "zone-a"},
@@ -226,6 +418,22 @@ func TestCreateUser_ValidationErrors(t *testing.T) {
 			name: "missing zone_id",
 			body: models.CreateUserRequest{Name: "Bob", Age: 25, Gender: "male"},
 		},
+		{
+			name: "latitude without longitude",
+			body: models.CreateUserRequest{Name: "Bob", Age: 25, Gender: "male", ZoneID: "zone-a", Latitude: &lat},
+		},
+		{
+			name: "longitude out of range",
+			body: models.CreateUserRequest{Name: "Bob", Age: 25, Gender: "male", ZoneID: "zone-a", Latitude: &lat, Longitude: &badLon},
+		},
+		{
+			name: "negative max_distance_km",
+			body: models.CreateUserRequest{Name: "Bob", Age: 25, Gender: "male", ZoneID: "zone-a", MaxDistanceKm: -1},
+		},
+		{
+			name: "bio too long",
+			body: models.CreateUserRequest{Name: "Bob", Age: 25, Gender: "male", ZoneID: "zone-a", Bio: strings.Repeat("a", models.MaxBioLength+1)},
+		},
 	}
 
 	for _, tc := range tests {
@@ -240,6 +448,39 @@ func TestCreateUser_ValidationErrors(t *testing.T) {
 	}
 }
 
+func TestCreateUser_AgeBoundaries(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	tests := []struct {
+		age        int
+		wantStatus int
+		wantError  string
+	}{
+		{17, http.StatusUnprocessableEntity, "age must be at least 18"},
+		{18, http.StatusCreated, ""},
+		{120, http.StatusCreated, ""},
+		{121, http.StatusUnprocessableEntity, "age must be 120 or less"},
+	}
+
+	for _, tc := range tests {
+		t.Run(fmt.Sprint(tc.age), func(t *testing.T) {
+			rr := doRequest(t, mux, "POST", "/users/", models.CreateUserRequest{
+				Name: "Alice", Age: tc.age, Gender: "female", ZoneID: "zone-a",
+			})
+			if rr.Code != tc.wantStatus {
+				t.Fatalf("status: got %d, want %d", rr.Code, tc.wantStatus)
+			}
+			if tc.wantError == "" {
+				return
+			}
+			resp := parseResponse(t, rr)
+			if len(resp.Errors) != 1 || resp.Errors[0].Message != tc.wantError {
+				t.Errorf("errors: got %v, want %q", resp.Errors, tc.wantError)
+			}
+		})
+	}
+}
+
 func TestCreateUser_InvalidJSON(t *testing.T) {
 	mux := setupTestRouter(t)
 
@@ -297,104 +538,1313 @@ func TestGetUser_InvalidUUID(t *testing.T) {
 	}
 }
 
-// ---------------------------------------------------------------------------
-// Feed endpoint tests
-// ---------------------------------------------------------------------------
-
-func TestGetFeed_Success(t *testing.T) {
+func TestGetUser_ETag(t *testing.T) {
 	mux := setupTestRouter(t)
 
-	// Create users: Alice and Bob in zone-a, Charlie in zone-b.
 	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
-	createTestUser(t, mux, "Bob", "male", "zone-a", 30)
-	createTestUser(t, mux, "Charlie", "male", "zone-b", 25)
+	path := fmt.Sprintf("/users/%s", aliceID)
 
-	// Get Alice's feed.
-	rr := doRequest(t, mux, "GET", fmt.Sprintf("/feed?user_id=%s", aliceID), nil)
+	rr := doRequest(t, mux, "GET", path, nil)
+	etag := rr.Header().Get("ETag")
+	if rr.Code != http.StatusOK || etag == "" {
+		t.Fatalf("expected 200 with an ETag, got %d and %q", rr.Code, etag)
+	}
+
+	// Replaying the request with the tag gets 304 and no body.
+	rr = doRequestWithHeaders(t, mux, "GET", path, nil, map[string]string{"If-None-Match": etag})
+	if rr.Code != http.StatusNotModified {
+		t.Fatalf("status: got %d, want %d", rr.Code, http.StatusNotModified)
+	}
+	if rr.Body.Len() != 0 {
+		t.Errorf("expected an empty body, got %q", rr.Body.String())
+	}
+	if got := rr.Header().Get("ETag"); got != etag {
+		t.Errorf("ETag on 304: got %q, want %q", got, etag)
+	}
 
+	// After an update the old tag no longer matches.
+	doRequest(t, mux, "PUT", path, models.CreateUserRequest{Name: "Alicia", Age: 28, Gender: "female", ZoneID: "zone-a"})
+	rr = doRequestWithHeaders(t, mux, "GET", path, nil, map[string]string{"If-None-Match": etag})
 	if rr.Code != http.StatusOK {
-		t.Errorf("status: got %d, want %d", rr.Code, http.StatusOK)
+		t.Fatalf("status after update: got %d, want %d", rr.Code, http.StatusOK)
+	}
+	if got := rr.Header().Get("ETag"); got == "" || got == etag {
+		t.Errorf("expected a new ETag after the update, got %q", got)
 	}
+}
 
-	resp := parseResponse(t, rr)
+func TestCreateUser_InterestedIn(t *testing.T) {
+	mux := setupTestRouter(t)
 
-	// Data should be an array with 1 user (Bob, same zone).
-	data, ok := resp.Data.([]interface{})
-	if !ok {
-		t.Fatal("expected data to be an array")
+	// Omitted: defaults to everyone.
+	_, data := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+	if data["interested_in"] != models.InterestedInEveryone {
+		t.Errorf("expected default interested_in=%q, got %v", models.InterestedInEveryone, data["interested_in"])
 	}
-	if len(data) != 1 {
-		t.Errorf("expected 1 user in feed, got %d", len(data))
+
+	rr := doRequest(t, mux, "POST", "/users/", models.CreateUserRequest{
+		Name: "Bob", Age: 30, Gender: "male", ZoneID: "zone-a", InterestedIn: "female",
+	})
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("status: got %d, want %d", rr.Code, http.StatusCreated)
+	}
+	if got := parseResponse(t, rr).Data.(map[string]interface{})["interested_in"]; got != "female" {
+		t.Errorf("expected interested_in=female, got %v", got)
 	}
 
-	// Meta should include the count.
-	if count, ok := resp.Meta["count"].(float64); !ok || int(count) != 1 {
-		t.Errorf("expected meta.count=1, got %v", resp.Meta["count"])
+	rr = doRequest(t, mux, "POST", "/users/", models.CreateUserRequest{
+		Name: "Carl", Age: 30, Gender: "male", ZoneID: "zone-a", InterestedIn: "   ",
+	})
+	if rr.Code != http.StatusUnprocessableEntity {
+		t.Errorf("blank interested_in: got %d, want %d", rr.Code, http.StatusUnprocessableEntity)
 	}
 }
 
-func TestGetFeed_UserNotFound(t *testing.T) {
+func TestCreateUser_AgeRangeValidation(t *testing.T) {
 	mux := setupTestRouter(t)
 
-	rr := doRequest(t, mux, "GET", fmt.Sprintf("/feed?user_id=%s", uuid.New()), nil)
+	tests := []struct {
+		name       string
+		min, max   int
+		wantStatus int
+	}{
+		{"valid range", 30, 40, http.StatusCreated},
+		{"equal bounds", 30, 30, http.StatusCreated},
+		{"omitted", 0, 0, http.StatusCreated},
+		{"min only", 30, 0, http.StatusCreated},
+		{"inverted", 40, 30, http.StatusUnprocessableEntity},
+		{"negative min", -1, 40, http.StatusUnprocessableEntity},
+		{"negative max", 20, -5, http.StatusUnprocessableEntity},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rr := doRequest(t, mux, "POST", "/users/", models.CreateUserRequest{
+				Name: "Alice", Age: 25, Gender: "female", ZoneID: "zone-a", MinAge: tc.min, MaxAge: tc.max,
+			})
+			if rr.Code != tc.wantStatus {
+				t.Errorf("status: got %d, want %d", rr.Code, tc.wantStatus)
+			}
+		})
+	}
+}
 
-	if rr.Code != http.StatusNotFound {
-		t.Errorf("status: got %d, want %d", rr.Code, http.StatusNotFound)
+func TestCreateUser_TagValidation(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	tooMany := make([]string, models.MaxTags+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("tag%d", i)
+	}
+
+	tests := []struct {
+		name       string
+		tags       []string
+		wantStatus int
+	}{
+		{"omitted", nil, http.StatusCreated},
+		{"valid", []string{"hiking", "music"}, http.StatusCreated},
+		{"at the limit", tooMany[:models.MaxTags], http.StatusCreated},
+		{"too many", tooMany, http.StatusUnprocessableEntity},
+		{"blank tag", []string{"hiking", "  "}, http.StatusUnprocessableEntity},
+		{"too long", []string{strings.Repeat("a", models.MaxTagLength+1)}, http.StatusUnprocessableEntity},
+		{"long after trimming is fine", []string{" " + strings.Repeat("a", models.MaxTagLength) + " "}, http.StatusCreated},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rr := doRequest(t, mux, "POST", "/users/", models.CreateUserRequest{
+				Name: "Alice", Age: 25, Gender: "female", ZoneID: "zone-a", Tags: tc.tags,
+			})
+			if rr.Code != tc.wantStatus {
+				t.Errorf("status: got %d, want %d", rr.Code, tc.wantStatus)
+			}
+		})
 	}
 }
 
-func TestGetFeed_MissingUserID(t *testing.T) {
+func TestCreateUser_NormalizesTags(t *testing.T) {
 	mux := setupTestRouter(t)
 
-	rr := doRequest(t, mux, "GET", "/feed", nil)
+	rr := doRequest(t, mux, "POST", "/users/", models.CreateUserRequest{
+		Name: "Alice", Age: 25, Gender: "female", ZoneID: "zone-a",
+		Tags: []string{" Hiking", "MUSIC", "hiking"},
+	})
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("status: got %d, want %d", rr.Code, http.StatusCreated)
+	}
 
-	if rr.Code != http.StatusUnprocessableEntity {
-		t.Errorf("status: got %d, want %d", rr.Code, http.StatusUnprocessableEntity)
+	data, _ := parseResponse(t, rr).Data.(map[string]interface{})
+	tags, _ := data["tags"].([]interface{})
+	if len(tags) != 2 || tags[0] != "hiking" || tags[1] != "music" {
+		t.Errorf("expected tags [hiking music], got %v", data["tags"])
 	}
 }
 
-func TestGetFeed_InvalidUUID(t *testing.T) {
+func TestCreateUser_BioRoundTrips(t *testing.T) {
 	mux := setupTestRouter(t)
 
-	rr := doRequest(t, mux, "GET", "/feed?user_id=not-a-uuid", nil)
+	// The limit counts characters, so a bio of 500 multi-byte runes fits.
+	bio := strings.Repeat("é", models.MaxBioLength)
+	rr := doRequest(t, mux, "POST", "/users/", models.CreateUserRequest{
+		Name: "Alice", Age: 25, Gender: "female", ZoneID: "zone-a", Bio: bio,
+	})
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("status: got %d, want %d", rr.Code, http.StatusCreated)
+	}
+	data, _ := parseResponse(t, rr).Data.(map[string]interface{})
+	aliceID := data["id"].(string)
 
-	if rr.Code != http.StatusUnprocessableEntity {
-		t.Errorf("status: got %d, want %d", rr.Code, http.StatusUnprocessableEntity)
+	data, _ = parseResponse(t, doRequest(t, mux, "GET", "/users/"+aliceID, nil)).Data.(map[string]interface{})
+	if data["bio"] != bio {
+		t.Errorf("GET /users/{id}: bio was not kept")
+	}
+
+	// The bio also appears on feed entries.
+	bobID, _ := createTestUser(t, mux, "Bob", "male", "zone-a", 30)
+	resp := parseResponse(t, doRequest(t, mux, "GET", "/feed?user_id="+bobID.String(), nil))
+	feed := resp.Data.([]interface{})
+	if len(feed) != 1 || feed[0].(map[string]interface{})["bio"] != bio {
+		t.Errorf("expected Alice's bio in Bob's feed, got %v", feed)
 	}
 }
 
-func TestGetFeed_ExcludesSwipedUsers(t *testing.T) {
+func TestGetFeed_RequireTags(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+	for name, tags := range map[string][]string{
+		"Bob":     {"hiking", "music", "chess"},
+		"Charlie": {"hiking"},
+		"Dana":    nil,
+	} {
+		rr := doRequest(t, mux, "POST", "/users/", models.CreateUserRequest{
+			Name: name, Age: 30, Gender: "male", ZoneID: "zone-a", Tags: tags,
+		})
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("create %s: status %d", name, rr.Code)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		query     string
+		wantNames []string
+	}{
+		{"single tag", "hiking", []string{"Bob", "Charlie"}},
+		{"all tags required", "hiking,music", []string{"Bob"}},
+		{"case and spaces ignored", " Hiking , MUSIC", []string{"Bob"}},
+		{"no one has it", "golf", nil},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			path := "/feed?user_id=" + aliceID.String() + "&require_tags=" + url.QueryEscape(tc.query)
+			rr := doRequest(t, mux, "GET", path, nil)
+			if rr.Code != http.StatusOK {
+				t.Fatalf("status: got %d, want %d", rr.Code, http.StatusOK)
+			}
+
+			feed, _ := parseResponse(t, rr).Data.([]interface{})
+			var names []string
+			for _, entry := range feed {
+				names = append(names, entry.(map[string]interface{})["name"].(string))
+			}
+			sort.Strings(names)
+			if !slices.Equal(names, tc.wantNames) {
+				t.Errorf("expected %v, got %v", tc.wantNames, names)
+			}
+		})
+	}
+}
+
+func TestCreateUser_NormalizesGenderSynonyms(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	tests := []struct {
+		gender, interestedIn         string
+		wantGender, wantInterestedIn string
+	}{
+		{"man", "women", "male", "female"},
+		{"Woman", "MEN", "female", "male"},
+		{" non-binary ", "any", "nonbinary", models.InterestedInEveryone},
+		{"female", "everyone", "female", models.InterestedInEveryone},
+	}
+	for _, tc := range tests {
+		t.Run(tc.gender+"/"+tc.interestedIn, func(t *testing.T) {
+			rr := doRequest(t, mux, "POST", "/users/", models.CreateUserRequest{
+				Name: "User", Age: 30, Gender: tc.gender, ZoneID: "zone-a", InterestedIn: tc.interestedIn,
+			})
+			if rr.Code != http.StatusCreated {
+				t.Fatalf("status: got %d, want %d", rr.Code, http.StatusCreated)
+			}
+			data := parseResponse(t, rr).Data.(map[string]interface{})
+			if data["gender"] != tc.wantGender || data["interested_in"] != tc.wantInterestedIn {
+				t.Errorf("got gender=%v interested_in=%v, want %s/%s",
+					data["gender"], data["interested_in"], tc.wantGender, tc.wantInterestedIn)
+			}
+		})
+	}
+}
+
+func TestCreateUser_GenderIsCaseInsensitive(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	for input, want := range map[string]models.Gender{
+		"MALE":      models.GenderMale,
+		" Female ":  models.GenderFemale,
+		"NonBinary": models.GenderNonbinary,
+		"\tOTHER\n": models.GenderOther,
+	} {
+		rr := doRequest(t, mux, "POST", "/users/", models.CreateUserRequest{
+			Name: "User", Age: 30, Gender: input, ZoneID: "zone-a",
+		})
+		if rr.Code != http.StatusCreated {
+			t.Errorf("%q: status %d, want %d", input, rr.Code, http.StatusCreated)
+			continue
+		}
+		if got := parseResponse(t, rr).Data.(map[string]interface{})["gender"]; got != string(want) {
+			t.Errorf("%q: got %v, want %s", input, got, want)
+		}
+	}
+}
+
+func TestCreateUser_UnknownGenderRejected(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	for _, body := range []models.CreateUserRequest{
+		{Name: "A", Age: 30, Gender: "robot", ZoneID: "zone-a"},
+		{Name: "D", Age: 30, Gender: "m@le", ZoneID: "zone-a"},
+		{Name: "E", Age: 30, Gender: "   ", ZoneID: "zone-a"},
+		{Name: "F", Age: 30, Gender: "123", ZoneID: "zone-a"},
+		{Name: "B", Age: 30, Gender: "male", ZoneID: "zone-a", InterestedIn: "robots"},
+		{Name: "C", Age: 30, Gender: "all", ZoneID: "zone-a"}, // "everyone" isn't a gender.
+	} {
+		rr := doRequest(t, mux, "POST", "/users/", body)
+		if rr.Code != http.StatusUnprocessableEntity {
+			t.Errorf("%s: got %d, want %d", body.Name, rr.Code, http.StatusUnprocessableEntity)
+		}
+	}
+}
+
+func TestFeed_FiltersOnNormalizedGenders(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	rr := doRequest(t, mux, "POST", "/users/", models.CreateUserRequest{
+		Name: "Viewer", Age: 30, Gender: "men", ZoneID: "zone-a", InterestedIn: "women",
+	})
+	viewerID := parseResponse(t, rr).Data.(map[string]interface{})["id"]
+	createTestUser(t, mux, "Ann", "woman", "zone-a", 29)
+	createTestUser(t, mux, "Bea", "female", "zone-a", 31)
+	createTestUser(t, mux, "Cal", "man", "zone-a", 30)
+
+	rr = doRequest(t, mux, "GET", fmt.Sprintf("/feed?user_id=%s", viewerID), nil)
+	data := parseResponse(t, rr).Data.([]interface{})
+	if len(data) != 2 {
+		t.Fatalf("expected Ann and Bea, got %d entries", len(data))
+	}
+	for _, raw := range data {
+		if name := raw.(map[string]interface{})["name"]; name == "Cal" {
+			t.Error("Cal should be filtered out")
+		}
+	}
+}
+
+func TestSetGenderSynonyms_ExtendsDefaults(t *testing.T) {
+	s := store.GetStore()
+	s.Reset()
+	userHandler := NewUserHandler(s)
+	userHandler.SetGenderSynonyms(map[string]string{"guy": "male", "agender": "agender"})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /users/", userHandler.CreateUser)
+
+	for gender, want := range map[string]string{"guy": "male", "men": "male", "agender": "agender"} {
+		rr := doRequest(t, mux, "POST", "/users/", models.CreateUserRequest{
+			Name: "User", Age: 30, Gender: gender, ZoneID: "zone-a",
+		})
+		if rr.Code != http.StatusCreated {
+			t.Errorf("%s: status %d, want %d", gender, rr.Code, http.StatusCreated)
+			continue
+		}
+		if got := parseResponse(t, rr).Data.(map[string]interface{})["gender"]; got != want {
+			t.Errorf("%s: got %v, want %s", gender, got, want)
+		}
+	}
+}
+
+func TestBoost(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	viewerID, _ := createTestUser(t, mux, "Viewer", "male", "zone-a", 30)
+	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+	bobID, _ := createTestUser(t, mux, "Bob", "male", "zone-a", 31)
+	carolID, _ := createTestUser(t, mux, "Carol", "female", "zone-a", 27)
+
+	// Boost whoever is last in the default (ID) order.
+	resp := parseResponse(t, doRequest(t, mux, "GET", "/feed?user_id="+viewerID.String(), nil))
+	feed := resp.Data.([]interface{})
+	lastID := feed[len(feed)-1].(map[string]interface{})["id"].(string)
+
+	before := time.Now()
+	rr := doRequest(t, mux, "POST", "/boost", models.BoostRequest{UserID: lastID, Minutes: 15})
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want %d", rr.Code, http.StatusOK)
+	}
+	data := parseResponse(t, rr).Data.(map[string]interface{})
+	until, err := time.Parse(time.RFC3339Nano, data["boost_until"].(string))
+	if err != nil {
+		t.Fatalf("boost_until: %v", err)
+	}
+	if d := until.Sub(before); d < 15*time.Minute || d > 16*time.Minute {
+		t.Errorf("boost_until: expected about 15 minutes from now, got %v", d)
+	}
+
+	resp = parseResponse(t, doRequest(t, mux, "GET", "/feed?user_id="+viewerID.String(), nil))
+	if first := resp.Data.([]interface{})[0].(map[string]interface{})["id"]; first != lastID {
+		t.Errorf("expected the boosted user first, got %v", first)
+	}
+
+	tests := []struct {
+		name       string
+		body       models.BoostRequest
+		wantStatus int
+	}{
+		{"default length", models.BoostRequest{UserID: aliceID.String()}, http.StatusOK},
+		{"longest", models.BoostRequest{UserID: bobID.String(), Minutes: models.MaxBoostMinutes}, http.StatusOK},
+		{"too long", models.BoostRequest{UserID: carolID.String(), Minutes: models.MaxBoostMinutes + 1}, http.StatusUnprocessableEntity},
+		{"negative", models.BoostRequest{UserID: carolID.String(), Minutes: -5}, http.StatusUnprocessableEntity},
+		{"missing user_id", models.BoostRequest{}, http.StatusUnprocessableEntity},
+		{"invalid user_id", models.BoostRequest{UserID: "nope"}, http.StatusUnprocessableEntity},
+		{"unknown user", models.BoostRequest{UserID: uuid.New().String()}, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rr := doRequest(t, mux, "POST", "/boost", tt.body); rr.Code != tt.wantStatus {
+				t.Errorf("status: got %d, want %d", rr.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestUpdateUser_Success(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+
+	rr := doRequest(t, mux, "PUT", fmt.Sprintf("/users/%s", aliceID), models.CreateUserRequest{
+		Name: "Alicia", Age: 29, Gender: "female", ZoneID: "zone-b",
+	})
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want %d", rr.Code, http.StatusOK)
+	}
+	data := parseResponse(t, rr).Data.(map[string]interface{})
+	if data["name"] != "Alicia" || data["zone_id"] != "zone-b" || data["age"] != float64(29) {
+		t.Errorf("response not updated: %v", data)
+	}
+
+	// The change is persisted, and the ID is unchanged.
+	rr = doRequest(t, mux, "GET", fmt.Sprintf("/users/%s", aliceID), nil)
+	stored := parseResponse(t, rr).Data.(map[string]interface{})
+	if stored["name"] != "Alicia" || stored["id"] != aliceID.String() {
+		t.Errorf("stored user not updated: %v", stored)
+	}
+}
+
+func TestUpdateUser_KeepsBoost(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+	rr := doRequest(t, mux, "POST", "/boost", map[string]any{"user_id": aliceID})
+	if rr.Code != http.StatusOK {
+		t.Fatalf("boost status: got %d, want %d", rr.Code, http.StatusOK)
+	}
+
+	// A profile update doesn't touch BoostUntil, so the boost survives it.
+	rr = doRequest(t, mux, "PUT", fmt.Sprintf("/users/%s", aliceID), models.CreateUserRequest{
+		Name: "Alicia", Age: 29, Gender: "female", ZoneID: "zone-a",
+	})
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want %d", rr.Code, http.StatusOK)
+	}
+	if stored, _ := store.GetStore().GetUser(aliceID); stored.BoostUntil == nil {
+		t.Error("expected the boost to survive a profile update")
+	}
+}
+
+func TestUpdateUser_Errors(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+	valid := models.CreateUserRequest{Name: "Alicia", Age: 29, Gender: "female", ZoneID: "zone-b"}
+
+	tests := []struct {
+		name       string
+		path       string
+		body       interface{}
+		wantStatus int
+	}{
+		{"unknown user", fmt.Sprintf("/users/%s", uuid.New()), valid, http.StatusNotFound},
+		{"malformed id", "/users/not-a-uuid", valid, http.StatusNotFound},
+		{"missing name", fmt.Sprintf("/users/%s", aliceID), models.CreateUserRequest{Age: 29, Gender: "female", ZoneID: "zone-b"}, http.StatusUnprocessableEntity},
+		{"invalid age", fmt.Sprintf("/users/%s", aliceID), models.CreateUserRequest{Name: "A", Age: 0, Gender: "female", ZoneID: "zone-b"}, http.StatusUnprocessableEntity},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rr := doRequest(t, mux, "PUT", tc.path, tc.body)
+			if rr.Code != tc.wantStatus {
+				t.Errorf("status: got %d, want %d", rr.Code, tc.wantStatus)
+			}
+		})
+	}
+}
+
+func TestDeleteUser_CascadesToFeedAndMatches(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+	bobID, _ := createTestUser(t, mux, "Bob", "male", "zone-a", 30)
+	carolID, _ := createTestUser(t, mux, "Carol", "female", "zone-a", 26)
+	createTestMatch(t, mux, aliceID, bobID)
+
+	rr := doRequest(t, mux, "DELETE", fmt.Sprintf("/users/%s", aliceID), nil)
+	if rr.Code != http.StatusNoContent {
+		t.Fatalf("status: got %d, want %d", rr.Code, http.StatusNoContent)
+	}
+
+	rr = doRequest(t, mux, "GET", fmt.Sprintf("/users/%s", aliceID), nil)
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("GET deleted user: got %d, want %d", rr.Code, http.StatusNotFound)
+	}
+
+	// Bob's match with Alice is gone.
+	rr = doRequest(t, mux, "GET", fmt.Sprintf("/matches?user_id=%s", bobID), nil)
+	if data := parseResponse(t, rr).Data.([]interface{}); len(data) != 0 {
+		t.Errorf("expected Bob to have no matches, got %d", len(data))
+	}
+
+	// Alice no longer appears in Carol's feed.
+	rr = doRequest(t, mux, "GET", fmt.Sprintf("/feed?user_id=%s", carolID), nil)
+	for _, raw := range parseResponse(t, rr).Data.([]interface{}) {
+		if raw.(map[string]interface{})["id"] == aliceID.String() {
+			t.Error("deleted user still appears in feed")
+		}
+	}
+}
+
+func TestDeleteUser_NotFound(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	for _, path := range []string{fmt.Sprintf("/users/%s", uuid.New()), "/users/not-a-uuid"} {
+		rr := doRequest(t, mux, "DELETE", path, nil)
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("%s: got %d, want %d", path, rr.Code, http.StatusNotFound)
+		}
+	}
+}
+
+func TestBatchGetUsers(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+	bobID, _ := createTestUser(t, mux, "Bob", "male", "zone-a", 30)
+	unknownID := uuid.New()
+
+	rr := doRequest(t, mux, "POST", "/users/batch", models.BatchUsersRequest{
+		IDs: []string{bobID.String(), unknownID.String(), aliceID.String(), bobID.String()},
+	})
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want %d", rr.Code, http.StatusOK)
+	}
+
+	data := parseResponse(t, rr).Data.(map[string]interface{})
+	users := data["users"].([]interface{})
+	if len(users) != 2 {
+		t.Fatalf("expected 2 users (duplicates collapsed), got %d", len(users))
+	}
+	// Request order is preserved: Bob was asked for first.
+	if name := users[0].(map[string]interface{})["name"]; name != "Bob" {
+		t.Errorf("expected Bob first, got %v", name)
+	}
+	missing := data["missing"].([]interface{})
+	if len(missing) != 1 || missing[0] != unknownID.String() {
+		t.Errorf("expected missing=[%s], got %v", unknownID, missing)
+	}
+}
+
+func TestBatchGetUsers_ValidationErrors(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+
+	tests := []struct {
+		name string
+		ids  []string
+	}{
+		{"malformed id", []string{aliceID.String(), "not-a-uuid"}},
+		{"empty list", []string{}},
+		{"too many ids", batchIDs(models.MaxBatchUsers + 1)},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rr := doRequest(t, mux, "POST", "/users/batch", models.BatchUsersRequest{IDs: tc.ids})
+			if rr.Code != http.StatusUnprocessableEntity {
+				t.Errorf("status: got %d, want %d", rr.Code, http.StatusUnprocessableEntity)
+			}
+		})
+	}
+}
+
+func TestBatchGetUsers_AtLimit(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	rr := doRequest(t, mux, "POST", "/users/batch", models.BatchUsersRequest{IDs: batchIDs(models.MaxBatchUsers)})
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want %d", rr.Code, http.StatusOK)
+	}
+	resp := parseResponse(t, rr).Data.(map[string]interface{})
+	if missing := resp["missing"].([]interface{}); len(missing) != models.MaxBatchUsers {
+		t.Errorf("missing: got %d ids, want %d", len(missing), models.MaxBatchUsers)
+	}
+}
+
+// batchIDs returns n distinct random user IDs as strings.
+func batchIDs(n int) []string {
+	ids := make([]string, n)
+	for i := range ids {
+		ids[i] = uuid.NewString()
+	}
+	return ids
+}
+
+func TestListUsers(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	// No users yet: data is an empty array, not null.
+	rr := doRequest(t, mux, "GET", "/users", nil)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want %d", rr.Code, http.StatusOK)
+	}
+	if data, ok := parseResponse(t, rr).Data.([]interface{}); !ok || len(data) != 0 {
+		t.Fatalf("expected an empty array, got %v", parseResponse(t, rr).Data)
+	}
+
+	for _, name := range []string{"Dave", "Alice", "Carol", "Bob", "Alice"} {
+		createTestUser(t, mux, name, "female", "zone-a", 28)
+	}
+
+	tests := []struct {
+		name      string
+		query     string
+		wantNames []string
+	}{
+		{"default page", "", []string{"Alice", "Alice", "Bob", "Carol", "Dave"}},
+		{"first page", "?limit=2", []string{"Alice", "Alice"}},
+		{"second page", "?limit=2&offset=2", []string{"Bob", "Carol"}},
+		{"past the end", "?offset=10", []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := doRequest(t, mux, "GET", "/users"+tt.query, nil)
+			if rr.Code != http.StatusOK {
+				t.Fatalf("status: got %d, want %d", rr.Code, http.StatusOK)
+			}
+			resp := parseResponse(t, rr)
+			names := []string{}
+			for _, u := range resp.Data.([]interface{}) {
+				names = append(names, u.(map[string]interface{})["name"].(string))
+			}
+			if !slices.Equal(names, tt.wantNames) {
+				t.Errorf("names: got %v, want %v", names, tt.wantNames)
+			}
+			if total := resp.Meta["total"].(float64); total != 5 {
+				t.Errorf("meta.total: got %v, want 5", total)
+			}
+		})
+	}
+
+	// Equal names are ordered by ID, so repeated calls agree.
+	first := parseResponse(t, doRequest(t, mux, "GET", "/users?limit=1", nil)).Data.([]interface{})
+	for i := 0; i < 5; i++ {
+		again := parseResponse(t, doRequest(t, mux, "GET", "/users?limit=1", nil)).Data.([]interface{})
+		if again[0].(map[string]interface{})["id"] != first[0].(map[string]interface{})["id"] {
+			t.Fatal("expected the same user on every call")
+		}
+	}
+
+	for _, query := range []string{"?limit=0", "?limit=101", "?limit=x", "?offset=-1"} {
+		t.Run("invalid "+query, func(t *testing.T) {
+			if rr := doRequest(t, mux, "GET", "/users"+query, nil); rr.Code != http.StatusUnprocessableEntity {
+				t.Errorf("status: got %d, want %d", rr.Code, http.StatusUnprocessableEntity)
+			}
+		})
+	}
+}
+
+func TestCreateUser_NotificationsEnabledByDefault(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	_, userData := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+
+	if userData["notify_on_like"] != true || userData["notify_on_match"] != true {
+		t.Errorf("expected notifications enabled by default, got like=%v match=%v",
+			userData["notify_on_like"], userData["notify_on_match"])
+	}
+}
+
+func TestUpdatePreferences_PartialUpdate(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+
+	rr := doRequest(t, mux, "PUT", fmt.Sprintf("/users/%s/preferences", aliceID), map[string]bool{
+		"notify_on_match": false,
+	})
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want %d", rr.Code, http.StatusOK)
+	}
+
+	data := parseResponse(t, rr).Data.(map[string]interface{})
+	if data["notify_on_match"] != false {
+		t.Errorf("notify_on_match: got %v, want false", data["notify_on_match"])
+	}
+	if data["notify_on_like"] != true {
+		t.Errorf("notify_on_like should be unchanged, got %v", data["notify_on_like"])
+	}
+}
+
+func TestUpdatePreferences_Errors(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+
+	rr := doRequest(t, mux, "PUT", fmt.Sprintf("/users/%s/preferences", aliceID), map[string]bool{})
+	if rr.Code != http.StatusUnprocessableEntity {
+		t.Errorf("empty body: got %d, want %d", rr.Code, http.StatusUnprocessableEntity)
+	}
+
+	rr = doRequest(t, mux, "PUT", fmt.Sprintf("/users/%s/preferences", uuid.New()), map[string]bool{
+		"notify_on_like": false,
+	})
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("unknown user: got %d, want %d", rr.Code, http.StatusNotFound)
+	}
+}
+
+func TestUpdatePreferences_QuietHours(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+	path := fmt.Sprintf("/users/%s/preferences", aliceID)
+
+	// Setting quiet hours stores them on the profile.
+	quiet := models.QuietHours{Start: "22:00", End: "07:00", Timezone: "Europe/Paris"}
+	rr := doRequest(t, mux, "PUT", path, models.UpdatePreferencesRequest{QuietHours: &quiet})
+	if rr.Code != http.StatusOK {
+		t.Fatalf("set: got %d, want %d", rr.Code, http.StatusOK)
+	}
+	data := parseResponse(t, rr).Data.(map[string]interface{})
+	got, ok := data["quiet_hours"].(map[string]interface{})
+	if !ok || got["start"] != "22:00" || got["end"] != "07:00" || got["timezone"] != "Europe/Paris" {
+		t.Errorf("quiet_hours: got %v", data["quiet_hours"])
+	}
+
+	// An empty start and end turn them off again.
+	rr = doRequest(t, mux, "PUT", path, models.UpdatePreferencesRequest{QuietHours: &models.QuietHours{}})
+	if rr.Code != http.StatusOK {
+		t.Fatalf("clear: got %d, want %d", rr.Code, http.StatusOK)
+	}
+	data = parseResponse(t, rr).Data.(map[string]interface{})
+	if _, present := data["quiet_hours"]; present {
+		t.Errorf("expected quiet_hours to be cleared, got %v", data["quiet_hours"])
+	}
+
+	invalid := []struct {
+		name  string
+		quiet models.QuietHours
+	}{
+		{"bad start", models.QuietHours{Start: "25:00", End: "07:00"}},
+		{"missing end", models.QuietHours{Start: "22:00"}},
+		{"empty window", models.QuietHours{Start: "22:00", End: "22:00"}},
+		{"unknown time zone", models.QuietHours{Start: "22:00", End: "07:00", Timezone: "Mars/Olympus"}},
+	}
+	for _, tc := range invalid {
+		t.Run(tc.name, func(t *testing.T) {
+			rr := doRequest(t, mux, "PUT", path, models.UpdatePreferencesRequest{QuietHours: &tc.quiet})
+			if rr.Code != http.StatusUnprocessableEntity {
+				t.Errorf("status: got %d, want %d", rr.Code, http.StatusUnprocessableEntity)
+			}
+		})
+	}
+}
+
+func TestExportUser_ContainsOnlyOwnData(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+	bobID, _ := createTestUser(t, mux, "Bob", "male", "zone-a", 30)
+	charlieID, _ := createTestUser(t, mux, "Charlie", "male", "zone-a", 25)
+	dianaID, _ := createTestUser(t, mux, "Diana", "female", "zone-a", 27)
+
+	swipe := func(from, to uuid.UUID, action string) {
+		doRequest(t, mux, "POST", "/swipe", models.CreateSwipeRequest{
+			SwiperID: from.String(), SwipedID: to.String(), Action: action,
+		})
+	}
+	swipe(aliceID, bobID, "LIKE")
+	swipe(bobID, aliceID, "LIKE") // Alice and Bob match.
+	swipe(charlieID, aliceID, "LIKE")
+	swipe(aliceID, charlieID, "PASS")
+	swipe(bobID, dianaID, "LIKE") // Unrelated to Alice.
+
+	rr := doRequest(t, mux, "GET", fmt.Sprintf("/users/%s/export", aliceID), nil)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want %d", rr.Code, http.StatusOK)
+	}
+
+	data := parseResponse(t, rr).Data.(map[string]interface{})
+
+	profile := data["profile"].(map[string]interface{})
+	if profile["name"] != "Alice" {
+		t.Errorf("profile name: got %v, want Alice", profile["name"])
+	}
+
+	swipes := data["swipes"].([]interface{})
+	if len(swipes) != 2 {
+		t.Errorf("expected 2 swipes made, got %d", len(swipes))
+	}
+	for _, raw := range swipes {
+		if raw.(map[string]interface{})["swiper_id"] != aliceID.String() {
+			t.Errorf("export contains a swipe not made by Alice: %v", raw)
+		}
+	}
+
+	likes := data["likes_received"].([]interface{})
+	if len(likes) != 2 {
+		t.Errorf("expected 2 likes received (Bob, Charlie), got %d", len(likes))
+	}
+	for _, raw := range likes {
+		if raw.(map[string]interface{})["swiped_id"] != aliceID.String() {
+			t.Errorf("export contains a like not aimed at Alice: %v", raw)
+		}
+	}
+
+	if matches := data["matches"].([]interface{}); len(matches) != 1 {
+		t.Errorf("expected 1 match, got %d", len(matches))
+	}
+}
+
+func TestExportUser_EmptyCollectionsAreArrays(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+
+	rr := doRequest(t, mux, "GET", fmt.Sprintf("/users/%s/export", aliceID), nil)
+	data := parseResponse(t, rr).Data.(map[string]interface{})
+
+	for _, field := range []string{"swipes", "likes_received", "matches"} {
+		if _, ok := data[field].([]interface{}); !ok {
+			t.Errorf("expected %s to be an array, got %v", field, data[field])
+		}
+	}
+}
+
+func TestExportUser_NotFound(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	rr := doRequest(t, mux, "GET", fmt.Sprintf("/users/%s/export", uuid.New()), nil)
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("status: got %d, want %d", rr.Code, http.StatusNotFound)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Feed endpoint tests
+// ---------------------------------------------------------------------------
+
+func TestGetFeed_Success(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	// Create users: Alice and Bob in zone-a, Charlie in zone-b.
+	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+	createTestUser(t, mux, "Bob", "male", "zone-a", 30)
+	createTestUser(t, mux, "Charlie", "male", "zone-b", 25)
+
+	// Get Alice's feed.
+	rr := doRequest(t, mux, "GET", fmt.Sprintf("/feed?user_id=%s", aliceID), nil)
+
+	if rr.Code != http.StatusOK {
+		t.Errorf("status: got %d, want %d", rr.Code, http.StatusOK)
+	}
+
+	resp := parseResponse(t, rr)
+
+	// Data should be an array with 1 user (Bob, same zone).
+	data, ok := resp.Data.([]interface{})
+	if !ok {
+		t.Fatal("expected data to be an array")
+	}
+	if len(data) != 1 {
+		t.Errorf("expected 1 user in feed, got %d", len(data))
+	}
+
+	// Meta should include the count.
+	if count, ok := resp.Meta["count"].(float64); !ok || int(count) != 1 {
+		t.Errorf("expected meta.count=1, got %v", resp.Meta["count"])
+	}
+}
+
+func TestGetFeed_UserNotFound(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	rr := doRequest(t, mux, "GET", fmt.Sprintf("/feed?user_id=%s", uuid.New()), nil)
+
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("status: got %d, want %d", rr.Code, http.StatusNotFound)
+	}
+}
+
+func TestGetFeed_MissingUserID(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	rr := doRequest(t, mux, "GET", "/feed", nil)
+
+	if rr.Code != http.StatusUnprocessableEntity {
+		t.Errorf("status: got %d, want %d", rr.Code, http.StatusUnprocessableEntity)
+	}
+}
+
+func TestGetFeed_InvalidUUID(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	rr := doRequest(t, mux, "GET", "/feed?user_id=not-a-uuid", nil)
+
+	if rr.Code != http.StatusUnprocessableEntity {
+		t.Errorf("status: got %d, want %d", rr.Code, http.StatusUnprocessableEntity)
+	}
+}
+
+func TestGetFeed_ExcludesSwipedUsers(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	// Create three users in the same zone.
+	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+	bobID, _ := createTestUser(t, mux, "Bob", "male", "zone-a", 30)
+	createTestUser(t, mux, "Charlie", "male", "zone-a", 25)
+
+	// Alice swipes on Bob.
+	swipeBody := models.CreateSwipeRequest{
+		SwiperID: aliceID.String(),
+		SwipedID: bobID.String(),
+		Action:   "LIKE",
+	}
+	doRequest(t, mux, "POST", "/swipe", swipeBody)
+
+	// Alice's feed should only have Charlie (Bob was swiped on).
+	rr := doRequest(t, mux, "GET", fmt.Sprintf("/feed?user_id=%s", aliceID), nil)
+	resp := parseResponse(t, rr)
+
+	data, ok := resp.Data.([]interface{})
+	if !ok {
+		t.Fatal("expected data to be an array")
+	}
+	if len(data) != 1 {
+		t.Fatalf("expected 1 user in feed, got %d", len(data))
+	}
+
+	// The remaining user should be Charlie.
+	user := data[0].(map[string]interface{})
+	if user["name"] != "Charlie" {
+		t.Errorf("expected Charlie in feed, got %v", user["name"])
+	}
+}
+
+func TestGetFeed_WithReasons(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+	bobID, _ := createTestUser(t, mux, "Bob", "male", "zone-a", 30)
+	doRequest(t, mux, "POST", "/swipe", models.CreateSwipeRequest{
+		SwiperID: bobID.String(), SwipedID: aliceID.String(), Action: "LIKE",
+	})
+
+	rr := doRequest(t, mux, "GET", fmt.Sprintf("/feed?user_id=%s&reasons=true", aliceID), nil)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want %d", rr.Code, http.StatusOK)
+	}
+
+	data := parseResponse(t, rr).Data.([]interface{})
+	if len(data) != 1 {
+		t.Fatalf("expected 1 user in feed, got %d", len(data))
+	}
+	reasons, ok := data[0].(map[string]interface{})["reasons"].([]interface{})
+	if !ok {
+		t.Fatal("expected reasons array on feed entry")
+	}
+	found := false
+	for _, r := range reasons {
+		if r == "liked you" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected \"liked you\" reason, got %v", reasons)
+	}
+}
+
+func TestGetFeed_ExcludeParam(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+	bobID, _ := createTestUser(t, mux, "Bob", "male", "zone-a", 30)
+	charlieID, _ := createTestUser(t, mux, "Charlie", "male", "zone-a", 25)
+	createTestUser(t, mux, "Dave", "male", "zone-a", 33)
+
+	rr := doRequest(t, mux, "GET", fmt.Sprintf("/feed?user_id=%s&exclude=%s,%s", aliceID, bobID, charlieID), nil)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want %d", rr.Code, http.StatusOK)
+	}
+
+	data := parseResponse(t, rr).Data.([]interface{})
+	if len(data) != 1 {
+		t.Fatalf("expected 1 user in feed, got %d", len(data))
+	}
+	if name := data[0].(map[string]interface{})["name"]; name != "Dave" {
+		t.Errorf("expected Dave, got %v", name)
+	}
+
+	// Without the parameter, everyone is back.
+	rr = doRequest(t, mux, "GET", fmt.Sprintf("/feed?user_id=%s", aliceID), nil)
+	if data := parseResponse(t, rr).Data.([]interface{}); len(data) != 3 {
+		t.Errorf("expected 3 users without exclude, got %d", len(data))
+	}
+}
+
+func TestGetFeed_ExcludeInvalidUUID(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+
+	rr := doRequest(t, mux, "GET", fmt.Sprintf("/feed?user_id=%s&exclude=%s,nope", aliceID, uuid.New()), nil)
+	if rr.Code != http.StatusUnprocessableEntity {
+		t.Errorf("status: got %d, want %d", rr.Code, http.StatusUnprocessableEntity)
+	}
+}
+
+func TestGetFeed_InvalidReasonsParam(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+
+	rr := doRequest(t, mux, "GET", fmt.Sprintf("/feed?user_id=%s&reasons=maybe", aliceID), nil)
+	if rr.Code != http.StatusUnprocessableEntity {
+		t.Errorf("status: got %d, want %d", rr.Code, http.StatusUnprocessableEntity)
+	}
+}
+
+func TestGetFeed_SortParam(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+	createTestUser(t, mux, "Bob", "male", "zone-a", 30)
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+	}{
+		{"interests", "&sort=interests", http.StatusOK},
+		{"newest", "&sort=newest", http.StatusOK},
+		{"age_asc", "&sort=age_asc", http.StatusOK},
+		{"age_desc", "&sort=age_desc", http.StatusOK},
+		{"distance", "&sort=distance", http.StatusOK},
+		{"recent_activity", "&sort=recent_activity", http.StatusOK},
+		{"least_liked", "&sort=least_liked", http.StatusOK},
+		{"unknown sort", "&sort=age", http.StatusUnprocessableEntity},
+		{"interests with cursor", "&sort=interests&cursor=", http.StatusUnprocessableEntity},
+		{"freshness weight", "&freshness_weight=0.5", http.StatusOK},
+		{"freshness weight above 1", "&freshness_weight=1.5", http.StatusUnprocessableEntity},
+		{"freshness weight NaN", "&freshness_weight=NaN", http.StatusUnprocessableEntity},
+		{"freshness weight not a number", "&freshness_weight=lots", http.StatusUnprocessableEntity},
+		{"freshness weight with cursor", "&freshness_weight=0.5&cursor=", http.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := doRequest(t, mux, "GET", "/feed?user_id="+aliceID.String()+tt.query, nil)
+			if rr.Code != tt.wantStatus {
+				t.Errorf("status: got %d, want %d", rr.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestGetFeed_IncludeSelf(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+	createTestUser(t, mux, "Bob", "male", "zone-a", 30)
+
+	rr := doRequest(t, mux, "GET", fmt.Sprintf("/feed?user_id=%s&include_self=true", aliceID), nil)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want %d", rr.Code, http.StatusOK)
+	}
+	data := parseResponse(t, rr).Data.([]interface{})
+	if len(data) != 1 {
+		t.Fatalf("expected exactly 1 profile, got %d", len(data))
+	}
+	if id := data[0].(map[string]interface{})["id"]; id != aliceID.String() {
+		t.Errorf("expected Alice's own profile, got %v", id)
+	}
+
+	rr = doRequest(t, mux, "GET", fmt.Sprintf("/feed?user_id=%s&include_self=sometimes", aliceID), nil)
+	if rr.Code != http.StatusUnprocessableEntity {
+		t.Errorf("invalid include_self: got %d, want %d", rr.Code, http.StatusUnprocessableEntity)
+	}
+}
+
+func TestGetFeed_OnlineOnly(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+	createTestUser(t, mux, "Bob", "male", "zone-a", 30)
+
+	// Users created through the API were active just now, so Bob is online.
+	rr := doRequest(t, mux, "GET", fmt.Sprintf("/feed?user_id=%s&online_only=true", aliceID), nil)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want %d", rr.Code, http.StatusOK)
+	}
+	data := parseResponse(t, rr).Data.([]interface{})
+	if len(data) != 1 {
+		t.Fatalf("expected 1 online candidate, got %d", len(data))
+	}
+	if online := data[0].(map[string]interface{})["online"]; online != true {
+		t.Errorf("expected online=true, got %v", online)
+	}
+
+	rr = doRequest(t, mux, "GET", fmt.Sprintf("/feed?user_id=%s&online_only=often", aliceID), nil)
+	if rr.Code != http.StatusUnprocessableEntity {
+		t.Errorf("invalid online_only: got %d, want %d", rr.Code, http.StatusUnprocessableEntity)
+	}
+}
+
+func TestGetFeed_OffsetPaging(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+	for i := 0; i < 25; i++ {
+		createTestUser(t, mux, fmt.Sprintf("User%d", i), "male", "zone-a", 30)
+	}
+
+	tests := []struct {
+		name       string
+		query      string
+		wantCount  int
+		wantOffset int
+		wantLimit  int
+		wantMore   bool
+	}{
+		{"defaults", "", 20, 0, 20, true},
+		{"second page", "&offset=20", 5, 20, 20, false},
+		{"custom limit", "&limit=10&offset=5", 10, 5, 10, true},
+		{"page before the last", "&limit=5&offset=15", 5, 15, 5, true},
+		{"last page ends at the total", "&limit=5&offset=20", 5, 20, 5, false},
+		{"past the end", "&offset=100", 0, 100, 20, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rr := doRequest(t, mux, "GET", "/feed?user_id="+aliceID.String()+tc.query, nil)
+			if rr.Code != http.StatusOK {
+				t.Fatalf("status: got %d, want %d", rr.Code, http.StatusOK)
+			}
+			resp := parseResponse(t, rr)
+			if data, _ := resp.Data.([]interface{}); len(data) != tc.wantCount {
+				t.Errorf("expected %d entries, got %d", tc.wantCount, len(data))
+			}
+			want := map[string]float64{
+				"count":  float64(tc.wantCount),
+				"offset": float64(tc.wantOffset),
+				"limit":  float64(tc.wantLimit),
+				"total":  25,
+			}
+			for key, value := range want {
+				if resp.Meta[key] != value {
+					t.Errorf("meta.%s: expected %v, got %v", key, value, resp.Meta[key])
+				}
+			}
+			if resp.Meta["has_more"] != tc.wantMore {
+				t.Errorf("meta.has_more: expected %v, got %v", tc.wantMore, resp.Meta["has_more"])
+			}
+		})
+	}
+}
+
+func TestGetFeed_OffsetPagingInvalidInput(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+
+	for _, query := range []string{
+		"&limit=0", "&limit=101", "&limit=-1", "&limit=ten",
+		"&offset=-1", "&offset=abc",
+		"&cursor=&offset=5",
+	} {
+		rr := doRequest(t, mux, "GET", "/feed?user_id="+aliceID.String()+query, nil)
+		if rr.Code != http.StatusUnprocessableEntity {
+			t.Errorf("%s: status got %d, want %d", query, rr.Code, http.StatusUnprocessableEntity)
+		}
+	}
+}
+
+func TestCountFeed(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+	bobID, _ := createTestUser(t, mux, "Bob", "male", "zone-a", 30)
+	createTestUser(t, mux, "Charlie", "male", "zone-a", 32)
+	createTestUser(t, mux, "Dave", "male", "zone-b", 32)
+	doRequest(t, mux, "POST", "/swipe", models.CreateSwipeRequest{
+		SwiperID: aliceID.String(), SwipedID: bobID.String(), Action: "PASS",
+	})
+
+	rr := doRequest(t, mux, "GET", "/feed/count?user_id="+aliceID.String(), nil)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want %d", rr.Code, http.StatusOK)
+	}
+	resp := parseResponse(t, rr)
+	if data := resp.Data.(map[string]interface{}); data["count"] != float64(1) {
+		t.Errorf("expected count 1, got %v", data["count"])
+	}
+	if _, ok := resp.Meta["stages"]; ok {
+		t.Error("expected no stages without explain")
+	}
+
+	// The count agrees with the feed itself.
+	rr = doRequest(t, mux, "GET", "/feed?user_id="+aliceID.String(), nil)
+	if feed := parseResponse(t, rr).Data.([]interface{}); len(feed) != 1 {
+		t.Errorf("expected the feed to hold 1 entry, got %d", len(feed))
+	}
+
+	rr = doRequest(t, mux, "GET", "/feed/count?explain=true&user_id="+aliceID.String(), nil)
+	stages, ok := parseResponse(t, rr).Meta["stages"].([]interface{})
+	if !ok || len(stages) == 0 {
+		t.Fatalf("expected meta.stages with explain, got %v", parseResponse(t, rr).Meta)
+	}
+	if first := stages[0].(map[string]interface{}); first["stage"] != "zone" || first["remaining"] != float64(3) {
+		t.Errorf("expected 3 users left after the zone stage, got %v", first)
+	}
+}
+
+func TestCountFeed_Errors(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+	}{
+		{"missing user_id", "", http.StatusUnprocessableEntity},
+		{"unknown user", "user_id=" + uuid.New().String(), http.StatusNotFound},
+		{"invalid explain", "user_id=" + aliceID.String() + "&explain=maybe", http.StatusUnprocessableEntity},
+		{"invalid option", "user_id=" + aliceID.String() + "&online_only=maybe", http.StatusUnprocessableEntity},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rr := doRequest(t, mux, "GET", "/feed/count?"+tc.query, nil)
+			if rr.Code != tc.wantStatus {
+				t.Errorf("status: got %d, want %d", rr.Code, tc.wantStatus)
+			}
+		})
+	}
+}
+
+func TestGetFeed_CursorPaging(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+	for _, name := range []string{"Bob", "Charlie", "Dave", "Eve", "Frank"} {
+		createTestUser(t, mux, name, "other", "zone-a", 30)
+	}
+
+	seen := make(map[string]bool)
+	cursor := ""
+	for pages := 1; ; pages++ {
+		rr := doRequest(t, mux, "GET", fmt.Sprintf("/feed?user_id=%s&limit=2&cursor=%s", aliceID, cursor), nil)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("status: got %d, want %d", rr.Code, http.StatusOK)
+		}
+		resp := parseResponse(t, rr)
+		for _, raw := range resp.Data.([]interface{}) {
+			name := raw.(map[string]interface{})["name"].(string)
+			if seen[name] {
+				t.Errorf("%s appeared on more than one page", name)
+			}
+			seen[name] = true
+		}
+
+		next, ok := resp.Meta["next_cursor"].(string)
+		if !ok {
+			if pages != 3 {
+				t.Errorf("expected 3 pages of 2, got %d", pages)
+			}
+			break
+		}
+		cursor = next
+	}
+	if len(seen) != 5 {
+		t.Errorf("expected 5 candidates across pages, got %d", len(seen))
+	}
+}
+
+func TestGetFeed_CursorPagingInvalidInput(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+
+	for _, query := range []string{"cursor=garbage", "cursor=&limit=0", "cursor=&limit=101", "cursor=&limit=x"} {
+		t.Run(query, func(t *testing.T) {
+			rr := doRequest(t, mux, "GET", fmt.Sprintf("/feed?user_id=%s&%s", aliceID, query), nil)
+			if rr.Code != http.StatusUnprocessableEntity {
+				t.Errorf("status: got %d, want %d", rr.Code, http.StatusUnprocessableEntity)
+			}
+		})
+	}
+}
+
+func TestGetFeed_NetworkMode(t *testing.T) {
 	mux := setupTestRouter(t)
 
-	// Create three users in the same zone.
 	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
 	bobID, _ := createTestUser(t, mux, "Bob", "male", "zone-a", 30)
-	createTestUser(t, mux, "Charlie", "male", "zone-a", 25)
-
-	// Alice swipes on Bob.
-	swipeBody := models.CreateSwipeRequest{
-		SwiperID: aliceID.String(),
-		SwipedID: bobID.String(),
-		Action:   "LIKE",
+	carolID, _ := createTestUser(t, mux, "Carol", "female", "zone-b", 27)
+	createTestUser(t, mux, "Dave", "male", "zone-a", 31)
+	createTestMatch(t, mux, aliceID, bobID)
+	createTestMatch(t, mux, bobID, carolID)
+
+	// Carol is in another zone, so only the network feed finds her; Dave
+	// is in Alice's zone but not connected to her.
+	rr := doRequest(t, mux, "GET", "/feed?mode=network&user_id="+aliceID.String(), nil)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want %d", rr.Code, http.StatusOK)
 	}
-	doRequest(t, mux, "POST", "/swipe", swipeBody)
-
-	// Alice's feed should only have Charlie (Bob was swiped on).
-	rr := doRequest(t, mux, "GET", fmt.Sprintf("/feed?user_id=%s", aliceID), nil)
 	resp := parseResponse(t, rr)
-
-	data, ok := resp.Data.([]interface{})
-	if !ok {
-		t.Fatal("expected data to be an array")
+	data, _ := resp.Data.([]interface{})
+	if len(data) != 1 || data[0].(map[string]interface{})["id"] != carolID.String() {
+		t.Errorf("expected only Carol, got %v", data)
 	}
-	if len(data) != 1 {
-		t.Fatalf("expected 1 user in feed, got %d", len(data))
+	if resp.Meta["total"] != float64(1) {
+		t.Errorf("meta.total: expected 1, got %v", resp.Meta["total"])
 	}
 
-	// The remaining user should be Charlie.
-	user := data[0].(map[string]interface{})
-	if user["name"] != "Charlie" {
-		t.Errorf("expected Charlie in feed, got %v", user["name"])
+	rr = doRequest(t, mux, "GET", "/feed?mode=network&user_id="+uuid.New().String(), nil)
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("unknown user: status got %d, want %d", rr.Code, http.StatusNotFound)
+	}
+	for _, query := range []string{"&mode=nearby", "&mode=network&cursor=", "&mode=network&limit=0", "&mode=network&offset=-1"} {
+		rr := doRequest(t, mux, "GET", "/feed?user_id="+aliceID.String()+query, nil)
+		if rr.Code != http.StatusUnprocessableEntity {
+			t.Errorf("%s: status got %d, want %d", query, rr.Code, http.StatusUnprocessableEntity)
+		}
 	}
 }
 
@@ -462,8 +1912,13 @@ func TestCreateSwipe_MutualMatch(t *testing.T) {
 	if data["matched"] != true {
 		t.Error("expected matched=true for mutual LIKE")
 	}
-	if data["match"] == nil {
-		t.Error("expected match details in response")
+	match, ok := data["match"].(map[string]interface{})
+	if !ok {
+		t.Fatal("expected match details in response")
+	}
+	// Bob's LIKE completed the match.
+	if match["initiator_id"] != bobID.String() {
+		t.Errorf("initiator_id: got %v, want Bob (%s)", match["initiator_id"], bobID)
 	}
 }
 
@@ -570,10 +2025,482 @@ func TestCreateSwipe_ValidationErrors(t *testing.T) {
 	}
 }
 
+func TestCreateSwipe_ActionAliases(t *testing.T) {
+	s := store.GetStore()
+	s.Reset()
+
+	aliases, err := models.NewSwipeActionAliases(map[string]string{"YES": "LIKE", "0": "pass"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	swipeHandler := NewSwipeHandler(services.NewSwipeService(s), services.NewMatchService(s), s)
+	swipeHandler.SetActionAliases(aliases)
+	router := http.NewServeMux()
+	router.HandleFunc("POST /swipe", swipeHandler.CreateSwipe)
+
+	tests := []struct {
+		action     string
+		wantStatus int
+		wantAction string
+	}{
+		{"YES", http.StatusCreated, "LIKE"},
+		{"0", http.StatusCreated, "PASS"},
+		{"LIKE", http.StatusCreated, "LIKE"},
+		{"NO", http.StatusUnprocessableEntity, ""},  // not configured
+		{"yes", http.StatusUnprocessableEntity, ""}, // aliases match exactly
+	}
+	for _, tc := range tests {
+		t.Run(tc.action, func(t *testing.T) {
+			// Fresh users each time, so no swipe depends on an earlier one.
+			swiper, swiped := uuid.New(), uuid.New()
+			for _, id := range []uuid.UUID{swiper, swiped} {
+				s.AddUser(models.User{ID: id, Name: "user", Age: 30, Gender: "other", ZoneID: "zone-a"})
+			}
+
+			rr := doRequest(t, router, "POST", "/swipe", models.CreateSwipeRequest{
+				SwiperID: swiper.String(), SwipedID: swiped.String(), Action: tc.action,
+			})
+			if rr.Code != tc.wantStatus {
+				t.Fatalf("status: got %d, want %d", rr.Code, tc.wantStatus)
+			}
+			if tc.wantAction == "" {
+				return
+			}
+			data := parseResponse(t, rr).Data.(map[string]interface{})
+			if got := data["swipe"].(map[string]interface{})["action"]; got != tc.wantAction {
+				t.Errorf("expected stored action %s, got %v", tc.wantAction, got)
+			}
+		})
+	}
+}
+
+func TestCreateSwipe_NoAliasesByDefault(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+	bobID, _ := createTestUser(t, mux, "Bob", "male", "zone-a", 30)
+
+	for _, action := range []string{"YES", "1"} {
+		rr := doRequest(t, mux, "POST", "/swipe", models.CreateSwipeRequest{
+			SwiperID: aliceID.String(), SwipedID: bobID.String(), Action: action,
+		})
+		if rr.Code != http.StatusUnprocessableEntity {
+			t.Errorf("%s: status got %d, want %d", action, rr.Code, http.StatusUnprocessableEntity)
+		}
+	}
+}
+
+func TestNewSwipeActionAliases_RejectsBadConfig(t *testing.T) {
+	tests := []struct {
+		name  string
+		pairs map[string]string
+	}{
+		{"unknown target", map[string]string{"YES": "MAYBE"}},
+		{"redefines LIKE", map[string]string{"LIKE": "PASS"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := models.NewSwipeActionAliases(tc.pairs); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+// setupLikeBackTest returns a router serving POST /swipe with the like-back
+// suggestion enabled, plus two users where Bob has already liked Alice and
+// Carol, who hasn't swiped on anyone.
+func setupLikeBackTest(t *testing.T) (mux http.Handler, alice, bob, carol uuid.UUID) {
+	t.Helper()
+
+	s := store.GetStore()
+	s.Reset()
+	swipeHandler := NewSwipeHandler(services.NewSwipeService(s), services.NewMatchService(s), s)
+	swipeHandler.SetLikeBackSuggestion(true)
+
+	router := http.NewServeMux()
+	router.HandleFunc("POST /swipe", swipeHandler.CreateSwipe)
+
+	for _, id := range []*uuid.UUID{&alice, &bob, &carol} {
+		*id = uuid.New()
+		s.AddUser(models.User{ID: *id, Name: "user", Age: 30, Gender: "other", ZoneID: "zone-a"})
+	}
+	s.AddSwipe(models.Swipe{SwiperID: bob, SwipedID: alice, Action: models.SwipeActionLike, Timestamp: time.Now().UTC()})
+	return router, alice, bob, carol
+}
+
+func TestCreateSwipe_DailyLimit(t *testing.T) {
+	s := store.GetStore()
+	s.Reset()
+
+	swipeService := services.NewSwipeService(s)
+	swipeService.SetDailySwipeLimit(2)
+	swipeHandler := NewSwipeHandler(swipeService, services.NewMatchService(s), s)
+	router := http.NewServeMux()
+	router.HandleFunc("POST /swipe", swipeHandler.CreateSwipe)
+
+	alice := uuid.New()
+	s.AddUser(models.User{ID: alice, Name: "Alice", Age: 30, Gender: "female", ZoneID: "zone-a"})
+	swipe := func() *httptest.ResponseRecorder {
+		other := uuid.New()
+		s.AddUser(models.User{ID: other, Name: "Other", Age: 30, Gender: "male", ZoneID: "zone-a"})
+		return doRequest(t, router, "POST", "/swipe", models.CreateSwipeRequest{
+			SwiperID: alice.String(), SwipedID: other.String(), Action: "LIKE",
+		})
+	}
+
+	for i := 0; i < 2; i++ {
+		if rr := swipe(); rr.Code != http.StatusCreated {
+			t.Fatalf("swipe %d: got %d, want %d", i, rr.Code, http.StatusCreated)
+		}
+	}
+
+	rr := swipe()
+	if rr.Code != http.StatusTooManyRequests {
+		t.Fatalf("status: got %d, want %d", rr.Code, http.StatusTooManyRequests)
+	}
+	resp := parseResponse(t, rr)
+	if resp.Data != nil {
+		t.Errorf("expected null data, got %v", resp.Data)
+	}
+	if len(resp.Errors) != 1 || !strings.Contains(resp.Errors[0].Message, "daily swipe limit") {
+		t.Errorf("expected a daily limit error, got %v", resp.Errors)
+	}
+	if retry, err := strconv.Atoi(rr.Header().Get("Retry-After")); err != nil || retry < 1 {
+		t.Errorf("expected a positive Retry-After, got %q", rr.Header().Get("Retry-After"))
+	}
+	if n := len(s.GetSwipesByUser(alice)); n != 2 {
+		t.Errorf("expected the refused swipe not to be recorded, got %d swipes", n)
+	}
+}
+
+func TestCreateSwipe_LikeBackSuggestion(t *testing.T) {
+	mux, alice, bob, carol := setupLikeBackTest(t)
+
+	pass := func(target uuid.UUID) models.APIResponse {
+		rr := doRequest(t, mux, "POST", "/swipe", models.CreateSwipeRequest{
+			SwiperID: alice.String(), SwipedID: target.String(), Action: "PASS",
+		})
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("status: got %d, want %d", rr.Code, http.StatusCreated)
+		}
+		return parseResponse(t, rr)
+	}
+
+	// Passing on Bob, who liked Alice, surfaces the suggestion.
+	if got := pass(bob).Meta["suggestion"]; got != LikeBackSuggestion {
+		t.Errorf("PASS on a liker: expected suggestion, got %v", got)
+	}
+
+	// Only the first PASS does.
+	if got, ok := pass(bob).Meta["suggestion"]; ok {
+		t.Errorf("second PASS on a liker: expected no suggestion, got %v", got)
+	}
+
+	// Carol never liked Alice, so there's nothing to suggest.
+	if got, ok := pass(carol).Meta["suggestion"]; ok {
+		t.Errorf("PASS on a non-liker: expected no suggestion, got %v", got)
+	}
+}
+
+func TestCreateSwipe_LikeBackSuggestionOffByDefault(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+	bobID, _ := createTestUser(t, mux, "Bob", "male", "zone-a", 30)
+	doRequest(t, mux, "POST", "/swipe", models.CreateSwipeRequest{
+		SwiperID: bobID.String(), SwipedID: aliceID.String(), Action: "LIKE",
+	})
+
+	rr := doRequest(t, mux, "POST", "/swipe", models.CreateSwipeRequest{
+		SwiperID: aliceID.String(), SwipedID: bobID.String(), Action: "PASS",
+	})
+	if got, ok := parseResponse(t, rr).Meta["suggestion"]; ok {
+		t.Errorf("expected no suggestion when disabled, got %v", got)
+	}
+}
+
 // ---------------------------------------------------------------------------
 // Matches endpoint tests
 // ---------------------------------------------------------------------------
 
+func TestUndoSwipe_RestoresFeedAndRemovesMatch(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+	bobID, _ := createTestUser(t, mux, "Bob", "male", "zone-a", 30)
+
+	doRequest(t, mux, "POST", "/swipe", models.CreateSwipeRequest{
+		SwiperID: bobID.String(), SwipedID: aliceID.String(), Action: "LIKE",
+	})
+	doRequest(t, mux, "POST", "/swipe", models.CreateSwipeRequest{
+		SwiperID: aliceID.String(), SwipedID: bobID.String(), Action: "LIKE",
+	})
+
+	rr := doRequest(t, mux, "POST", "/swipe/undo", models.UndoSwipeRequest{UserID: aliceID.String()})
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want %d", rr.Code, http.StatusOK)
+	}
+	data, ok := parseResponse(t, rr).Data.(map[string]interface{})
+	if !ok {
+		t.Fatal("expected data to be an object")
+	}
+	if data["swiped_id"] != bobID.String() || data["action"] != "LIKE" {
+		t.Errorf("expected the LIKE on Bob to be returned, got %v", data)
+	}
+
+	// The match is gone for both users.
+	rr = doRequest(t, mux, "GET", "/matches?user_id="+bobID.String(), nil)
+	if matches, _ := parseResponse(t, rr).Data.([]interface{}); len(matches) != 0 {
+		t.Errorf("expected no matches after undo, got %d", len(matches))
+	}
+
+	// Bob is back in Alice's feed.
+	rr = doRequest(t, mux, "GET", "/feed?user_id="+aliceID.String(), nil)
+	feed, _ := parseResponse(t, rr).Data.([]interface{})
+	if len(feed) != 1 {
+		t.Fatalf("expected Bob back in the feed, got %d entries", len(feed))
+	}
+}
+
+func TestUndoSwipe_Errors(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+
+	tests := []struct {
+		name       string
+		body       interface{}
+		wantStatus int
+	}{
+		{"missing user_id", models.UndoSwipeRequest{}, http.StatusUnprocessableEntity},
+		{"invalid user_id", models.UndoSwipeRequest{UserID: "nope"}, http.StatusUnprocessableEntity},
+		{"unknown user", models.UndoSwipeRequest{UserID: uuid.New().String()}, http.StatusNotFound},
+		{"nothing to undo", models.UndoSwipeRequest{UserID: aliceID.String()}, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := doRequest(t, mux, "POST", "/swipe/undo", tt.body)
+			if rr.Code != tt.wantStatus {
+				t.Errorf("status: got %d, want %d", rr.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestUndoSwipe_MatchUndoDisallowed(t *testing.T) {
+	s := store.GetStore()
+	s.Reset()
+	swipeHandler := NewSwipeHandler(services.NewSwipeService(s), services.NewMatchService(s), s)
+	swipeHandler.SetAllowMatchUndo(false)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /swipe", swipeHandler.CreateSwipe)
+	mux.HandleFunc("POST /swipe/undo", swipeHandler.UndoSwipe)
+
+	var alice, bob, carol uuid.UUID
+	for _, id := range []*uuid.UUID{&alice, &bob, &carol} {
+		*id = uuid.New()
+		s.AddUser(models.User{ID: *id, Name: "user", Age: 30, Gender: "other", ZoneID: "zone-a"})
+	}
+
+	doRequest(t, mux, "POST", "/swipe", models.CreateSwipeRequest{
+		SwiperID: bob.String(), SwipedID: alice.String(), Action: "LIKE",
+	})
+	doRequest(t, mux, "POST", "/swipe", models.CreateSwipeRequest{
+		SwiperID: alice.String(), SwipedID: bob.String(), Action: "LIKE",
+	})
+
+	rr := doRequest(t, mux, "POST", "/swipe/undo", models.UndoSwipeRequest{UserID: alice.String()})
+	if rr.Code != http.StatusConflict {
+		t.Fatalf("status: got %d, want %d", rr.Code, http.StatusConflict)
+	}
+	if s.FindMatch(alice, bob) == nil {
+		t.Error("the match should be kept")
+	}
+	if s.FindSwipe(alice, bob) == nil || s.FindSwipe(bob, alice) == nil {
+		t.Error("both LIKEs should be kept")
+	}
+
+	// Swipes that didn't form a match can still be undone.
+	doRequest(t, mux, "POST", "/swipe", models.CreateSwipeRequest{
+		SwiperID: alice.String(), SwipedID: carol.String(), Action: "PASS",
+	})
+	rr = doRequest(t, mux, "POST", "/swipe/undo", models.UndoSwipeRequest{UserID: alice.String()})
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want %d", rr.Code, http.StatusOK)
+	}
+	if s.FindSwipe(alice, carol) != nil {
+		t.Error("the PASS on Carol should be undone")
+	}
+}
+
+func TestDeleteSwipe(t *testing.T) {
+	mux := setupTestRouter(t)
+	s := store.GetStore()
+
+	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+	bobID, _ := createTestUser(t, mux, "Bob", "male", "zone-a", 30)
+	carolID, _ := createTestUser(t, mux, "Carol", "female", "zone-a", 27)
+	createTestMatch(t, mux, aliceID, bobID)
+	doRequest(t, mux, "POST", "/swipe", models.CreateSwipeRequest{
+		SwiperID: bobID.String(), SwipedID: carolID.String(), Action: "PASS",
+	})
+
+	deletePath := func(swiper, swiped uuid.UUID) string {
+		return "/swipe?swiper_id=" + swiper.String() + "&swiped_id=" + swiped.String()
+	}
+
+	t.Run("without a match", func(t *testing.T) {
+		rr := doAdminRequest(t, mux, "DELETE", deletePath(bobID, carolID), nil)
+		if rr.Code != http.StatusNoContent {
+			t.Fatalf("status: got %d, want %d", rr.Code, http.StatusNoContent)
+		}
+		if s.FindSwipe(bobID, carolID) != nil {
+			t.Error("the PASS on Carol should be gone")
+		}
+		if s.FindMatch(aliceID, bobID) == nil {
+			t.Error("Alice and Bob's match should be kept")
+		}
+	})
+
+	t.Run("with a match", func(t *testing.T) {
+		rr := doAdminRequest(t, mux, "DELETE", deletePath(bobID, aliceID), nil)
+		if rr.Code != http.StatusNoContent {
+			t.Fatalf("status: got %d, want %d", rr.Code, http.StatusNoContent)
+		}
+		if s.FindMatch(aliceID, bobID) != nil {
+			t.Error("the match should be removed with the swipe")
+		}
+		if s.FindSwipe(aliceID, bobID) == nil {
+			t.Error("Alice's LIKE on Bob should be kept")
+		}
+	})
+
+	tests := []struct {
+		name       string
+		path       string
+		admin      bool
+		wantStatus int
+	}{
+		{"swipe already removed", deletePath(bobID, aliceID), true, http.StatusNotFound},
+		{"never swiped", deletePath(carolID, aliceID), true, http.StatusNotFound},
+		{"missing swiped_id", "/swipe?swiper_id=" + aliceID.String(), true, http.StatusUnprocessableEntity},
+		{"invalid swiper_id", "/swipe?swiper_id=nope&swiped_id=" + bobID.String(), true, http.StatusUnprocessableEntity},
+		{"not an admin", deletePath(aliceID, bobID), false, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var rr *httptest.ResponseRecorder
+			if tt.admin {
+				rr = doAdminRequest(t, mux, "DELETE", tt.path, nil)
+			} else {
+				rr = doRequest(t, mux, "DELETE", tt.path, nil)
+			}
+			if rr.Code != tt.wantStatus {
+				t.Errorf("status: got %d, want %d", rr.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestGetSwipes(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+	bobID, _ := createTestUser(t, mux, "Bob", "male", "zone-a", 30)
+	carolID, _ := createTestUser(t, mux, "Carol", "female", "zone-a", 27)
+	daveID, _ := createTestUser(t, mux, "Dave", "male", "zone-a", 31)
+
+	for _, swipe := range []struct {
+		swiped uuid.UUID
+		action string
+	}{{bobID, "LIKE"}, {carolID, "PASS"}, {daveID, "LIKE"}} {
+		rr := doRequest(t, mux, "POST", "/swipe", models.CreateSwipeRequest{
+			SwiperID: aliceID.String(), SwipedID: swipe.swiped.String(), Action: swipe.action,
+		})
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("swipe status: got %d, want %d", rr.Code, http.StatusCreated)
+		}
+	}
+	// Bob's swipe on Alice is his, not hers.
+	doRequest(t, mux, "POST", "/swipe", models.CreateSwipeRequest{
+		SwiperID: bobID.String(), SwipedID: aliceID.String(), Action: "PASS",
+	})
+
+	swipedIDs := func(resp models.APIResponse) []string {
+		var ids []string
+		for _, item := range resp.Data.([]interface{}) {
+			ids = append(ids, item.(map[string]interface{})["swiped_id"].(string))
+		}
+		return ids
+	}
+
+	tests := []struct {
+		name      string
+		query     string
+		want      []uuid.UUID
+		wantTotal float64
+	}{
+		{"newest first", "", []uuid.UUID{daveID, carolID, bobID}, 3},
+		{"likes only", "&action=LIKE", []uuid.UUID{daveID, bobID}, 2},
+		{"passes only", "&action=PASS", []uuid.UUID{carolID}, 1},
+		{"second page", "&limit=1&offset=1", []uuid.UUID{carolID}, 3},
+		{"past the end", "&offset=10", nil, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := doRequest(t, mux, "GET", "/swipes?user_id="+aliceID.String()+tt.query, nil)
+			if rr.Code != http.StatusOK {
+				t.Fatalf("status: got %d, want %d", rr.Code, http.StatusOK)
+			}
+			resp := parseResponse(t, rr)
+			got := swipedIDs(resp)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d swipes, want %d", len(got), len(tt.want))
+			}
+			for i, id := range tt.want {
+				if got[i] != id.String() {
+					t.Errorf("swipe %d: got %s, want %s", i, got[i], id)
+				}
+			}
+			if resp.Meta["total"] != tt.wantTotal || resp.Meta["count"] != float64(len(tt.want)) {
+				t.Errorf("meta: got total %v, count %v", resp.Meta["total"], resp.Meta["count"])
+			}
+		})
+	}
+}
+
+func TestGetSwipes_Errors(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+	alice := aliceID.String()
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+	}{
+		{"missing user_id", "", http.StatusUnprocessableEntity},
+		{"invalid user_id", "user_id=nope", http.StatusUnprocessableEntity},
+		{"unknown user", "user_id=" + uuid.New().String(), http.StatusNotFound},
+		{"invalid action", "user_id=" + alice + "&action=MAYBE", http.StatusUnprocessableEntity},
+		{"invalid limit", "user_id=" + alice + "&limit=0", http.StatusUnprocessableEntity},
+		{"invalid offset", "user_id=" + alice + "&offset=-1", http.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := doRequest(t, mux, "GET", "/swipes?"+tt.query, nil)
+			if rr.Code != tt.wantStatus {
+				t.Errorf("status: got %d, want %d", rr.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
 func TestGetMatches_Success(t *testing.T) {
 	mux := setupTestRouter(t)
 
@@ -605,7 +2532,17 @@ func TestGetMatches_Success(t *testing.T) {
 		t.Fatal("expected data to be an array")
 	}
 	if len(data) != 1 {
-		t.Errorf("expected 1 match, got %d", len(data))
+		t.Fatalf("expected 1 match, got %d", len(data))
+	}
+
+	// Each entry carries Bob's full profile rather than bare IDs.
+	entry := data[0].(map[string]interface{})
+	other, ok := entry["user"].(map[string]interface{})
+	if !ok || other["id"] != bobID.String() || other["name"] != "Bob" {
+		t.Errorf("expected Bob's profile in the match, got %v", entry["user"])
+	}
+	if entry["match_id"] == nil || entry["matched_at"] == nil {
+		t.Errorf("expected match_id and matched_at, got %v", entry)
 	}
 
 	// Verify meta count.
@@ -759,6 +2696,35 @@ func TestFullFlow_CreateSwipeMatch(t *testing.T) {
 // Response envelope tests
 // ---------------------------------------------------------------------------
 
+func TestWriteInternalError_IncludesIncidentID(t *testing.T) {
+	// A handler that always hits an unexpected failure path.
+	failing := func(w http.ResponseWriter, r *http.Request) {
+		writeInternalError(w, errors.New("database exploded"))
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /boom", failing)
+	rr := doRequest(t, mux, "GET", "/boom", nil)
+
+	if rr.Code != http.StatusInternalServerError {
+		t.Errorf("status: got %d, want %d", rr.Code, http.StatusInternalServerError)
+	}
+
+	resp := parseResponse(t, rr)
+	incidentID, ok := resp.Meta["incident_id"].(string)
+	if !ok {
+		t.Fatalf("expected meta.incident_id, got %v", resp.Meta)
+	}
+	if _, err := uuid.Parse(incidentID); err != nil {
+		t.Errorf("incident_id is not a UUID: %q", incidentID)
+	}
+
+	// The underlying error must not leak to the client.
+	if len(resp.Errors) != 1 || resp.Errors[0].Message != "internal server error" {
+		t.Errorf("unexpected errors: %+v", resp.Errors)
+	}
+}
+
 func TestResponseEnvelope_AlwaysHasRequiredFields(t *testing.T) {
 	mux := setupTestRouter(t)
 
@@ -1,18 +1,69 @@
 // This file contains the health check endpoint handler.
-//   - GET / — Returns a simple health check response
+//   - GET / — Returns the service status, uptime, and store sizes
 package handlers
 
 import (
 	"net/http"
+	"time"
+
+	"github.com/dlfelps/tinder-go-claude/internal/store"
 )
 
-// HealthCheck handles GET / — a simple endpoint that confirms the API
-// is running. Health check endpoints are standard practice in web services;
-// they're used by load balancers and monitoring tools to verify the service
-// is alive.
-func HealthCheck(w http.ResponseWriter, r *http.Request) {
-	writeSuccess(w, http.StatusOK, map[string]string{
-		"status":  "healthy",
-		"service": "tinder-claude",
-	}, nil)
+// HealthHandler serves the health check. It holds the store so the check can
+// report whether the store is usable and how much data it holds, and the
+// time the process started so it can report uptime.
+type HealthHandler struct {
+	store     *store.InMemoryStore
+	startedAt time.Time
+}
+
+// NewHealthHandler creates a new HealthHandler. startedAt is when the
+// process started; main.go captures it before doing anything else.
+func NewHealthHandler(s *store.InMemoryStore, startedAt time.Time) *HealthHandler {
+	return &HealthHandler{store: s, startedAt: startedAt}
+}
+
+// HealthStatus is the JSON body returned by GET /.
+type HealthStatus struct {
+	// Status is "healthy" when the store is ready and "unhealthy"
+	// otherwise. Older clients only look at this field.
+	Status  string `json:"status"`
+	Service string `json:"service"`
+
+	// Uptime is how long the process has been running, rounded to the
+	// second, in Go duration format such as "1h2m3s".
+	Uptime string `json:"uptime"`
+
+	// Store is "ready" or "unavailable". The counts are only filled in
+	// when it is ready.
+	Store   string `json:"store"`
+	Users   int    `json:"users"`
+	Swipes  int    `json:"swipes"`
+	Matches int    `json:"matches"`
+}
+
+// HealthCheck handles GET / — confirms the API is running and reports some
+// basic numbers. Health check endpoints are standard practice in web
+// services; they're used by load balancers and monitoring tools to verify
+// the service is alive. A store that isn't ready is reported with 503 so a
+// load balancer stops sending traffic to this instance.
+func (h *HealthHandler) HealthCheck(w http.ResponseWriter, r *http.Request) {
+	health := HealthStatus{
+		Status:  "healthy",
+		Service: "tinder-claude",
+		Uptime:  time.Since(h.startedAt).Round(time.Second).String(),
+		Store:   "ready",
+	}
+	if h.store == nil {
+		health.Status = "unhealthy"
+		health.Store = "unavailable"
+		writeSuccess(w, http.StatusServiceUnavailable, health, nil)
+		return
+	}
+
+	stats := h.store.Stats()
+	health.Users = stats.TotalUsers
+	health.Swipes = stats.TotalSwipes
+	health.Matches = stats.TotalMatches
+	writeSuccess(w, http.StatusOK, health, nil)
 }
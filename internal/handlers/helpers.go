@@ -12,10 +12,21 @@
 package handlers
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
 	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
 
 	"github.com/dlfelps/tinder-go-claude/internal/models"
+	"github.com/google/uuid"
 )
 
 // writeJSON is a helper that serializes a value to JSON and writes it to the
@@ -46,7 +57,217 @@ func writeSuccess(w http.ResponseWriter, status int, data interface{}, meta map[
 	writeJSON(w, status, models.NewSuccessResponse(data, meta))
 }
 
+// writePaginated writes one page of a list with the standard paging
+// metadata; see models.NewPaginatedResponse.
+func writePaginated(w http.ResponseWriter, data interface{}, total, limit, offset int) {
+	writeJSON(w, http.StatusOK, models.NewPaginatedResponse(data, total, limit, offset))
+}
+
+// omitNullErrorData is set by SetOmitNullErrorData. It is an atomic.Bool
+// because every handler goroutine reads it.
+var omitNullErrorData atomic.Bool
+
+// SetOmitNullErrorData controls whether error responses include "data":
+// null. By default they do; when enabled, the "data" key is left out of
+// error responses entirely while "meta" and "errors" remain. Success
+// responses are never affected.
+func SetOmitNullErrorData(enabled bool) {
+	omitNullErrorData.Store(enabled)
+}
+
+// newErrorResponse builds an error envelope honoring SetOmitNullErrorData.
+func newErrorResponse(messages ...string) models.APIResponse {
+	resp := models.NewErrorResponse(messages...)
+	resp.OmitNullData = omitNullErrorData.Load()
+	return resp
+}
+
 // writeError writes an error API response with the standard envelope.
 func writeError(w http.ResponseWriter, status int, messages ...string) {
-	writeJSON(w, status, models.NewErrorResponse(messages...))
+	writeJSON(w, status, newErrorResponse(messages...))
+}
+
+// writeInternalError writes a 500 response for an unexpected error. The
+// underlying error is never shown to the client; instead, a fresh incident
+// ID is logged alongside the error and returned in meta.incident_id, so a
+// user reporting a problem can quote an ID that points at the exact log line.
+func writeInternalError(w http.ResponseWriter, err error) {
+	incidentID := logInternalError(err)
+
+	resp := newErrorResponse("internal server error")
+	resp.Meta["incident_id"] = incidentID.String()
+	writeJSON(w, http.StatusInternalServerError, resp)
+}
+
+// logInternalError logs err under a fresh incident ID and returns the ID.
+// It is the logging half of writeInternalError, for when no response can
+// be written.
+func logInternalError(err error) uuid.UUID {
+	incidentID := uuid.New()
+	slog.Error("internal error", "incident_id", incidentID.String(), "error", err)
+	return incidentID
+}
+
+// requireJSONContentType is set by SetRequireJSONContentType. Like
+// omitNullErrorData, it is an atomic.Bool because every handler goroutine
+// reads it.
+var requireJSONContentType atomic.Bool
+
+// SetRequireJSONContentType controls whether request bodies with no
+// Content-Type header are rejected with 415. By default they are accepted
+// and decoded as JSON, for older clients that never set the header; a
+// Content-Type other than application/json is rejected either way.
+func SetRequireJSONContentType(required bool) {
+	requireJSONContentType.Store(required)
+}
+
+// maxBodyBytes is the largest request body decodeJSONBody will read. Every
+// request this API accepts is a small JSON object, so 1 MiB leaves plenty of
+// room while stopping a client from making the server buffer an arbitrarily
+// large body.
+const maxBodyBytes = 1 << 20
+
+// hasJSONContentType reports whether r's Content-Type allows decoding its
+// body as JSON; see decodeJSONBody.
+func hasJSONContentType(r *http.Request) bool {
+	contentType := r.Header.Get("Content-Type")
+	if contentType == "" {
+		return !requireJSONContentType.Load()
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && mediaType == "application/json"
+}
+
+// decodeJSONBody reads the request body and decodes it as JSON into dst. On
+// failure it writes the error response itself and returns false, so callers
+// can simply do:
+//
+//	if !decodeJSONBody(w, r, &req) {
+//		return
+//	}
+//
+// A body larger than maxBodyBytes gets 413 without being read in full.
+//
+// When the client sends a Content-Length header, the number of bytes actually
+// read must match it exactly; a short or oversized body gets 400. This stops
+// a client that declares one length and then truncates the body from having
+// a partial payload silently accepted. (Clients that trickle a body in
+// slowly are cut off by the server's read timeouts, set in main.go.)
+// r.ContentLength is -1 when the header is absent (e.g. chunked encoding),
+// in which case there is nothing to compare against.
+//
+// A body that has the right length but isn't valid JSON gets 422, matching
+// the rest of the API's validation errors.
+//
+// Before any of that, the Content-Type must be application/json (with any
+// parameters, such as a charset); anything else, such as a form post, gets
+// 415 rather than a confusing 422 about invalid JSON. A request with no
+// Content-Type is accepted as JSON unless SetRequireJSONContentType is on.
+func decodeJSONBody(w http.ResponseWriter, r *http.Request, dst any) bool {
+	if !hasJSONContentType(r) {
+		writeError(w, http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+		return false
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("request body must be at most %d bytes", maxBodyBytes))
+			return false
+		}
+		// The server reports a body cut short of its Content-Length as
+		// io.ErrUnexpectedEOF.
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			writeError(w, http.StatusBadRequest, "request body does not match Content-Length")
+			return false
+		}
+		writeError(w, http.StatusBadRequest, "could not read request body")
+		return false
+	}
+	if r.ContentLength >= 0 && int64(len(body)) != r.ContentLength {
+		writeError(w, http.StatusBadRequest, "request body does not match Content-Length")
+		return false
+	}
+
+	if err := json.Unmarshal(body, dst); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, "invalid JSON in request body")
+		return false
+	}
+	return true
+}
+
+// queryParamError describes a missing or malformed query parameter. Its
+// message is written so a handler can pass it straight to the client, which
+// keeps the wording identical across endpoints.
+type queryParamError struct {
+	Name    string // The query parameter, e.g. "user_id".
+	Missing bool   // True if the parameter was absent or empty.
+	Want    string // What a valid value looks like, e.g. "a valid UUID".
+}
+
+// Error implements the error interface.
+func (e *queryParamError) Error() string {
+	if e.Missing {
+		return fmt.Sprintf("%s query parameter is required", e.Name)
+	}
+	return fmt.Sprintf("%s must be %s", e.Name, e.Want)
+}
+
+// queryUUID reads a required UUID query parameter. It returns a
+// *queryParamError if the parameter is missing or isn't a UUID.
+func queryUUID(r *http.Request, name string) (uuid.UUID, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return uuid.Nil, &queryParamError{Name: name, Missing: true}
+	}
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		return uuid.Nil, &queryParamError{Name: name, Want: "a valid UUID"}
+	}
+	return id, nil
+}
+
+// queryInt reads an optional integer query parameter, returning def when it
+// is absent. It returns a *queryParamError if the value isn't an integer;
+// range checks are left to the caller, since they differ per parameter.
+func queryInt(r *http.Request, name string, def int) (int, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, &queryParamError{Name: name, Want: "an integer"}
+	}
+	return n, nil
+}
+
+// contentETag returns a strong ETag for v: a quoted hash of its JSON
+// encoding. Any change to a serialized field changes the tag, so clients
+// can cache a resource and revalidate it with If-None-Match.
+func contentETag(v any) (string, error) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(body)
+	// Half the digest is plenty to tell versions apart and keeps the
+	// header short.
+	return `"` + hex.EncodeToString(sum[:16]) + `"`, nil
+}
+
+// etagMatches reports whether an If-None-Match header value matches etag.
+// The header may list several tags separated by commas, or be "*" to match
+// any current version. As RFC 9110 requires for If-None-Match, the
+// comparison is weak: a W/ prefix on either side is ignored.
+func etagMatches(ifNoneMatch, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,338 @@
+// This file contains tests for the shared request/response helpers, in
+// particular decodeJSONBody's Content-Type and Content-Length enforcement,
+// the query parameter parsers, and the error envelope's null-data setting.
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dlfelps/tinder-go-claude/internal/models"
+	"github.com/google/uuid"
+)
+
+func TestDecodeJSONBody_ContentLengthMismatch(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	body, err := json.Marshal(models.CreateUserRequest{
+		Name: "Alice", Age: 25, Gender: "female", ZoneID: "zone-a",
+	})
+	if err != nil {
+		t.Fatalf("failed to marshal body: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		contentLength int64
+		wantStatus    int
+	}{
+		{"matching length", int64(len(body)), http.StatusCreated},
+		{"declared shorter than body", int64(len(body)) - 5, http.StatusBadRequest},
+		{"declared longer than body", int64(len(body)) + 10, http.StatusBadRequest},
+		{"no Content-Length header", -1, http.StatusCreated},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/users/", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			// httptest.NewRequest fills in ContentLength from the reader;
+			// overwrite it to simulate a client lying about the length.
+			req.ContentLength = tc.contentLength
+
+			rr := httptest.NewRecorder()
+			mux.ServeHTTP(rr, req)
+
+			if rr.Code != tc.wantStatus {
+				t.Errorf("status: got %d, want %d (body: %s)", rr.Code, tc.wantStatus, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestDecodeJSONBody_TooLarge(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	tests := []struct {
+		name       string
+		size       int
+		wantStatus int
+	}{
+		// Both bodies are invalid JSON, so one that is read gets 422.
+		{"at the limit", maxBodyBytes, http.StatusUnprocessableEntity},
+		{"over the limit", maxBodyBytes + 1, http.StatusRequestEntityTooLarge},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/swipe", bytes.NewReader(bytes.Repeat([]byte("x"), tc.size)))
+			req.Header.Set("Content-Type", "application/json")
+			rr := httptest.NewRecorder()
+			mux.ServeHTTP(rr, req)
+
+			if rr.Code != tc.wantStatus {
+				t.Errorf("status: got %d, want %d", rr.Code, tc.wantStatus)
+			}
+		})
+	}
+}
+
+func TestDecodeJSONBody_InvalidJSONStill422(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	req := httptest.NewRequest("POST", "/swipe", bytes.NewReader([]byte("{not json")))
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusUnprocessableEntity {
+		t.Errorf("status: got %d, want %d", rr.Code, http.StatusUnprocessableEntity)
+	}
+}
+
+func TestDecodeJSONBody_ContentType(t *testing.T) {
+	mux := setupTestRouter(t)
+	// The setting is package-wide, so restore the default for later tests.
+	t.Cleanup(func() { SetRequireJSONContentType(false) })
+
+	tests := []struct {
+		name        string
+		path        string
+		contentType string
+		require     bool
+		wantStatus  int
+	}{
+		{"create user with text/plain", "/users/", "text/plain", false, http.StatusUnsupportedMediaType},
+		{"swipe with text/plain", "/swipe", "text/plain", false, http.StatusUnsupportedMediaType},
+		{"swipe with a form post", "/swipe", "application/x-www-form-urlencoded", false, http.StatusUnsupportedMediaType},
+		{"JSON with a charset", "/users/", "application/json; charset=utf-8", false, http.StatusCreated},
+		{"missing type accepted by default", "/users/", "", false, http.StatusCreated},
+		{"missing type rejected when required", "/users/", "", true, http.StatusUnsupportedMediaType},
+		{"JSON accepted when required", "/users/", "Application/JSON", true, http.StatusCreated},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			SetRequireJSONContentType(tc.require)
+
+			body, err := json.Marshal(models.CreateUserRequest{
+				Name: "Alice", Age: 25, Gender: "female", ZoneID: "zone-a",
+			})
+			if err != nil {
+				t.Fatalf("failed to marshal body: %v", err)
+			}
+			req := httptest.NewRequest("POST", tc.path, bytes.NewReader(body))
+			if tc.contentType != "" {
+				req.Header.Set("Content-Type", tc.contentType)
+			}
+			rr := httptest.NewRecorder()
+			mux.ServeHTTP(rr, req)
+
+			if rr.Code != tc.wantStatus {
+				t.Errorf("status: got %d, want %d (body: %s)", rr.Code, tc.wantStatus, rr.Body.String())
+			}
+		})
+	}
+}
+
+// TestDecodeJSONBody_TruncatedBodyOverNetwork sends a raw HTTP request over
+// a real connection that declares more bytes than it delivers, which is what
+// a stalled or truncated upload looks like to the server.
+func TestDecodeJSONBody_TruncatedBodyOverNetwork(t *testing.T) {
+	server := httptest.NewServer(setupTestRouter(t))
+	defer server.Close()
+
+	conn, err := net.Dial("tcp", server.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+
+	partial := `{"name":"Alice"`
+	fmt.Fprintf(conn, "POST /users/ HTTP/1.1\r\nHost: test\r\nContent-Type: application/json\r\nContent-Length: %d\r\n\r\n%s",
+		len(partial)+50, partial)
+	// Closing our write side makes the server see EOF before the declared
+	// length has arrived.
+	if err := conn.(*net.TCPConn).CloseWrite(); err != nil {
+		t.Fatalf("close write: %v", err)
+	}
+
+	resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	if err != nil {
+		t.Fatalf("read response: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("status: got %d, want %d", resp.StatusCode, http.StatusBadRequest)
+	}
+}
+
+func TestQueryUUID(t *testing.T) {
+	id := uuid.New()
+
+	tests := []struct {
+		name    string
+		query   string
+		want    uuid.UUID
+		wantErr string // Empty means no error expected.
+	}{
+		{"missing", "", uuid.Nil, "user_id query parameter is required"},
+		{"empty", "user_id=", uuid.Nil, "user_id query parameter is required"},
+		{"invalid", "user_id=not-a-uuid", uuid.Nil, "user_id must be a valid UUID"},
+		{"valid", "user_id=" + id.String(), id, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/feed?"+tt.query, nil)
+			got, err := queryUUID(r, "user_id")
+
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if got != tt.want {
+					t.Errorf("expected %s, got %s", tt.want, got)
+				}
+				return
+			}
+
+			var paramErr *queryParamError
+			if !errors.As(err, &paramErr) {
+				t.Fatalf("expected *queryParamError, got %v", err)
+			}
+			if paramErr.Name != "user_id" {
+				t.Errorf("expected Name user_id, got %q", paramErr.Name)
+			}
+			if err.Error() != tt.wantErr {
+				t.Errorf("expected message %q, got %q", tt.wantErr, err.Error())
+			}
+		})
+	}
+}
+
+func TestQueryInt(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    int
+		wantErr bool
+	}{
+		{"missing uses default", "", 20, false},
+		{"empty uses default", "limit=", 20, false},
+		{"valid", "limit=5", 5, false},
+		{"negative is parsed", "limit=-3", -3, false},
+		{"invalid", "limit=ten", 0, true},
+		{"float", "limit=1.5", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/feed?"+tt.query, nil)
+			got, err := queryInt(r, "limit", 20)
+
+			if tt.wantErr {
+				var paramErr *queryParamError
+				if !errors.As(err, &paramErr) {
+					t.Fatalf("expected *queryParamError, got %v", err)
+				}
+				if paramErr.Missing {
+					t.Error("expected Missing to be false for a malformed value")
+				}
+				if err.Error() != "limit must be an integer" {
+					t.Errorf("unexpected message %q", err.Error())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestETagMatches(t *testing.T) {
+	const etag = `"abc123"`
+	tests := []struct {
+		name        string
+		ifNoneMatch string
+		want        bool
+	}{
+		{"exact", `"abc123"`, true},
+		{"different", `"def456"`, false},
+		{"in a list", `"def456", "abc123"`, true},
+		{"wildcard", "*", true},
+		{"weak", `W/"abc123"`, true},
+		{"unquoted", "abc123", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := etagMatches(tt.ifNoneMatch, etag); got != tt.want {
+				t.Errorf("etagMatches(%q): got %v, want %v", tt.ifNoneMatch, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrorEnvelope_NullDataOmission(t *testing.T) {
+	mux := setupTestRouter(t)
+	// The setting is package-wide, so restore the default for later tests.
+	t.Cleanup(func() { SetOmitNullErrorData(false) })
+
+	tests := []struct {
+		name     string
+		omit     bool
+		wantData bool
+	}{
+		{"default keeps data null", false, true},
+		{"omission drops data", true, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			SetOmitNullErrorData(tc.omit)
+
+			rr := doRequest(t, mux, "GET", "/users/"+uuid.New().String(), nil)
+			if rr.Code != http.StatusNotFound {
+				t.Fatalf("status: got %d, want %d", rr.Code, http.StatusNotFound)
+			}
+
+			// Decode into a map to see exactly which keys were sent.
+			var raw map[string]json.RawMessage
+			if err := json.Unmarshal(rr.Body.Bytes(), &raw); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			data, hasData := raw["data"]
+			if hasData != tc.wantData {
+				t.Errorf("data key present: got %v, want %v (body %s)", hasData, tc.wantData, rr.Body)
+			}
+			if hasData && string(data) != "null" {
+				t.Errorf("expected data to be null, got %s", data)
+			}
+			if _, ok := raw["meta"]; !ok {
+				t.Error("expected meta to be present")
+			}
+			if string(raw["errors"]) != `[{"message":"user not found"}]` {
+				t.Errorf("unexpected errors: %s", raw["errors"])
+			}
+		})
+	}
+
+	// Success responses always carry data, whatever the setting.
+	SetOmitNullErrorData(true)
+	rr := doRequest(t, mux, "GET", "/", nil)
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(rr.Body.Bytes(), &raw); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if _, ok := raw["data"]; !ok {
+		t.Errorf("expected success response to keep data, got %s", rr.Body)
+	}
+}
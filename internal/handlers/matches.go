@@ -0,0 +1,223 @@
+// This file contains HTTP handlers for individual matches:
+//   - GET    /matches/{id} — Retrieve a match by its ID
+//   - DELETE /matches/{id} — Delete (unmatch) a match by its ID
+//   - POST   /matches/{id}/seen — Mark a match seen for one of its users
+//   - GET    /matches/check?user_a=<uuid>&user_b=<uuid> — The match between two users
+//   - GET    /matches/trail?user_id=<uuid>&other_user_id=<uuid> — How a match came about
+//   - GET    /matches/recent?limit=<n> — Anonymized newest matches platform-wide
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/dlfelps/tinder-go-claude/internal/models"
+	"github.com/dlfelps/tinder-go-claude/internal/store"
+	"github.com/google/uuid"
+)
+
+// MatchHandler handles requests that address a single match by ID.
+type MatchHandler struct {
+	store *store.InMemoryStore
+}
+
+// NewMatchHandler creates a new MatchHandler with the given store.
+func NewMatchHandler(s *store.InMemoryStore) *MatchHandler {
+	return &MatchHandler{store: s}
+}
+
+// GetMatch handles GET /matches/{id} — returns a single match record.
+func (h *MatchHandler) GetMatch(w http.ResponseWriter, r *http.Request) {
+	matchID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, "match not found")
+		return
+	}
+
+	match, exists := h.store.GetMatchByID(matchID)
+	if !exists {
+		writeError(w, http.StatusNotFound, "match not found")
+		return
+	}
+
+	writeSuccess(w, http.StatusOK, match, nil)
+}
+
+// DeleteMatch handles DELETE /matches/{id} — removes the match. On success
+// it responds 204 No Content, which by definition carries no body.
+func (h *MatchHandler) DeleteMatch(w http.ResponseWriter, r *http.Request) {
+	matchID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, "match not found")
+		return
+	}
+
+	if !h.store.RemoveMatch(matchID) {
+		writeError(w, http.StatusNotFound, "match not found")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// MarkMatchSeen handles POST /matches/{id}/seen — records that the user in
+// the body has seen the match, so GET /matches reports it with seen=true for
+// them. The partner's flag is unaffected. It responds 204 on success, and
+// 404 if the match doesn't exist or the user isn't part of it.
+func (h *MatchHandler) MarkMatchSeen(w http.ResponseWriter, r *http.Request) {
+	matchID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, "match not found")
+		return
+	}
+
+	var req models.MarkMatchSeenRequest
+	if !decodeJSONBody(w, r, &req) {
+		return
+	}
+
+	userID, errs := req.Validate()
+	if len(errs) > 0 {
+		writeError(w, http.StatusUnprocessableEntity, errs...)
+		return
+	}
+
+	if !h.store.MarkMatchSeen(matchID, userID) {
+		writeError(w, http.StatusNotFound, "match not found")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CheckMatch handles GET /matches/check?user_a=<uuid>&user_b=<uuid> —
+// returns the match between two users, in either order, so a client can
+// confirm they are matched. It responds 404 if either user doesn't exist or
+// they aren't matched.
+func (h *MatchHandler) CheckMatch(w http.ResponseWriter, r *http.Request) {
+	userA, err := queryUUID(r, "user_a")
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	userB, err := queryUUID(r, "user_b")
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	for _, id := range []uuid.UUID{userA, userB} {
+		if _, exists := h.store.GetUser(id); !exists {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("user %s not found", id))
+			return
+		}
+	}
+
+	match := h.store.FindMatch(userA, userB)
+	if match == nil {
+		writeError(w, http.StatusNotFound, "users are not matched")
+		return
+	}
+	writeSuccess(w, http.StatusOK, match, nil)
+}
+
+// GetMatchTrail handles GET /matches/trail?user_id=<uuid>&other_user_id=<uuid>
+// — returns the match between two users together with the two LIKEs that
+// created it, for investigating match disputes. It responds 404 if the two
+// users aren't matched.
+//
+// ServeMux prefers the most specific pattern, so "/matches/trail" wins over
+// "/matches/{id}" and "trail" is never parsed as a match ID.
+func (h *MatchHandler) GetMatchTrail(w http.ResponseWriter, r *http.Request) {
+	userID, err := queryUUID(r, "user_id")
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	otherID, err := queryUUID(r, "other_user_id")
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	match := h.store.FindMatch(userID, otherID)
+	if match == nil {
+		writeError(w, http.StatusNotFound, "users are not matched")
+		return
+	}
+	trail := models.MatchTrail{Match: *match}
+
+	// Collect the LIKE each user sent the other.
+	trail.Swipes = []models.Swipe{}
+	for _, pair := range [][2]uuid.UUID{{userID, otherID}, {otherID, userID}} {
+		if like := h.findLike(pair[0], pair[1], match.Timestamp); like != nil {
+			trail.Swipes = append(trail.Swipes, *like)
+		}
+	}
+	sort.SliceStable(trail.Swipes, func(i, j int) bool {
+		return trail.Swipes[i].Timestamp.Before(trail.Swipes[j].Timestamp)
+	})
+
+	writeSuccess(w, http.StatusOK, trail, nil)
+}
+
+// findLike returns the latest LIKE from swiperID to swipedID made at or
+// before the given match time, or nil. A pair that was liked, passed, and
+// liked again, or unmatched and rematched, is matched by the latest LIKE,
+// not the first one.
+func (h *MatchHandler) findLike(swiperID, swipedID uuid.UUID, matchedAt time.Time) *models.Swipe {
+	for _, swipe := range h.store.GetSwipesByUserSorted(swiperID, true) {
+		if swipe.SwipedID == swipedID && swipe.Action == models.SwipeActionLike && !swipe.Timestamp.After(matchedAt) {
+			return &swipe
+		}
+	}
+	return nil
+}
+
+// Page sizes for GET /matches/recent.
+const (
+	defaultRecentMatchesLimit = 20
+	maxRecentMatchesLimit     = 100
+)
+
+// GetRecentMatches handles GET /matches/recent?limit=<n> — returns the
+// newest matches across the whole platform, for a "love is in the air"
+// ticker. Anyone can call it, so each match is reduced to the zones and
+// time it happened in: no names, user IDs, or match IDs.
+func (h *MatchHandler) GetRecentMatches(w http.ResponseWriter, r *http.Request) {
+	limit, err := queryInt(r, "limit", defaultRecentMatchesLimit)
+	if err != nil || limit < 1 || limit > maxRecentMatchesLimit {
+		writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("limit must be an integer between 1 and %d", maxRecentMatchesLimit))
+		return
+	}
+
+	matches := h.store.GetAllMatches()
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Timestamp.After(matches[j].Timestamp)
+	})
+	matches = matches[:min(limit, len(matches))]
+
+	// Look up every participant at once for their zones.
+	ids := make([]uuid.UUID, 0, 2*len(matches))
+	for _, match := range matches {
+		ids = append(ids, match.User1ID, match.User2ID)
+	}
+	users := h.store.GetUsers(ids)
+
+	recent := make([]models.RecentMatch, 0, len(matches))
+	for _, match := range matches {
+		zones := []string{}
+		for _, id := range []uuid.UUID{match.User1ID, match.User2ID} {
+			if user, ok := users[id]; ok && !slices.Contains(zones, user.ZoneID) {
+				zones = append(zones, user.ZoneID)
+			}
+		}
+		slices.Sort(zones)
+		recent = append(recent, models.RecentMatch{Zones: zones, MatchedAt: match.Timestamp})
+	}
+
+	writeSuccess(w, http.StatusOK, recent, map[string]any{"count": len(recent)})
+}
@@ -0,0 +1,453 @@
+// This file contains tests for the single-match endpoints and match list
+// paging.
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dlfelps/tinder-go-claude/internal/models"
+	"github.com/dlfelps/tinder-go-claude/internal/store"
+	"github.com/google/uuid"
+)
+
+// createTestMatch makes two users LIKE each other through the API and
+// returns the resulting match ID.
+func createTestMatch(t *testing.T, mux http.Handler, a, b uuid.UUID) string {
+	t.Helper()
+
+	doRequest(t, mux, "POST", "/swipe", models.CreateSwipeRequest{
+		SwiperID: a.String(), SwipedID: b.String(), Action: "LIKE",
+	})
+	rr := doRequest(t, mux, "POST", "/swipe", models.CreateSwipeRequest{
+		SwiperID: b.String(), SwipedID: a.String(), Action: "LIKE",
+	})
+
+	data := parseResponse(t, rr).Data.(map[string]interface{})
+	match, ok := data["match"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected a match between %s and %s", a, b)
+	}
+	return match["id"].(string)
+}
+
+func TestMatchIDs_AreUnique(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+	bobID, _ := createTestUser(t, mux, "Bob", "male", "zone-a", 30)
+	charlieID, _ := createTestUser(t, mux, "Charlie", "male", "zone-a", 25)
+
+	first := createTestMatch(t, mux, aliceID, bobID)
+	second := createTestMatch(t, mux, aliceID, charlieID)
+
+	if _, err := uuid.Parse(first); err != nil {
+		t.Errorf("match id is not a UUID: %q", first)
+	}
+	if first == second {
+		t.Errorf("expected distinct match ids, both were %s", first)
+	}
+}
+
+func TestGetMatch_ByID(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+	bobID, _ := createTestUser(t, mux, "Bob", "male", "zone-a", 30)
+	matchID := createTestMatch(t, mux, aliceID, bobID)
+
+	rr := doRequest(t, mux, "GET", "/matches/"+matchID, nil)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want %d", rr.Code, http.StatusOK)
+	}
+
+	data := parseResponse(t, rr).Data.(map[string]interface{})
+	if data["id"] != matchID {
+		t.Errorf("id: got %v, want %s", data["id"], matchID)
+	}
+}
+
+func TestGetMatch_NotFound(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	for _, id := range []string{uuid.New().String(), "not-a-uuid"} {
+		rr := doRequest(t, mux, "GET", "/matches/"+id, nil)
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("%s: status got %d, want %d", id, rr.Code, http.StatusNotFound)
+		}
+	}
+}
+
+func TestDeleteMatch_ByID(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+	bobID, _ := createTestUser(t, mux, "Bob", "male", "zone-a", 30)
+	matchID := createTestMatch(t, mux, aliceID, bobID)
+
+	rr := doRequest(t, mux, "DELETE", "/matches/"+matchID, nil)
+	if rr.Code != http.StatusNoContent {
+		t.Fatalf("status: got %d, want %d", rr.Code, http.StatusNoContent)
+	}
+
+	// The match is gone from both lookups.
+	if rr := doRequest(t, mux, "GET", "/matches/"+matchID, nil); rr.Code != http.StatusNotFound {
+		t.Errorf("GET after delete: got %d, want %d", rr.Code, http.StatusNotFound)
+	}
+	rr = doRequest(t, mux, "GET", fmt.Sprintf("/matches?user_id=%s", aliceID), nil)
+	if data := parseResponse(t, rr).Data.([]interface{}); len(data) != 0 {
+		t.Errorf("expected no matches for Alice after delete, got %d", len(data))
+	}
+
+	// Deleting again reports not found.
+	if rr := doRequest(t, mux, "DELETE", "/matches/"+matchID, nil); rr.Code != http.StatusNotFound {
+		t.Errorf("second delete: got %d, want %d", rr.Code, http.StatusNotFound)
+	}
+}
+
+func TestCheckMatch(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+	bobID, _ := createTestUser(t, mux, "Bob", "male", "zone-a", 30)
+	matchID := createTestMatch(t, mux, aliceID, bobID)
+
+	// The order of the two users doesn't matter.
+	for _, pair := range [][2]uuid.UUID{{aliceID, bobID}, {bobID, aliceID}} {
+		rr := doRequest(t, mux, "GET", fmt.Sprintf("/matches/check?user_a=%s&user_b=%s", pair[0], pair[1]), nil)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("status: got %d, want %d", rr.Code, http.StatusOK)
+		}
+		data := parseResponse(t, rr).Data.(map[string]interface{})
+		if data["id"] != matchID {
+			t.Errorf("match id: got %v, want %s", data["id"], matchID)
+		}
+		if data["timestamp"] == nil {
+			t.Error("expected the match timestamp")
+		}
+	}
+}
+
+func TestCheckMatch_Errors(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+	bobID, _ := createTestUser(t, mux, "Bob", "male", "zone-a", 30)
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+	}{
+		{"not matched", fmt.Sprintf("user_a=%s&user_b=%s", aliceID, bobID), http.StatusNotFound},
+		{"unknown user", fmt.Sprintf("user_a=%s&user_b=%s", aliceID, uuid.New()), http.StatusNotFound},
+		{"missing user_b", fmt.Sprintf("user_a=%s", aliceID), http.StatusUnprocessableEntity},
+		{"invalid user_a", fmt.Sprintf("user_a=nope&user_b=%s", bobID), http.StatusUnprocessableEntity},
+		{"invalid user_b", fmt.Sprintf("user_a=%s&user_b=nope", aliceID), http.StatusUnprocessableEntity},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rr := doRequest(t, mux, "GET", "/matches/check?"+tc.query, nil)
+			if rr.Code != tc.wantStatus {
+				t.Errorf("status: got %d, want %d", rr.Code, tc.wantStatus)
+			}
+		})
+	}
+}
+
+func TestGetMatchTrail(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+	bobID, _ := createTestUser(t, mux, "Bob", "male", "zone-a", 30)
+
+	// Store the swipes directly with fixed timestamps: Alice likes first.
+	s := store.GetStore()
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	s.AddSwipe(models.Swipe{SwiperID: aliceID, SwipedID: bobID, Action: models.SwipeActionLike, Timestamp: base})
+	s.AddSwipe(models.Swipe{SwiperID: bobID, SwipedID: aliceID, Action: models.SwipeActionLike, Timestamp: base.Add(time.Hour)})
+	match := models.Match{ID: uuid.New(), User1ID: bobID, User2ID: aliceID, Timestamp: base.Add(time.Hour)}
+	s.AddMatch(match)
+	matchID := match.ID.String()
+
+	// Ask from Bob's side to show the order follows time, not the query.
+	rr := doRequest(t, mux, "GET", fmt.Sprintf("/matches/trail?user_id=%s&other_user_id=%s", bobID, aliceID), nil)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want %d", rr.Code, http.StatusOK)
+	}
+
+	data := parseResponse(t, rr).Data.(map[string]interface{})
+	if id := data["match"].(map[string]interface{})["id"]; id != matchID {
+		t.Errorf("match id: got %v, want %s", id, matchID)
+	}
+
+	swipes := data["swipes"].([]interface{})
+	if len(swipes) != 2 {
+		t.Fatalf("expected 2 swipes, got %d", len(swipes))
+	}
+	first := swipes[0].(map[string]interface{})
+	second := swipes[1].(map[string]interface{})
+	if first["swiper_id"] != aliceID.String() || second["swiper_id"] != bobID.String() {
+		t.Errorf("expected Alice's like then Bob's, got %v then %v", first["swiper_id"], second["swiper_id"])
+	}
+	for _, swipe := range []map[string]interface{}{first, second} {
+		if swipe["action"] != "LIKE" {
+			t.Errorf("expected LIKE, got %v", swipe["action"])
+		}
+	}
+}
+
+func TestGetMatchTrail_ReLikedPair(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+	bobID, _ := createTestUser(t, mux, "Bob", "male", "zone-a", 30)
+
+	// Alice likes, passes, then likes Bob again; Bob's like makes the
+	// match. A later like by Alice, after the match, isn't part of it.
+	s := store.GetStore()
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	s.AddSwipe(models.Swipe{SwiperID: aliceID, SwipedID: bobID, Action: models.SwipeActionLike, Timestamp: base})
+	s.AddSwipe(models.Swipe{SwiperID: aliceID, SwipedID: bobID, Action: models.SwipeActionPass, Timestamp: base.Add(time.Hour)})
+	relike := base.Add(2 * time.Hour)
+	s.AddSwipe(models.Swipe{SwiperID: aliceID, SwipedID: bobID, Action: models.SwipeActionLike, Timestamp: relike})
+	s.AddSwipe(models.Swipe{SwiperID: bobID, SwipedID: aliceID, Action: models.SwipeActionLike, Timestamp: base.Add(3 * time.Hour)})
+	s.AddMatch(models.Match{ID: uuid.New(), User1ID: bobID, User2ID: aliceID, Timestamp: base.Add(3 * time.Hour)})
+	s.AddSwipe(models.Swipe{SwiperID: aliceID, SwipedID: bobID, Action: models.SwipeActionLike, Timestamp: base.Add(4 * time.Hour)})
+
+	rr := doRequest(t, mux, "GET", fmt.Sprintf("/matches/trail?user_id=%s&other_user_id=%s", aliceID, bobID), nil)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want %d", rr.Code, http.StatusOK)
+	}
+
+	swipes := parseResponse(t, rr).Data.(map[string]interface{})["swipes"].([]interface{})
+	if len(swipes) != 2 {
+		t.Fatalf("expected 2 swipes, got %d", len(swipes))
+	}
+	first := swipes[0].(map[string]interface{})
+	if first["swiper_id"] != aliceID.String() {
+		t.Fatalf("expected Alice's like first, got %v", first["swiper_id"])
+	}
+	if got := first["timestamp"]; got != relike.Format(time.RFC3339) {
+		t.Errorf("Alice's like: got timestamp %v, want %s", got, relike.Format(time.RFC3339))
+	}
+}
+
+func TestGetMatchTrail_Errors(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+	bobID, _ := createTestUser(t, mux, "Bob", "male", "zone-a", 30)
+	// Only one side likes, so there's no match.
+	doRequest(t, mux, "POST", "/swipe", models.CreateSwipeRequest{
+		SwiperID: aliceID.String(), SwipedID: bobID.String(), Action: "LIKE",
+	})
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantError  string
+	}{
+		{"not matched", fmt.Sprintf("user_id=%s&other_user_id=%s", aliceID, bobID), http.StatusNotFound, "users are not matched"},
+		{"missing other_user_id", fmt.Sprintf("user_id=%s", aliceID), http.StatusUnprocessableEntity, "other_user_id query parameter is required"},
+		{"invalid user_id", fmt.Sprintf("user_id=nope&other_user_id=%s", bobID), http.StatusUnprocessableEntity, "user_id must be a valid UUID"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rr := doRequest(t, mux, "GET", "/matches/trail?"+tc.query, nil)
+			if rr.Code != tc.wantStatus {
+				t.Errorf("status: got %d, want %d", rr.Code, tc.wantStatus)
+			}
+			if errs := parseResponse(t, rr).Errors; len(errs) != 1 || errs[0].Message != tc.wantError {
+				t.Errorf("errors: got %v, want [%s]", errs, tc.wantError)
+			}
+		})
+	}
+}
+
+func TestGetMatches_BeforeCursorPaging(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+
+	// Five matches an hour apart, stored directly so the times are fixed.
+	s := store.GetStore()
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	var names []string
+	for i := 0; i < 5; i++ {
+		name := fmt.Sprintf("Match%d", i)
+		otherID, _ := createTestUser(t, mux, name, "male", "zone-a", 30)
+		s.AddMatch(models.Match{ID: uuid.New(), User1ID: aliceID, User2ID: otherID, Timestamp: base.Add(time.Duration(i) * time.Hour)})
+		names = append(names, name)
+	}
+
+	// Walk backwards two at a time; expect newest first, each exactly once.
+	var got []string
+	before := ""
+	for page := 0; page < 5; page++ {
+		path := fmt.Sprintf("/matches?user_id=%s&limit=2&before=%s", aliceID, url.QueryEscape(before))
+		rr := doRequest(t, mux, "GET", path, nil)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("status: got %d, want %d", rr.Code, http.StatusOK)
+		}
+		resp := parseResponse(t, rr)
+		for _, raw := range resp.Data.([]interface{}) {
+			entry := raw.(map[string]interface{})
+			got = append(got, entry["user"].(map[string]interface{})["name"].(string))
+		}
+
+		next, ok := resp.Meta["next_cursor"].(string)
+		if !ok {
+			break
+		}
+		before = next
+	}
+
+	want := []string{names[4], names[3], names[2], names[1], names[0]}
+	if !slices.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestGetMatches_BeforeCursorInvalidInput(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+
+	for _, query := range []string{"&before=yesterday", "&before=&limit=0", "&before=&limit=101"} {
+		rr := doRequest(t, mux, "GET", "/matches?user_id="+aliceID.String()+query, nil)
+		if rr.Code != http.StatusUnprocessableEntity {
+			t.Errorf("%s: status got %d, want %d", query, rr.Code, http.StatusUnprocessableEntity)
+		}
+	}
+
+	rr := doRequest(t, mux, "GET", "/matches?before=&user_id="+uuid.New().String(), nil)
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("unknown user: status got %d, want %d", rr.Code, http.StatusNotFound)
+	}
+}
+
+func TestMarkMatchSeen(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+	bobID, _ := createTestUser(t, mux, "Bob", "male", "zone-a", 30)
+	carolID, _ := createTestUser(t, mux, "Carol", "female", "zone-a", 26)
+	matchID := createTestMatch(t, mux, aliceID, bobID)
+
+	// seenFlag returns the seen flag of the user's only match.
+	seenFlag := func(userID uuid.UUID) bool {
+		t.Helper()
+		rr := doRequest(t, mux, "GET", fmt.Sprintf("/matches?user_id=%s", userID), nil)
+		data := parseResponse(t, rr).Data.([]interface{})
+		if len(data) != 1 {
+			t.Fatalf("expected 1 match, got %d", len(data))
+		}
+		return data[0].(map[string]interface{})["seen"].(bool)
+	}
+
+	if seenFlag(aliceID) || seenFlag(bobID) {
+		t.Fatal("expected a new match to be unseen by both users")
+	}
+
+	rr := doRequest(t, mux, "POST", "/matches/"+matchID+"/seen", models.MarkMatchSeenRequest{UserID: aliceID.String()})
+	if rr.Code != http.StatusNoContent {
+		t.Fatalf("status: got %d, want %d", rr.Code, http.StatusNoContent)
+	}
+
+	if !seenFlag(aliceID) {
+		t.Error("expected the match to be seen by Alice")
+	}
+	if seenFlag(bobID) {
+		t.Error("expected Bob's seen flag to be unaffected")
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			matchID string
+			userID  string
+			want    int
+		}{
+			{"not a participant", matchID, carolID.String(), http.StatusNotFound},
+			{"unknown match", uuid.New().String(), aliceID.String(), http.StatusNotFound},
+			{"invalid match id", "not-a-uuid", aliceID.String(), http.StatusNotFound},
+			{"missing user_id", matchID, "", http.StatusUnprocessableEntity},
+			{"invalid user_id", matchID, "not-a-uuid", http.StatusUnprocessableEntity},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				rr := doRequest(t, mux, "POST", "/matches/"+tt.matchID+"/seen", models.MarkMatchSeenRequest{UserID: tt.userID})
+				if rr.Code != tt.want {
+					t.Errorf("status: got %d, want %d", rr.Code, tt.want)
+				}
+			})
+		}
+	})
+}
+
+func TestGetRecentMatches(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	// Three matches an hour apart, stored directly so the times are fixed.
+	// The last pair live in different zones.
+	s := store.GetStore()
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	pairs := [][2]string{{"zone-a", "zone-a"}, {"zone-b", "zone-b"}, {"zone-c", "zone-a"}}
+	var private []string
+	for i, zones := range pairs {
+		aID, _ := createTestUser(t, mux, fmt.Sprintf("Alpha%d", i), "female", zones[0], 28)
+		bID, _ := createTestUser(t, mux, fmt.Sprintf("Beta%d", i), "male", zones[1], 30)
+		match := models.Match{ID: uuid.New(), User1ID: aID, User2ID: bID, Timestamp: base.Add(time.Duration(i) * time.Hour)}
+		s.AddMatch(match)
+		private = append(private, aID.String(), bID.String(), match.ID.String(), fmt.Sprintf("Alpha%d", i), fmt.Sprintf("Beta%d", i))
+	}
+
+	rr := doRequest(t, mux, "GET", "/matches/recent?limit=2", nil)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want %d", rr.Code, http.StatusOK)
+	}
+
+	// Nothing that identifies a person or a match may appear anywhere.
+	body := rr.Body.String()
+	for _, value := range private {
+		if strings.Contains(body, value) {
+			t.Errorf("response leaks %q: %s", value, body)
+		}
+	}
+
+	data := parseResponse(t, rr).Data.([]interface{})
+	if len(data) != 2 {
+		t.Fatalf("expected 2 matches with limit=2, got %d", len(data))
+	}
+	wantZones := [][]string{{"zone-a", "zone-c"}, {"zone-b"}}
+	for i, raw := range data {
+		entry := raw.(map[string]interface{})
+		if len(entry) != 2 {
+			t.Errorf("entry %d: expected only zones and matched_at, got %v", i, entry)
+		}
+		var zones []string
+		for _, z := range entry["zones"].([]interface{}) {
+			zones = append(zones, z.(string))
+		}
+		if !slices.Equal(zones, wantZones[i]) {
+			t.Errorf("entry %d zones: got %v, want %v", i, zones, wantZones[i])
+		}
+		want := base.Add(time.Duration(2-i) * time.Hour).Format(time.RFC3339)
+		if entry["matched_at"] != want {
+			t.Errorf("entry %d matched_at: got %v, want %s (newest first)", i, entry["matched_at"], want)
+		}
+	}
+
+	for _, query := range []string{"?limit=0", "?limit=101", "?limit=x"} {
+		if rr := doRequest(t, mux, "GET", "/matches/recent"+query, nil); rr.Code != http.StatusUnprocessableEntity {
+			t.Errorf("%s: got %d, want %d", query, rr.Code, http.StatusUnprocessableEntity)
+		}
+	}
+}
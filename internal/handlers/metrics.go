@@ -0,0 +1,193 @@
+// This file contains in-process request metrics for basic monitoring:
+//   - Metrics.Middleware — Counts every request and times it
+//   - GET /metrics — Returns the counters and latency histograms as JSON
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// latencyBucketsMs are the upper bounds, in milliseconds, of the latency
+// histogram buckets. Requests slower than the last bound are counted only in
+// the final "+Inf" bucket.
+var latencyBucketsMs = []float64{5, 10, 25, 50, 100, 250, 500, 1000, 2500}
+
+// Metrics counts requests by status code and keeps a latency histogram per
+// endpoint. A mutex protects the counters: the maps change as new status
+// codes and endpoints appear, which atomics alone can't handle.
+//
+// Endpoints are labeled by the route pattern that served them, such as
+// "GET /users/{id}", not the raw path, so a new user ID doesn't create a new
+// histogram.
+type Metrics struct {
+	mu        sync.Mutex
+	total     int64
+	byStatus  map[int]int64
+	endpoints map[string]*latencyHistogram
+}
+
+// latencyHistogram holds one endpoint's request count, total latency, and
+// per-bucket counts. counts has one entry per latencyBucketsMs bound plus a
+// final overflow bucket; each request lands in exactly one entry.
+type latencyHistogram struct {
+	count  int64
+	sumMs  float64
+	counts []int64
+}
+
+// NewMetrics creates an empty Metrics.
+func NewMetrics() *Metrics {
+	return &Metrics{
+		byStatus:  make(map[int]int64),
+		endpoints: make(map[string]*latencyHistogram),
+	}
+}
+
+// Middleware wraps next so that every request is counted and timed. Wrap it
+// around everything else, including RecoverMiddleware, so that requests that
+// panic are counted as the 500s they turn into.
+func (m *Metrics) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rw := &statusRecordingWriter{ResponseWriter: w, status: http.StatusOK}
+		start := time.Now()
+		next.ServeHTTP(rw, r)
+
+		// ServeMux sets r.Pattern while routing. It is empty when no route
+		// matched (a 405, or a path nothing handles) or when middleware
+		// answered before routing, as read-only mode does.
+		endpoint := r.Pattern
+		if endpoint == "" {
+			endpoint = "unmatched"
+		}
+		m.record(endpoint, rw.status, time.Since(start))
+	})
+}
+
+// record adds one finished request to the counters.
+func (m *Metrics) record(endpoint string, status int, elapsed time.Duration) {
+	ms := float64(elapsed) / float64(time.Millisecond)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.total++
+	m.byStatus[status]++
+
+	h, ok := m.endpoints[endpoint]
+	if !ok {
+		h = &latencyHistogram{counts: make([]int64, len(latencyBucketsMs)+1)}
+		m.endpoints[endpoint] = h
+	}
+	h.count++
+	h.sumMs += ms
+	bucket := len(latencyBucketsMs)
+	for i, bound := range latencyBucketsMs {
+		if ms <= bound {
+			bucket = i
+			break
+		}
+	}
+	h.counts[bucket]++
+}
+
+// MetricsSnapshot is the JSON form of the metrics returned by GET /metrics.
+type MetricsSnapshot struct {
+	RequestsTotal int64 `json:"requests_total"`
+
+	// ResponsesByStatus counts responses per HTTP status code. JSON object
+	// keys are strings, so the codes are too.
+	ResponsesByStatus map[string]int64 `json:"responses_by_status"`
+
+	// Endpoints maps each route pattern to its latency histogram.
+	Endpoints map[string]EndpointLatency `json:"endpoints"`
+}
+
+// EndpointLatency is one endpoint's latency histogram. As in Prometheus,
+// bucket counts are cumulative: each counts the requests that took at most
+// LeMs milliseconds, and the last bucket ("+Inf") equals Count.
+type EndpointLatency struct {
+	Count   int64           `json:"count"`
+	SumMs   float64         `json:"sum_ms"`
+	Buckets []LatencyBucket `json:"buckets"`
+}
+
+// LatencyBucket is one cumulative histogram bucket. LeMs is a string so the
+// final bucket can be "+Inf", which JSON numbers can't express.
+type LatencyBucket struct {
+	LeMs  string `json:"le_ms"`
+	Count int64  `json:"count"`
+}
+
+// Snapshot returns a copy of the current counters.
+func (m *Metrics) Snapshot() MetricsSnapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	snap := MetricsSnapshot{
+		RequestsTotal:     m.total,
+		ResponsesByStatus: make(map[string]int64, len(m.byStatus)),
+		Endpoints:         make(map[string]EndpointLatency, len(m.endpoints)),
+	}
+	for status, count := range m.byStatus {
+		snap.ResponsesByStatus[strconv.Itoa(status)] = count
+	}
+	for endpoint, h := range m.endpoints {
+		latency := EndpointLatency{
+			Count:   h.count,
+			SumMs:   h.sumMs,
+			Buckets: make([]LatencyBucket, 0, len(h.counts)),
+		}
+		var cumulative int64
+		for i, count := range h.counts {
+			cumulative += count
+			le := "+Inf"
+			if i < len(latencyBucketsMs) {
+				le = strconv.FormatFloat(latencyBucketsMs[i], 'f', -1, 64)
+			}
+			latency.Buckets = append(latency.Buckets, LatencyBucket{LeMs: le, Count: cumulative})
+		}
+		snap.Endpoints[endpoint] = latency
+	}
+	return snap
+}
+
+// GetMetrics handles GET /metrics — returns the request counters and
+// latency histograms collected so far. The request for the metrics is
+// itself counted once it finishes, so it isn't in its own response.
+func (m *Metrics) GetMetrics(w http.ResponseWriter, r *http.Request) {
+	writeSuccess(w, http.StatusOK, m.Snapshot(), nil)
+}
+
+// statusRecordingWriter remembers the status code of a response. It starts
+// as 200 because a handler that only calls Write implicitly sends 200 OK.
+type statusRecordingWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+// WriteHeader records the status code of the first call, the one that is
+// actually sent.
+func (w *statusRecordingWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write marks the header as written: a Write without a prior WriteHeader
+// sends 200 OK, and any later WriteHeader is ignored.
+func (w *statusRecordingWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap returns the underlying ResponseWriter, so http.ResponseController
+// can still reach optional interfaces such as http.Flusher.
+func (w *statusRecordingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
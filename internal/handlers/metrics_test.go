@@ -0,0 +1,75 @@
+// This file contains tests for the request metrics middleware and
+// GET /metrics.
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestMetrics_CountsRequests(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	doRequest(t, mux, "GET", "/", nil)
+	doRequest(t, mux, "GET", "/users/"+uuid.New().String(), nil)
+	doRequest(t, mux, "GET", "/users/"+uuid.New().String(), nil)
+
+	rr := doRequest(t, mux, "GET", "/metrics", nil)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want %d", rr.Code, http.StatusOK)
+	}
+	data := parseResponse(t, rr).Data.(map[string]interface{})
+
+	// The metrics request itself is counted only after it responds.
+	if data["requests_total"] != float64(3) {
+		t.Errorf("requests_total: got %v, want 3", data["requests_total"])
+	}
+	statuses := data["responses_by_status"].(map[string]interface{})
+	if len(statuses) != 2 || statuses["200"] != float64(1) || statuses["404"] != float64(2) {
+		t.Errorf("responses_by_status: got %v, want 200: 1 and 404: 2", statuses)
+	}
+
+	// Both user lookups share one histogram, keyed by the route pattern.
+	endpoints := data["endpoints"].(map[string]interface{})
+	users, ok := endpoints["GET /users/{id}"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected a histogram for GET /users/{id}, got %v", endpoints)
+	}
+	if users["count"] != float64(2) {
+		t.Errorf("count: got %v, want 2", users["count"])
+	}
+	buckets := users["buckets"].([]interface{})
+	last := buckets[len(buckets)-1].(map[string]interface{})
+	if last["le_ms"] != "+Inf" || last["count"] != float64(2) {
+		t.Errorf("last bucket: got %v, want +Inf with count 2", last)
+	}
+	if len(endpoints) != 2 {
+		t.Errorf("expected histograms for 2 endpoints, got %v", endpoints)
+	}
+
+	// Now the first metrics request is in.
+	data = parseResponse(t, doRequest(t, mux, "GET", "/metrics", nil)).Data.(map[string]interface{})
+	if data["requests_total"] != float64(4) {
+		t.Errorf("requests_total after GET /metrics: got %v, want 4", data["requests_total"])
+	}
+}
+
+func TestMetrics_CountsPanicsAndUnmatchedRoutes(t *testing.T) {
+	metrics := NewMetrics()
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /boom", func(w http.ResponseWriter, r *http.Request) { panic("boom") })
+	handler := metrics.Middleware(RecoverMiddleware(mux))
+
+	doRequest(t, handler, "GET", "/boom", nil)
+	doRequest(t, handler, "GET", "/nowhere", nil)
+
+	snap := metrics.Snapshot()
+	if snap.RequestsTotal != 2 || snap.ResponsesByStatus["500"] != 1 || snap.ResponsesByStatus["404"] != 1 {
+		t.Errorf("got %+v, want one 500 and one 404", snap)
+	}
+	if snap.Endpoints["GET /boom"].Count != 1 || snap.Endpoints["unmatched"].Count != 1 {
+		t.Errorf("endpoints: got %+v", snap.Endpoints)
+	}
+}
@@ -0,0 +1,166 @@
+// This file contains HTTP middleware. In Go, middleware is simply a function
+// that takes a handler and returns a new handler wrapping it, so it can run
+// code before and/or after the wrapped handler.
+package handlers
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"sync/atomic"
+)
+
+// AdminTokenHeader is the request header carrying the admin token.
+const AdminTokenHeader = "X-Admin-Token"
+
+// RequireAdmin wraps an admin-only handler. Requests must carry the
+// configured token in the X-Admin-Token header; otherwise they receive 403.
+//
+// If token is empty, admin endpoints are disabled entirely (fail closed),
+// so forgetting to configure a token never exposes them.
+func RequireAdmin(token string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// subtle.ConstantTimeCompare takes the same time regardless of where
+		// the strings differ, which avoids leaking the token through timing.
+		provided := r.Header.Get(AdminTokenHeader)
+		if token == "" || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			writeError(w, http.StatusForbidden, "admin access required")
+			return
+		}
+		next(w, r)
+	}
+}
+
+// RequireDebug wraps a development-only handler, such as the demo data
+// seeder. Unless enabled is true, requests get 404, as if the route didn't
+// exist, so production deployments don't advertise it.
+func RequireDebug(enabled bool, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !enabled {
+			writeError(w, http.StatusNotFound, "not found")
+			return
+		}
+		next(w, r)
+	}
+}
+
+// ReadOnlyGuard implements a read-only mode for maintenance windows: reads
+// keep working while every mutating request is refused with 503. This is
+// narrower than a full maintenance mode, which would take the whole API down.
+//
+// The flag is an atomic.Bool, so it can be flipped at runtime from the admin
+// endpoint while requests are being served, without a mutex.
+type ReadOnlyGuard struct {
+	enabled atomic.Bool
+}
+
+// NewReadOnlyGuard creates a guard with read-only mode initially set to
+// the given value.
+func NewReadOnlyGuard(enabled bool) *ReadOnlyGuard {
+	g := &ReadOnlyGuard{}
+	g.enabled.Store(enabled)
+	return g
+}
+
+// Enabled reports whether read-only mode is on.
+func (g *ReadOnlyGuard) Enabled() bool {
+	return g.enabled.Load()
+}
+
+// SetEnabled turns read-only mode on or off.
+func (g *ReadOnlyGuard) SetEnabled(enabled bool) {
+	g.enabled.Store(enabled)
+}
+
+// Middleware wraps next so that, while read-only mode is on, any request
+// that isn't GET, HEAD, or OPTIONS gets a 503. Paths listed in exempt are
+// always let through — at minimum the endpoint that turns the mode off.
+func (g *ReadOnlyGuard) Middleware(next http.Handler, exempt ...string) http.Handler {
+	// Build a set once, so the per-request check is a single map lookup.
+	exemptSet := make(map[string]struct{}, len(exempt))
+	for _, path := range exempt {
+		exemptSet[path] = struct{}{}
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if g.Enabled() && !isSafeMethod(r.Method) {
+			if _, ok := exemptSet[r.URL.Path]; !ok {
+				writeError(w, http.StatusServiceUnavailable, "the service is in read-only mode; writes are temporarily disabled")
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// isSafeMethod reports whether an HTTP method is read-only by definition.
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	default:
+		return false
+	}
+}
+
+// RecoverMiddleware wraps next so that a panic in any handler produces a
+// 500 with the standard error envelope instead of a dropped connection.
+// The panic value and stack trace are logged under an incident ID, exactly
+// like other internal errors (see writeInternalError).
+//
+// If the handler had already started its response before panicking, the
+// status line and headers are on their way to the client and can't be
+// replaced, so the panic is only logged.
+//
+// http.ErrAbortHandler is re-panicked: it is the standard library's way for
+// a handler to abort a response on purpose, and the server handles it.
+func RecoverMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rw := &responseStartedWriter{ResponseWriter: w}
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			if rec == http.ErrAbortHandler {
+				panic(rec)
+			}
+
+			err := fmt.Errorf("panic serving %s %s: %v\n%s", r.Method, r.URL.Path, rec, debug.Stack())
+			if rw.started {
+				logInternalError(err)
+				return
+			}
+			writeInternalError(w, err)
+		}()
+		next.ServeHTTP(rw, r)
+	})
+}
+
+// responseStartedWriter records whether a response has been started, that
+// is, whether WriteHeader or Write has been called. Embedding the
+// http.ResponseWriter interface passes every other method straight through.
+type responseStartedWriter struct {
+	http.ResponseWriter
+	started bool
+}
+
+// WriteHeader records that the response has started.
+func (w *responseStartedWriter) WriteHeader(status int) {
+	w.started = true
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write records that the response has started. A Write without a prior
+// WriteHeader implicitly sends 200 OK.
+func (w *responseStartedWriter) Write(b []byte) (int, error) {
+	w.started = true
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap returns the underlying ResponseWriter, so http.ResponseController
+// can still reach optional interfaces such as http.Flusher.
+func (w *responseStartedWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
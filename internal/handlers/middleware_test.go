@@ -0,0 +1,70 @@
+// This file contains tests for RecoverMiddleware. The guards in
+// middleware.go are tested alongside the endpoints they protect.
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRecoverMiddleware_PanicReturnsErrorEnvelope(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /boom", func(w http.ResponseWriter, r *http.Request) {
+		panic("something went wrong")
+	})
+	mux.HandleFunc("GET /ok", func(w http.ResponseWriter, r *http.Request) {
+		writeSuccess(w, http.StatusOK, "fine", nil)
+	})
+	handler := RecoverMiddleware(mux)
+
+	rr := doRequest(t, handler, "GET", "/boom", nil)
+	if rr.Code != http.StatusInternalServerError {
+		t.Fatalf("status: got %d, want %d", rr.Code, http.StatusInternalServerError)
+	}
+	resp := parseResponse(t, rr)
+	if len(resp.Errors) != 1 || resp.Errors[0].Message != "internal server error" {
+		t.Errorf("expected an internal server error message, got %v", resp.Errors)
+	}
+	if _, ok := resp.Meta["incident_id"].(string); !ok {
+		t.Errorf("expected meta.incident_id, got %v", resp.Meta)
+	}
+
+	// The panic is contained to its request; the next one is served normally.
+	rr = doRequest(t, handler, "GET", "/ok", nil)
+	if rr.Code != http.StatusOK {
+		t.Errorf("status after panic: got %d, want %d", rr.Code, http.StatusOK)
+	}
+}
+
+func TestRecoverMiddleware_StartedResponseIsLeftAlone(t *testing.T) {
+	handler := RecoverMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte("partial"))
+		panic("too late to report")
+	}))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/", nil))
+
+	// The 202 was already sent, so no error envelope is appended.
+	if rr.Code != http.StatusAccepted {
+		t.Errorf("status: got %d, want %d", rr.Code, http.StatusAccepted)
+	}
+	if rr.Body.String() != "partial" {
+		t.Errorf("body: got %q, want %q", rr.Body.String(), "partial")
+	}
+}
+
+func TestRecoverMiddleware_RepanicsErrAbortHandler(t *testing.T) {
+	handler := RecoverMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+
+	defer func() {
+		if rec := recover(); rec != http.ErrAbortHandler {
+			t.Errorf("expected http.ErrAbortHandler to propagate, got %v", rec)
+		}
+	}()
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+}
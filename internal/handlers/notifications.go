@@ -0,0 +1,73 @@
+// This file contains HTTP handlers for notification polling:
+//   - GET /notifications/matches?user_id=<uuid>&since=<RFC3339> — New matches since a cursor
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/dlfelps/tinder-go-claude/internal/services"
+	"github.com/google/uuid"
+)
+
+// NotificationHandler handles notification polling requests.
+type NotificationHandler struct {
+	notificationService *services.NotificationService
+}
+
+// NewNotificationHandler creates a new NotificationHandler with the given
+// notification service.
+func NewNotificationHandler(ns *services.NotificationService) *NotificationHandler {
+	return &NotificationHandler{notificationService: ns}
+}
+
+// GetMatchEvents handles GET /notifications/matches — returns the matches
+// created since the user's last poll (or since the optional `since`
+// timestamp) and advances the user's cursor. During the user's quiet hours
+// nothing is returned and meta.deferred_until says when to poll again.
+func (h *NotificationHandler) GetMatchEvents(w http.ResponseWriter, r *http.Request) {
+	userIDStr := r.URL.Query().Get("user_id")
+	if userIDStr == "" {
+		writeError(w, http.StatusUnprocessableEntity, "user_id query parameter is required")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, "user_id must be a valid UUID")
+		return
+	}
+
+	// `since` is optional. A nil pointer tells the service to use the
+	// stored cursor.
+	var since *time.Time
+	if raw := r.URL.Query().Get("since"); raw != "" {
+		parsed, err := time.Parse(time.RFC3339Nano, raw)
+		if err != nil {
+			writeError(w, http.StatusUnprocessableEntity, "since must be an RFC3339 timestamp")
+			return
+		}
+		since = &parsed
+	}
+
+	batch, err := h.notificationService.DrainMatchEvents(userID, since)
+	if err != nil {
+		var notFoundErr *services.NotFoundError
+		if errors.As(err, &notFoundErr) {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		writeInternalError(w, err)
+		return
+	}
+
+	meta := map[string]any{
+		"count":  len(batch.Matches),
+		"cursor": batch.Cursor.Format(time.RFC3339Nano),
+	}
+	if batch.DeferredUntil != nil {
+		meta["deferred_until"] = batch.DeferredUntil.UTC().Format(time.RFC3339)
+	}
+	writeSuccess(w, http.StatusOK, batch.Matches, meta)
+}
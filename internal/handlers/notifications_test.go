@@ -0,0 +1,72 @@
+// This file contains tests for the notification polling endpoint.
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestGetMatchEvents_DrainsAndAdvances(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+	bobID, _ := createTestUser(t, mux, "Bob", "male", "zone-a", 30)
+	createTestMatch(t, mux, aliceID, bobID)
+
+	path := fmt.Sprintf("/notifications/matches?user_id=%s", aliceID)
+
+	rr := doRequest(t, mux, "GET", path, nil)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want %d", rr.Code, http.StatusOK)
+	}
+	resp := parseResponse(t, rr)
+	if data := resp.Data.([]interface{}); len(data) != 1 {
+		t.Fatalf("expected 1 match event, got %d", len(data))
+	}
+	cursor, ok := resp.Meta["cursor"].(string)
+	if !ok || cursor == "" {
+		t.Fatalf("expected meta.cursor, got %v", resp.Meta["cursor"])
+	}
+
+	// Polling again returns nothing new.
+	rr = doRequest(t, mux, "GET", path, nil)
+	if data := parseResponse(t, rr).Data.([]interface{}); len(data) != 0 {
+		t.Errorf("expected 0 new events, got %d", len(data))
+	}
+
+	// Replaying from the returned cursor also returns nothing.
+	rr = doRequest(t, mux, "GET", path+"&since="+url.QueryEscape(cursor), nil)
+	if data := parseResponse(t, rr).Data.([]interface{}); len(data) != 0 {
+		t.Errorf("expected 0 events after cursor, got %d", len(data))
+	}
+}
+
+func TestGetMatchEvents_Validation(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+
+	tests := []struct {
+		name string
+		path string
+		want int
+	}{
+		{"missing user_id", "/notifications/matches", http.StatusUnprocessableEntity},
+		{"invalid user_id", "/notifications/matches?user_id=nope", http.StatusUnprocessableEntity},
+		{"invalid since", fmt.Sprintf("/notifications/matches?user_id=%s&since=yesterday", aliceID), http.StatusUnprocessableEntity},
+		{"unknown user", fmt.Sprintf("/notifications/matches?user_id=%s", uuid.New()), http.StatusNotFound},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rr := doRequest(t, mux, "GET", tc.path, nil)
+			if rr.Code != tc.want {
+				t.Errorf("status: got %d, want %d", rr.Code, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,17 @@
+// This file contains the handler that publishes the API description:
+//   - GET /openapi.json — The OpenAPI 3 document for every endpoint
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/dlfelps/tinder-go-claude/internal/openapi"
+)
+
+// GetOpenAPISpec handles GET /openapi.json — returns the OpenAPI document
+// built by the openapi package. Unlike every other endpoint, the document
+// is not wrapped in the response envelope: tools such as Swagger UI and code
+// generators expect the bare document.
+func GetOpenAPISpec(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, openapi.Spec())
+}
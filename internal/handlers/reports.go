@@ -0,0 +1,75 @@
+// This file contains HTTP handlers for abuse reports:
+//   - POST /reports — Flag a user's profile for review
+//   - GET  /reports — List every report with counts per reported user (admin)
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/dlfelps/tinder-go-claude/internal/models"
+	"github.com/dlfelps/tinder-go-claude/internal/store"
+	"github.com/google/uuid"
+)
+
+// ReportHandler handles filing and reviewing abuse reports.
+type ReportHandler struct {
+	store *store.InMemoryStore
+}
+
+// NewReportHandler creates a new ReportHandler with the given store.
+func NewReportHandler(s *store.InMemoryStore) *ReportHandler {
+	return &ReportHandler{store: s}
+}
+
+// CreateReport handles POST /reports — records that reporter_id has
+// flagged reported_id for the given reason. Both users must exist, a user
+// can't report themself, and the reason must be non-blank and at most
+// models.MaxReportReasonLength characters.
+func (h *ReportHandler) CreateReport(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateReportRequest
+	if !decodeJSONBody(w, r, &req) {
+		return
+	}
+
+	reporterID, reportedID, reason, errs := req.Validate()
+	if len(errs) > 0 {
+		writeError(w, http.StatusUnprocessableEntity, errs...)
+		return
+	}
+
+	if _, exists := h.store.GetUser(reporterID); !exists {
+		writeError(w, http.StatusNotFound, "reporter user not found")
+		return
+	}
+	if _, exists := h.store.GetUser(reportedID); !exists {
+		writeError(w, http.StatusNotFound, "reported user not found")
+		return
+	}
+
+	report := h.store.AddReport(models.Report{
+		ReporterID: reporterID,
+		ReportedID: reportedID,
+		Reason:     reason,
+		Timestamp:  time.Now().UTC(),
+	})
+	writeSuccess(w, http.StatusCreated, report, nil)
+}
+
+// ListReports handles GET /reports — returns every report, oldest first,
+// with meta.counts giving the number of reports against each reported
+// user. It exposes who reported whom, so it must be registered behind
+// RequireAdmin.
+func (h *ReportHandler) ListReports(w http.ResponseWriter, r *http.Request) {
+	reports := h.store.GetReports()
+
+	counts := make(map[uuid.UUID]int)
+	for _, report := range reports {
+		counts[report.ReportedID]++
+	}
+
+	writeSuccess(w, http.StatusOK, reports, map[string]any{
+		"count":  len(reports),
+		"counts": counts,
+	})
+}
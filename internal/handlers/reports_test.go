@@ -0,0 +1,89 @@
+// This file contains tests for filing abuse reports and the admin listing.
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/dlfelps/tinder-go-claude/internal/models"
+	"github.com/google/uuid"
+)
+
+func TestCreateReport_AndListForAdmin(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+	bobID, _ := createTestUser(t, mux, "Bob", "male", "zone-a", 30)
+	carolID, _ := createTestUser(t, mux, "Carol", "female", "zone-a", 27)
+
+	reports := []models.CreateReportRequest{
+		{ReporterID: aliceID.String(), ReportedID: bobID.String(), Reason: "  spam  "},
+		{ReporterID: carolID.String(), ReportedID: bobID.String(), Reason: "harassment"},
+		{ReporterID: bobID.String(), ReportedID: carolID.String(), Reason: "fake profile"},
+	}
+	for _, body := range reports {
+		if rr := doRequest(t, mux, "POST", "/reports", body); rr.Code != http.StatusCreated {
+			t.Fatalf("status: got %d, want %d", rr.Code, http.StatusCreated)
+		}
+	}
+
+	// Listing reports needs the admin token.
+	if rr := doRequest(t, mux, "GET", "/reports", nil); rr.Code != http.StatusForbidden {
+		t.Errorf("without token: got %d, want %d", rr.Code, http.StatusForbidden)
+	}
+
+	rr := doAdminRequest(t, mux, "GET", "/reports", nil)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want %d", rr.Code, http.StatusOK)
+	}
+	resp := parseResponse(t, rr)
+
+	data := resp.Data.([]interface{})
+	if len(data) != 3 {
+		t.Fatalf("expected 3 reports, got %d", len(data))
+	}
+	first := data[0].(map[string]interface{})
+	if first["reason"] != "spam" || first["reported_id"] != bobID.String() {
+		t.Errorf("expected the first report, trimmed, got %v", first)
+	}
+
+	counts := resp.Meta["counts"].(map[string]interface{})
+	if counts[bobID.String()] != float64(2) || counts[carolID.String()] != float64(1) {
+		t.Errorf("counts: got %v, want Bob 2 and Carol 1", counts)
+	}
+	if _, ok := counts[aliceID.String()]; ok {
+		t.Errorf("expected no count for Alice, who wasn't reported")
+	}
+}
+
+func TestCreateReport_Errors(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+	bobID, _ := createTestUser(t, mux, "Bob", "male", "zone-a", 30)
+	alice, bob := aliceID.String(), bobID.String()
+
+	tests := []struct {
+		name string
+		body models.CreateReportRequest
+		want int
+	}{
+		{"invalid reporter", models.CreateReportRequest{ReporterID: "x", ReportedID: bob, Reason: "spam"}, http.StatusUnprocessableEntity},
+		{"invalid reported", models.CreateReportRequest{ReporterID: alice, ReportedID: "x", Reason: "spam"}, http.StatusUnprocessableEntity},
+		{"self", models.CreateReportRequest{ReporterID: alice, ReportedID: alice, Reason: "spam"}, http.StatusUnprocessableEntity},
+		{"missing reason", models.CreateReportRequest{ReporterID: alice, ReportedID: bob}, http.StatusUnprocessableEntity},
+		{"blank reason", models.CreateReportRequest{ReporterID: alice, ReportedID: bob, Reason: "   "}, http.StatusUnprocessableEntity},
+		{"reason too long", models.CreateReportRequest{ReporterID: alice, ReportedID: bob, Reason: strings.Repeat("a", models.MaxReportReasonLength+1)}, http.StatusUnprocessableEntity},
+		{"unknown reporter", models.CreateReportRequest{ReporterID: uuid.New().String(), ReportedID: bob, Reason: "spam"}, http.StatusNotFound},
+		{"unknown reported", models.CreateReportRequest{ReporterID: alice, ReportedID: uuid.New().String(), Reason: "spam"}, http.StatusNotFound},
+		{"longest reason", models.CreateReportRequest{ReporterID: alice, ReportedID: bob, Reason: strings.Repeat("é", models.MaxReportReasonLength)}, http.StatusCreated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rr := doRequest(t, mux, "POST", "/reports", tt.body); rr.Code != tt.want {
+				t.Errorf("status: got %d, want %d", rr.Code, tt.want)
+			}
+		})
+	}
+}
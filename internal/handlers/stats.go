@@ -0,0 +1,25 @@
+// This file contains the HTTP handler for store-wide statistics:
+//   - GET /stats — Totals of users, swipes, and matches, and users per zone
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/dlfelps/tinder-go-claude/internal/store"
+)
+
+// StatsHandler serves aggregate counts for a dashboard.
+type StatsHandler struct {
+	store *store.InMemoryStore
+}
+
+// NewStatsHandler creates a new StatsHandler with the given store.
+func NewStatsHandler(s *store.InMemoryStore) *StatsHandler {
+	return &StatsHandler{store: s}
+}
+
+// GetStats handles GET /stats — returns the total number of users, swipes
+// (and how many were LIKEs and PASSes), and matches, plus users per zone.
+func (h *StatsHandler) GetStats(w http.ResponseWriter, r *http.Request) {
+	writeSuccess(w, http.StatusOK, h.store.Stats(), nil)
+}
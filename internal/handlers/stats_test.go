@@ -0,0 +1,50 @@
+// This file contains tests for the store-wide statistics endpoint.
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/dlfelps/tinder-go-claude/internal/models"
+)
+
+func TestGetStats(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+	bobID, _ := createTestUser(t, mux, "Bob", "male", "zone-a", 30)
+	carolID, _ := createTestUser(t, mux, "Carol", "female", "zone-b", 27)
+
+	// Alice and Bob match (two LIKEs); Carol passes on Bob.
+	createTestMatch(t, mux, aliceID, bobID)
+	doRequest(t, mux, "POST", "/swipe", models.CreateSwipeRequest{
+		SwiperID: carolID.String(), SwipedID: bobID.String(), Action: "PASS",
+	})
+
+	rr := doRequest(t, mux, "GET", "/stats", nil)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want %d", rr.Code, http.StatusOK)
+	}
+	data, ok := parseResponse(t, rr).Data.(map[string]interface{})
+	if !ok {
+		t.Fatal("expected data to be an object")
+	}
+
+	want := map[string]float64{
+		"total_users":   3,
+		"total_swipes":  3,
+		"likes":         2,
+		"passes":        1,
+		"total_matches": 1,
+	}
+	for field, value := range want {
+		if data[field] != value {
+			t.Errorf("%s: got %v, want %v", field, data[field], value)
+		}
+	}
+
+	zones, _ := data["users_by_zone"].(map[string]interface{})
+	if len(zones) != 2 || zones["zone-a"] != float64(2) || zones["zone-b"] != float64(1) {
+		t.Errorf("users_by_zone: got %v, want zone-a 2 and zone-b 1", zones)
+	}
+}
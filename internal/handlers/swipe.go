@@ -1,12 +1,18 @@
 // This file contains HTTP handlers for swipe and match endpoints:
 //   - POST /swipe         — Submit a swipe action (LIKE or PASS)
+//   - POST /swipe/undo    — Take back the user's most recent swipe
+//   - GET  /swipes?user_id=<uuid> — List a user's swipe history
 //   - GET  /matches?user_id=<uuid> — List all matches for a user
 package handlers
 
 import (
-	"encoding/json"
 	"errors"
+	"fmt"
+	"math"
 	"net/http"
+	"slices"
+	"strconv"
+	"time"
 
 	"github.com/dlfelps/tinder-go-claude/internal/models"
 	"github.com/dlfelps/tinder-go-claude/internal/services"
@@ -17,19 +23,59 @@ import (
 // SwipeHandler handles swipe and match HTTP requests.
 type SwipeHandler struct {
 	swipeService *services.SwipeService
+	matchService *services.MatchService
 	store        *store.InMemoryStore
+
+	// likeBackSuggestion enables meta.suggestion on a PASS against someone
+	// who liked the swiper; see SetLikeBackSuggestion.
+	likeBackSuggestion bool
+
+	// actionAliases translates alternative action strings; see
+	// SetActionAliases. Nil accepts only LIKE and PASS.
+	actionAliases models.SwipeActionAliases
+
+	// allowMatchUndo lets POST /swipe/undo take back a LIKE that formed a
+	// match; see SetAllowMatchUndo.
+	allowMatchUndo bool
 }
 
-// NewSwipeHandler creates a new SwipeHandler with the given swipe service
-// and store. The store is needed for the GetMatches handler to verify user
-// existence.
-func NewSwipeHandler(ss *services.SwipeService, s *store.InMemoryStore) *SwipeHandler {
+// NewSwipeHandler creates a new SwipeHandler with the given services and
+// store. The store is needed to look up a user's earlier swipes.
+func NewSwipeHandler(ss *services.SwipeService, ms *services.MatchService, s *store.InMemoryStore) *SwipeHandler {
 	return &SwipeHandler{
-		swipeService: ss,
-		store:        s,
+		swipeService:   ss,
+		matchService:   ms,
+		store:          s,
+		allowMatchUndo: true,
 	}
 }
 
+// LikeBackSuggestion is the meta.suggestion text returned when a user
+// passes on someone who already liked them.
+const LikeBackSuggestion = "this person liked you — are you sure?"
+
+// SetLikeBackSuggestion turns the like-back suggestion on or off. When on,
+// a user's first PASS on someone who has LIKEd them gets meta.suggestion in
+// the response, so the client can offer to undo it. It is off by default.
+func (h *SwipeHandler) SetLikeBackSuggestion(enabled bool) {
+	h.likeBackSuggestion = enabled
+}
+
+// SetActionAliases makes POST /swipe accept extra action strings, such as
+// "YES" for LIKE, for client versions that don't send LIKE and PASS. Values
+// that are neither canonical nor aliased are still rejected with 422.
+func (h *SwipeHandler) SetActionAliases(aliases models.SwipeActionAliases) {
+	h.actionAliases = aliases
+}
+
+// SetAllowMatchUndo controls whether POST /swipe/undo may take back a LIKE
+// that formed a match, removing the match too. It is allowed by default.
+// When disabled, such an undo gets 409, since the other user may already
+// be talking to the match.
+func (h *SwipeHandler) SetAllowMatchUndo(allowed bool) {
+	h.allowMatchUndo = allowed
+}
+
 // CreateSwipe handles POST /swipe — records a swipe action and checks for
 // mutual matches.
 //
@@ -41,20 +87,25 @@ func NewSwipeHandler(ss *services.SwipeService, s *store.InMemoryStore) *SwipeHa
 func (h *SwipeHandler) CreateSwipe(w http.ResponseWriter, r *http.Request) {
 	// Step 1: Decode the JSON request body.
 	var req models.CreateSwipeRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeError(w, http.StatusUnprocessableEntity, "invalid JSON in request body")
+	if !decodeJSONBody(w, r, &req) {
 		return
 	}
 
 	// Step 2: Validate the request.
 	// The Validate method returns parsed UUIDs and action along with errors,
-	// so we don't have to parse them again if validation succeeds.
-	swiperID, swipedID, action, errs := req.Validate()
+	// so we don't have to parse them again if validation succeeds. Any
+	// configured action aliases are translated here.
+	swiperID, swipedID, action, errs := req.ValidateWithAliases(h.actionAliases)
 	if len(errs) > 0 {
 		writeError(w, http.StatusUnprocessableEntity, errs...)
 		return
 	}
 
+	// The suggestion is only for a first PASS, so check for an earlier one
+	// before this swipe is recorded.
+	suggestLikeBack := h.likeBackSuggestion && action == models.SwipeActionPass &&
+		!h.hasPassed(swiperID, swipedID)
+
 	// Step 3: Process the swipe through the service layer.
 	result, err := h.swipeService.ProcessSwipe(swiperID, swipedID, action)
 	if err != nil {
@@ -63,14 +114,23 @@ func (h *SwipeHandler) CreateSwipe(w http.ResponseWriter, r *http.Request) {
 		// to Python's isinstance() or except clauses.
 		var notFoundErr *services.NotFoundError
 		var validationErr *services.ValidationError
+		var forbiddenErr *services.ForbiddenError
+		var conflictErr *services.ConflictError
+		var rateLimitErr *services.RateLimitError
 
 		switch {
 		case errors.As(err, &notFoundErr):
 			writeError(w, http.StatusNotFound, err.Error())
 		case errors.As(err, &validationErr):
 			writeError(w, http.StatusBadRequest, err.Error())
+		case errors.As(err, &forbiddenErr):
+			writeError(w, http.StatusForbidden, err.Error())
+		case errors.As(err, &conflictErr):
+			writeError(w, http.StatusConflict, err.Error())
+		case errors.As(err, &rateLimitErr):
+			writeRateLimitError(w, rateLimitErr)
 		default:
-			writeError(w, http.StatusInternalServerError, "internal server error")
+			writeInternalError(w, err)
 		}
 		return
 	}
@@ -87,40 +147,245 @@ func (h *SwipeHandler) CreateSwipe(w http.ResponseWriter, r *http.Request) {
 		responseData["match"] = result.Match
 	}
 
-	writeSuccess(w, http.StatusCreated, responseData, nil)
+	var meta map[string]any
+	if suggestLikeBack && h.hasLikedBy(swiperID, swipedID) {
+		meta = map[string]any{"suggestion": LikeBackSuggestion}
+	}
+
+	writeSuccess(w, http.StatusCreated, responseData, meta)
 }
 
-// GetMatches handles GET /matches?user_id=<uuid> — returns all matches
-// for the given user.
-func (h *SwipeHandler) GetMatches(w http.ResponseWriter, r *http.Request) {
-	// Step 1: Extract and validate the user_id query parameter.
-	userIDStr := r.URL.Query().Get("user_id")
-	if userIDStr == "" {
-		writeError(w, http.StatusUnprocessableEntity, "user_id query parameter is required")
+// UndoSwipe handles POST /swipe/undo — removes the user's most recent swipe
+// so that person reappears in their feed. Undoing a LIKE also undoes any
+// match it created, unless SetAllowMatchUndo has disabled that, in which
+// case it gets 409 and nothing changes. Returns the undone swipe, or 404 if
+// there is nothing to undo.
+func (h *SwipeHandler) UndoSwipe(w http.ResponseWriter, r *http.Request) {
+	var req models.UndoSwipeRequest
+	if !decodeJSONBody(w, r, &req) {
+		return
+	}
+
+	userID, errs := req.Validate()
+	if len(errs) > 0 {
+		writeError(w, http.StatusUnprocessableEntity, errs...)
+		return
+	}
+
+	if _, exists := h.store.GetUser(userID); !exists {
+		writeError(w, http.StatusNotFound, "user not found")
+		return
+	}
+
+	var undone *models.Swipe
+	if h.allowMatchUndo {
+		undone, _ = h.store.UndoLastSwipe(userID)
+	} else {
+		var matched bool
+		undone, matched = h.store.UndoLastSwipeUnlessMatched(userID)
+		if matched {
+			writeError(w, http.StatusConflict, "the last swipe formed a match and can't be undone")
+			return
+		}
+	}
+	if undone == nil {
+		writeError(w, http.StatusNotFound, "no swipes to undo")
+		return
+	}
+
+	writeSuccess(w, http.StatusOK, undone, nil)
+}
+
+// writeRateLimitError writes a 429 for a RateLimitError. The Retry-After
+// header tells the client how many seconds to wait, rounded up so that
+// retrying on time never lands just before the limit resets.
+func writeRateLimitError(w http.ResponseWriter, err *services.RateLimitError) {
+	retryAfter := int(math.Ceil(err.RetryAfter.Seconds()))
+	w.Header().Set("Retry-After", strconv.Itoa(max(retryAfter, 1)))
+	writeError(w, http.StatusTooManyRequests, err.Error())
+}
+
+// hasPassed reports whether swiperID has already passed on swipedID.
+func (h *SwipeHandler) hasPassed(swiperID, swipedID uuid.UUID) bool {
+	for _, swipe := range h.store.GetSwipesByUser(swiperID) {
+		if swipe.SwipedID == swipedID && swipe.Action == models.SwipeActionPass {
+			return true
+		}
+	}
+	return false
+}
+
+// hasLikedBy reports whether likerID has LIKEd userID.
+func (h *SwipeHandler) hasLikedBy(userID, likerID uuid.UUID) bool {
+	for _, like := range h.store.GetLikesReceived(userID) {
+		if like.SwiperID == likerID {
+			return true
+		}
+	}
+	return false
+}
+
+// Page sizes for GET /swipes.
+const (
+	defaultSwipeHistoryPageSize = 20
+	maxSwipeHistoryPageSize     = 100
+)
+
+// DeleteSwipe handles DELETE /swipe?swiper_id=<uuid>&swiped_id=<uuid> — an
+// admin tool for removing one user's swipe on another, rather than just the
+// latest swipe as UndoSwipe does. A match the swipe was part of goes with
+// it. It responds 204 on success and 404 if there is no such swipe.
+func (h *SwipeHandler) DeleteSwipe(w http.ResponseWriter, r *http.Request) {
+	swiperID, err := queryUUID(r, "swiper_id")
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
 		return
 	}
+	swipedID, err := queryUUID(r, "swiped_id")
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	if !h.store.RemoveSwipe(swiperID, swipedID) {
+		writeError(w, http.StatusNotFound, "swipe not found")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
 
-	userID, err := uuid.Parse(userIDStr)
+// GetSwipes handles GET /swipes?user_id=<uuid>&action=<LIKE|PASS>&limit=<n>&offset=<n>
+// — returns one page of the swipes a user has made, newest first. The
+// optional action keeps only LIKEs or only PASSes. meta.total is the number
+// of swipes after filtering.
+func (h *SwipeHandler) GetSwipes(w http.ResponseWriter, r *http.Request) {
+	userID, err := queryUUID(r, "user_id")
 	if err != nil {
-		writeError(w, http.StatusUnprocessableEntity, "user_id must be a valid UUID")
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	var action models.SwipeAction
+	if raw := r.URL.Query().Get("action"); raw != "" {
+		action = models.SwipeAction(raw)
+		if !action.IsValid() {
+			writeError(w, http.StatusUnprocessableEntity, "action must be LIKE or PASS")
+			return
+		}
+	}
+
+	limit, err := queryInt(r, "limit", defaultSwipeHistoryPageSize)
+	if err != nil || limit < 1 || limit > maxSwipeHistoryPageSize {
+		writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("limit must be an integer between 1 and %d", maxSwipeHistoryPageSize))
+		return
+	}
+	offset, err := queryInt(r, "offset", 0)
+	if err != nil || offset < 0 {
+		writeError(w, http.StatusUnprocessableEntity, "offset must be a non-negative integer")
 		return
 	}
 
-	// Step 2: Verify the user exists before querying matches.
 	if _, exists := h.store.GetUser(userID); !exists {
 		writeError(w, http.StatusNotFound, "user not found")
 		return
 	}
 
-	// Step 3: Retrieve all matches for the user.
-	matches := h.store.GetMatchesForUser(userID)
+	// Newest first; filtering afterwards keeps that order.
+	swipes := h.store.GetSwipesByUserSorted(userID, true)
+	if action != "" {
+		swipes = slices.DeleteFunc(swipes, func(swipe models.Swipe) bool {
+			return swipe.Action != action
+		})
+	}
+
+	total := len(swipes)
+	page := swipes[min(offset, total):min(offset+limit, total)]
+	writePaginated(w, page, total, limit, offset)
+}
 
-	// Ensure we return an empty array rather than null in JSON.
-	if matches == nil {
-		matches = []models.Match{}
+// GetMatches handles GET /matches?user_id=<uuid> — returns all matches
+// for the given user, each with the other person's full profile and when
+// they matched. Adding a before parameter switches to paging (see
+// getMatchesPage).
+func (h *SwipeHandler) GetMatches(w http.ResponseWriter, r *http.Request) {
+	// Step 1: Extract and validate the user_id query parameter.
+	userID, err := queryUUID(r, "user_id")
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
 	}
 
-	writeSuccess(w, http.StatusOK, matches, map[string]any{
-		"count": len(matches),
-	})
+	// A "before" parameter (empty for the first page) switches to
+	// timestamp-cursor paging.
+	if r.URL.Query().Has("before") {
+		h.getMatchesPage(w, r, userID)
+		return
+	}
+
+	// Step 2: Retrieve the matches, each with the other person's profile.
+	// The service reports a missing user as a NotFoundError.
+	matches, err := h.matchService.GetMatchesWithProfiles(userID)
+	if err != nil {
+		var notFoundErr *services.NotFoundError
+		if errors.As(err, &notFoundErr) {
+			writeError(w, http.StatusNotFound, "user not found")
+			return
+		}
+		writeInternalError(w, err)
+		return
+	}
+
+	// Without paging parameters every match comes back at once: a single
+	// page that holds the whole list.
+	writePaginated(w, matches, len(matches), len(matches), 0)
+}
+
+// Page sizes for GET /matches paging.
+const (
+	defaultMatchPageSize = 20
+	maxMatchPageSize     = 100
+)
+
+// getMatchesPage serves GET /matches?user_id=<uuid>&before=<timestamp>&limit=<n>.
+// Matches come newest first. Clients start with an empty before and pass
+// meta.next_cursor back as before to get older matches; next_cursor is
+// absent on the last page. Two matches created in the same nanosecond
+// could straddle a page boundary and the older page would miss one, which
+// real timestamps make vanishingly unlikely.
+func (h *SwipeHandler) getMatchesPage(w http.ResponseWriter, r *http.Request, userID uuid.UUID) {
+	var before time.Time
+	if raw := r.URL.Query().Get("before"); raw != "" {
+		t, err := time.Parse(time.RFC3339Nano, raw)
+		if err != nil {
+			writeError(w, http.StatusUnprocessableEntity, "before must be an RFC 3339 timestamp")
+			return
+		}
+		before = t
+	}
+
+	limit, err := queryInt(r, "limit", defaultMatchPageSize)
+	if err != nil || limit < 1 || limit > maxMatchPageSize {
+		writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("limit must be an integer between 1 and %d", maxMatchPageSize))
+		return
+	}
+
+	// Ask for one extra match: if it comes back, there is another page.
+	matches, err := h.matchService.GetMatchesWithProfilesBefore(userID, before, limit+1)
+	if err != nil {
+		var notFoundErr *services.NotFoundError
+		if errors.As(err, &notFoundErr) {
+			writeError(w, http.StatusNotFound, "user not found")
+			return
+		}
+		writeInternalError(w, err)
+		return
+	}
+
+	meta := map[string]any{"limit": limit}
+	if len(matches) > limit {
+		matches = matches[:limit]
+		meta["next_cursor"] = matches[limit-1].MatchedAt.Format(time.RFC3339Nano)
+	}
+	meta["count"] = len(matches)
+	writeSuccess(w, http.StatusOK, matches, meta)
 }
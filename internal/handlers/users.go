@@ -1,13 +1,24 @@
 // This file contains HTTP handlers for user-related endpoints:
 //   - POST /users/   — Create a new user profile
+//   - GET  /users    — List all users, one page at a time
 //   - GET  /users/{id} — Retrieve a user by their UUID
+//   - PUT  /users/{id} — Update a user's profile details
+//   - DELETE /users/{id} — Delete a user and everything linked to them
+//   - POST /users/batch — Look up several users at once
+//   - GET  /users/{id}/export — Download everything stored about a user
+//   - PUT  /users/{id}/preferences — Update notification preferences
+//   - POST /boost — Show a user first in feeds for a while
 package handlers
 
 import (
-	"encoding/json"
+	"bytes"
+	"fmt"
 	"net/http"
+	"sort"
+	"time"
 
 	"github.com/dlfelps/tinder-go-claude/internal/models"
+	"github.com/dlfelps/tinder-go-claude/internal/services"
 	"github.com/dlfelps/tinder-go-claude/internal/store"
 	"github.com/google/uuid"
 )
@@ -18,11 +29,56 @@ import (
 // with dependency injection.
 type UserHandler struct {
 	store *store.InMemoryStore
+
+	// genders canonicalizes gender and interested_in values on the way in.
+	genders *models.GenderNormalizer
+
+	// ids generates the IDs of new users; see SetIDGenerator.
+	ids services.IDGenerator
 }
 
-// NewUserHandler creates a new UserHandler with the given store.
+// NewUserHandler creates a new UserHandler with the given store. Genders
+// are normalized with the default synonyms until SetGenderSynonyms is called.
 func NewUserHandler(s *store.InMemoryStore) *UserHandler {
-	return &UserHandler{store: s}
+	return &UserHandler{store: s, genders: models.NewGenderNormalizer(nil), ids: services.RandomIDs{}}
+}
+
+// SetIDGenerator replaces the generator of new user IDs, so tests can
+// predict them. Nil restores the default, services.RandomIDs.
+func (h *UserHandler) SetIDGenerator(ids services.IDGenerator) {
+	if ids == nil {
+		ids = services.RandomIDs{}
+	}
+	h.ids = ids
+}
+
+// SetGenderSynonyms adds synonym mappings (e.g. "guy" → "male") on top of
+// models.DefaultGenderSynonyms.
+func (h *UserHandler) SetGenderSynonyms(synonyms map[string]string) {
+	h.genders = models.NewGenderNormalizer(synonyms)
+}
+
+// normalizeGenders rewrites req.Gender and req.InterestedIn to their
+// canonical forms, so that "men" and "male" filter identically. It returns
+// a validation message for each value that isn't recognized.
+func (h *UserHandler) normalizeGenders(req *models.CreateUserRequest) []string {
+	var errs []string
+
+	gender, ok := h.genders.Gender(req.Gender)
+	if !ok {
+		errs = append(errs, fmt.Sprintf("gender %q is not recognized", req.Gender))
+	}
+	req.Gender = gender
+
+	if req.InterestedIn != "" {
+		interestedIn, ok := h.genders.InterestedIn(req.InterestedIn)
+		if !ok {
+			errs = append(errs, fmt.Sprintf("interested_in %q is not recognized", req.InterestedIn))
+		}
+		req.InterestedIn = interestedIn
+	}
+
+	return errs
 }
 
 // CreateUser handles POST /users/ — creates a new user profile.
@@ -37,12 +93,11 @@ func NewUserHandler(s *store.InMemoryStore) *UserHandler {
 // step of the process.
 func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
 	// Step 1: Decode the JSON request body into our request struct.
-	// json.NewDecoder reads from r.Body (an io.Reader) and parses JSON.
+	// decodeJSONBody writes the error response itself (400 for a body that
+	// doesn't match Content-Length, 422 for invalid JSON, mirroring FastAPI's
+	// automatic validation error response), so we just return on failure.
 	var req models.CreateUserRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		// If the request body isn't valid JSON, return a 422 error.
-		// This mirrors FastAPI's automatic validation error response.
-		writeError(w, http.StatusUnprocessableEntity, "invalid JSON in request body")
+	if !decodeJSONBody(w, r, &req) {
 		return
 	}
 
@@ -53,15 +108,36 @@ func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusUnprocessableEntity, errs...)
 		return
 	}
+	if errs := h.normalizeGenders(&req); len(errs) > 0 {
+		writeError(w, http.StatusUnprocessableEntity, errs...)
+		return
+	}
 
-	// Step 3: Create the domain model with a generated UUID.
-	// uuid.New() generates a random UUID v4, similar to Python's uuid.uuid4().
+	// Step 3: Create the domain model with a generated UUID. By default
+	// h.ids calls uuid.New(), which generates a random UUID v4, similar to
+	// Python's uuid.uuid4().
+	now := time.Now().UTC()
 	user := models.User{
-		ID:     uuid.New(),
-		Name:   req.Name,
-		Age:    req.Age,
-		Gender: req.Gender,
-		ZoneID: req.ZoneID,
+		ID:           h.ids.New(),
+		Name:         req.Name,
+		Age:          req.Age,
+		Gender:       req.Gender,
+		ZoneID:       req.ZoneID,
+		Bio:          req.Bio,
+		InterestedIn: req.InterestedInOrDefault(),
+		MinAge:       req.MinAge,
+		MaxAge:       req.MaxAge,
+		Tags:         req.NormalizedTags(),
+		LastActiveAt: now,
+		JoinedAt:     now,
+
+		Latitude:      req.Latitude,
+		Longitude:     req.Longitude,
+		MaxDistanceKm: req.MaxDistanceKm,
+
+		// Notifications are opt-out, so new users start with them enabled.
+		NotifyOnLike:  true,
+		NotifyOnMatch: true,
 	}
 
 	// Step 4: Persist the user in the store.
@@ -71,8 +147,50 @@ func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
 	writeSuccess(w, http.StatusCreated, user, nil)
 }
 
+// Page sizes for GET /users.
+const (
+	defaultUserListPageSize = 20
+	maxUserListPageSize     = 100
+)
+
+// ListUsers handles GET /users?limit=<n>&offset=<n> — returns one page of
+// every registered user, for admin tooling. meta.total is the number of
+// users in the store.
+//
+// The store keeps users in a map, and Go randomizes map iteration order, so
+// the users are sorted (by name, then ID to break ties) before slicing.
+// Otherwise the same offset could return different users on each call.
+func (h *UserHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
+	limit, err := queryInt(r, "limit", defaultUserListPageSize)
+	if err != nil || limit < 1 || limit > maxUserListPageSize {
+		writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("limit must be an integer between 1 and %d", maxUserListPageSize))
+		return
+	}
+	offset, err := queryInt(r, "offset", 0)
+	if err != nil || offset < 0 {
+		writeError(w, http.StatusUnprocessableEntity, "offset must be a non-negative integer")
+		return
+	}
+
+	users := h.store.GetAllUsers()
+	sort.Slice(users, func(i, j int) bool {
+		if users[i].Name != users[j].Name {
+			return users[i].Name < users[j].Name
+		}
+		return bytes.Compare(users[i].ID[:], users[j].ID[:]) < 0
+	})
+
+	total := len(users)
+	page := users[min(offset, total):min(offset+limit, total)]
+	writePaginated(w, page, total, limit, offset)
+}
+
 // GetUser handles GET /users/{id} — retrieves a user by their UUID.
 //
+// The response carries an ETag derived from the profile. A client that sends
+// it back in If-None-Match gets 304 Not Modified, with no body, until the
+// profile changes.
+//
 // Go 1.22+ introduced path parameters in the standard library's ServeMux.
 // We extract the {id} parameter using r.PathValue("id"), which is similar
 // to FastAPI's path parameter injection.
@@ -94,6 +212,231 @@ func (h *UserHandler) GetUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Step 3: Return the user data with HTTP 200 OK.
+	// Step 3: Answer a conditional request with 304 if the client's copy
+	// is current. The ETag goes on both responses so the client can keep
+	// revalidating. If the tag can't be computed, the request is simply
+	// treated as unconditional.
+	if etag, err := contentETag(user); err == nil {
+		w.Header().Set("ETag", etag)
+		if inm := r.Header.Get("If-None-Match"); inm != "" && etagMatches(inm, etag) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+
+	// Step 4: Return the user data with HTTP 200 OK.
+	writeSuccess(w, http.StatusOK, user, nil)
+}
+
+// UpdateUser handles PUT /users/{id} — replaces the user's profile (name,
+// age, gender, zone, bio, tags, location, and who they want to see) with
+// the values in the body. The body takes the same shape as POST /users/ and
+// is validated the same way, so every field is required. Fields that aren't
+// part of the profile, such as notification preferences, boosts, and
+// LastActiveAt, are left unchanged.
+func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, "user not found")
+		return
+	}
+
+	var req models.CreateUserRequest
+	if !decodeJSONBody(w, r, &req) {
+		return
+	}
+	if errs := req.Validate(); len(errs) > 0 {
+		writeError(w, http.StatusUnprocessableEntity, errs...)
+		return
+	}
+	if errs := h.normalizeGenders(&req); len(errs) > 0 {
+		writeError(w, http.StatusUnprocessableEntity, errs...)
+		return
+	}
+
+	// As in UpdatePreferences, the profile fields change under the store's
+	// lock, so a concurrent boost or activity update isn't undone.
+	interestedIn, tags := req.InterestedInOrDefault(), req.NormalizedTags()
+	user, exists := h.store.ModifyUser(userID, func(user *models.User) {
+		user.Name = req.Name
+		user.Age = req.Age
+		user.Gender = req.Gender
+		user.ZoneID = req.ZoneID
+		user.Bio = req.Bio
+		user.InterestedIn = interestedIn
+		user.MinAge = req.MinAge
+		user.MaxAge = req.MaxAge
+		user.Tags = tags
+		user.Latitude = req.Latitude
+		user.Longitude = req.Longitude
+		user.MaxDistanceKm = req.MaxDistanceKm
+	})
+	if !exists {
+		writeError(w, http.StatusNotFound, "user not found")
+		return
+	}
+
+	writeSuccess(w, http.StatusOK, user, nil)
+}
+
+// DeleteUser handles DELETE /users/{id} — removes the user along with their
+// swipes and matches. Like DeleteMatch, it responds 204 No Content.
+func (h *UserHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, "user not found")
+		return
+	}
+
+	if !h.store.DeleteUser(userID) {
+		writeError(w, http.StatusNotFound, "user not found")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// BatchGetUsers handles POST /users/batch — returns the profiles for a list
+// of IDs in one round trip, plus the IDs that weren't found. Finding only
+// some of the users is still a 200; the caller checks "missing".
+func (h *UserHandler) BatchGetUsers(w http.ResponseWriter, r *http.Request) {
+	var req models.BatchUsersRequest
+	if !decodeJSONBody(w, r, &req) {
+		return
+	}
+	ids, errs := req.Validate()
+	if len(errs) > 0 {
+		writeError(w, http.StatusUnprocessableEntity, errs...)
+		return
+	}
+
+	// GetUsers resolves every ID under a single lock; walking ids (rather
+	// than ranging over the map) keeps the response in request order.
+	found := h.store.GetUsers(ids)
+	resp := models.BatchUsersResponse{
+		Users:   make([]models.User, 0, len(found)),
+		Missing: []uuid.UUID{},
+	}
+	for _, id := range ids {
+		if user, ok := found[id]; ok {
+			resp.Users = append(resp.Users, user)
+		} else {
+			resp.Missing = append(resp.Missing, id)
+		}
+	}
+
+	writeSuccess(w, http.StatusOK, resp, map[string]any{
+		"count": len(resp.Users),
+	})
+}
+
+// ExportUser handles GET /users/{id}/export — returns a single JSON document
+// with the user's profile, the swipes they made, the likes they received,
+// and their matches.
+func (h *UserHandler) ExportUser(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, "user not found")
+		return
+	}
+
+	user, exists := h.store.GetUser(userID)
+	if !exists {
+		writeError(w, http.StatusNotFound, "user not found")
+		return
+	}
+
+	// The store returns nil slices when there is nothing to report; swap
+	// them for empty slices so the export always contains [] rather than null.
+	export := models.UserExport{
+		Profile:       user,
+		Swipes:        h.store.GetSwipesByUser(userID),
+		LikesReceived: h.store.GetLikesReceived(userID),
+		Matches:       h.store.GetMatchesForUser(userID),
+	}
+	if export.Swipes == nil {
+		export.Swipes = []models.Swipe{}
+	}
+	if export.LikesReceived == nil {
+		export.LikesReceived = []models.Swipe{}
+	}
+	if export.Matches == nil {
+		export.Matches = []models.Match{}
+	}
+
+	writeSuccess(w, http.StatusOK, export, nil)
+}
+
+// UpdatePreferences handles PUT /users/{id}/preferences — changes the user's
+// notification preferences and quiet hours. Omitted fields keep their
+// current value.
+func (h *UserHandler) UpdatePreferences(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, "user not found")
+		return
+	}
+
+	var req models.UpdatePreferencesRequest
+	if !decodeJSONBody(w, r, &req) {
+		return
+	}
+	if errs := req.Validate(); len(errs) > 0 {
+		writeError(w, http.StatusUnprocessableEntity, errs...)
+		return
+	}
+
+	// Apply only the preferences that were provided. ModifyUser makes the
+	// change under the store's lock, so a concurrent update to other fields
+	// (a boost, say) can't be lost.
+	user, exists := h.store.ModifyUser(userID, func(user *models.User) {
+		if req.NotifyOnLike != nil {
+			user.NotifyOnLike = *req.NotifyOnLike
+		}
+		if req.NotifyOnMatch != nil {
+			user.NotifyOnMatch = *req.NotifyOnMatch
+		}
+		if req.QuietHours != nil {
+			user.QuietHours = req.QuietHours
+			if req.ClearsQuietHours() {
+				user.QuietHours = nil
+			}
+		}
+	})
+	if !exists {
+		writeError(w, http.StatusNotFound, "user not found")
+		return
+	}
+
+	writeSuccess(w, http.StatusOK, user, nil)
+}
+
+// Boost handles POST /boost — boosts a user so they appear ahead of
+// everyone else in other users' feeds for the next N minutes (default
+// models.DefaultBoostMinutes). Boosting again restarts the clock from now
+// rather than adding to the time left. The boost needs no cleanup: feeds
+// compare boost_until with the current time, so it lapses on its own.
+func (h *UserHandler) Boost(w http.ResponseWriter, r *http.Request) {
+	var req models.BoostRequest
+	if !decodeJSONBody(w, r, &req) {
+		return
+	}
+	userID, duration, errs := req.Validate()
+	if len(errs) > 0 {
+		writeError(w, http.StatusUnprocessableEntity, errs...)
+		return
+	}
+
+	// As in UpdatePreferences, only BoostUntil changes, under the store's
+	// lock.
+	until := time.Now().UTC().Add(duration)
+	user, exists := h.store.ModifyUser(userID, func(user *models.User) {
+		user.BoostUntil = &until
+	})
+	if !exists {
+		writeError(w, http.StatusNotFound, "user not found")
+		return
+	}
+
 	writeSuccess(w, http.StatusOK, user, nil)
 }
@@ -0,0 +1,127 @@
+// This file contains HTTP handlers for zone-level endpoints:
+//   - GET /zones/{zone_id}/active?within=<duration> — Recently active users in a zone
+//   - GET /users/{id}/zone-mates — Everyone else in a user's zone
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/dlfelps/tinder-go-claude/internal/models"
+	"github.com/dlfelps/tinder-go-claude/internal/store"
+	"github.com/google/uuid"
+)
+
+// defaultActiveWindow is used when the client omits the "within" parameter.
+const defaultActiveWindow = 24 * time.Hour
+
+// ZoneHandler handles zone-related HTTP requests.
+type ZoneHandler struct {
+	store *store.InMemoryStore
+
+	// now returns the current time. It defaults to time.Now but can be
+	// replaced (see SetClock) so tests can control what "recent" means.
+	now func() time.Time
+}
+
+// NewZoneHandler creates a new ZoneHandler with the given store.
+func NewZoneHandler(s *store.InMemoryStore) *ZoneHandler {
+	return &ZoneHandler{store: s, now: time.Now}
+}
+
+// SetClock replaces the handler's time source. Functions are first-class
+// values in Go, so a "fake clock" is just a function returning a fixed time.
+func (h *ZoneHandler) SetClock(now func() time.Time) {
+	h.now = now
+}
+
+// GetActiveUsers handles GET /zones/{zone_id}/active?within=24h — returns the
+// users in the zone whose LastActiveAt falls inside the requested window.
+//
+// The window is parsed with time.ParseDuration, which understands strings
+// like "90m", "24h", or "1h30m".
+func (h *ZoneHandler) GetActiveUsers(w http.ResponseWriter, r *http.Request) {
+	zoneID := r.PathValue("zone_id")
+
+	// Step 1: Parse and validate the activity window.
+	within := defaultActiveWindow
+	if raw := r.URL.Query().Get("within"); raw != "" {
+		d, err := time.ParseDuration(raw)
+		if err != nil || d <= 0 {
+			writeError(w, http.StatusUnprocessableEntity, "within must be a positive duration such as 24h or 90m")
+			return
+		}
+		within = d
+	}
+
+	// Step 2: Keep only zone members active since the cutoff.
+	cutoff := h.now().Add(-within)
+	active := []models.User{}
+	for _, user := range h.store.GetUsersInZone(zoneID) {
+		if !user.LastActiveAt.Before(cutoff) {
+			active = append(active, user)
+		}
+	}
+
+	writeSuccess(w, http.StatusOK, active, map[string]any{
+		"count":  len(active),
+		"within": within.String(),
+	})
+}
+
+// Page sizes for GET /users/{id}/zone-mates.
+const (
+	defaultZoneMatesPageSize = 20
+	maxZoneMatesPageSize     = 100
+)
+
+// GetZoneMates handles GET /users/{id}/zone-mates?limit=<n>&offset=<n> —
+// returns one page of the other users in the same zone as the given user,
+// for a "community" view.
+//
+// Unlike the feed, nothing is filtered out beyond the user themself and
+// anyone blocked either way: people they have swiped on, and people outside
+// their preferences, are all listed. Zones are compared exactly, without the
+// feed's zone aliases. Users are ordered by ID so pages are stable while the
+// zone is unchanged.
+func (h *ZoneHandler) GetZoneMates(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, "user not found")
+		return
+	}
+
+	limit, err := queryInt(r, "limit", defaultZoneMatesPageSize)
+	if err != nil || limit < 1 || limit > maxZoneMatesPageSize {
+		writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("limit must be an integer between 1 and %d", maxZoneMatesPageSize))
+		return
+	}
+	offset, err := queryInt(r, "offset", 0)
+	if err != nil || offset < 0 {
+		writeError(w, http.StatusUnprocessableEntity, "offset must be a non-negative integer")
+		return
+	}
+
+	user, exists := h.store.GetUser(userID)
+	if !exists {
+		writeError(w, http.StatusNotFound, "user not found")
+		return
+	}
+
+	mates := []models.User{}
+	for _, other := range h.store.GetUsersInZone(user.ZoneID) {
+		if other.ID != userID && !h.store.IsBlocked(userID, other.ID) {
+			mates = append(mates, other)
+		}
+	}
+	sort.Slice(mates, func(i, j int) bool {
+		return bytes.Compare(mates[i].ID[:], mates[j].ID[:]) < 0
+	})
+
+	total := len(mates)
+	page := mates[min(offset, total):min(offset+limit, total)]
+	writePaginated(w, page, total, limit, offset)
+}
@@ -0,0 +1,219 @@
+// This file contains tests for the zone endpoints. The active-users tests
+// use a fake clock so "recently active" can be asserted without sleeping.
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/dlfelps/tinder-go-claude/internal/models"
+	"github.com/dlfelps/tinder-go-claude/internal/store"
+	"github.com/google/uuid"
+)
+
+// setupZoneTest resets the store and returns a router serving the zone
+// endpoints with the handler's clock frozen at the given time.
+func setupZoneTest(t *testing.T, now time.Time) (http.Handler, *store.InMemoryStore) {
+	t.Helper()
+
+	s := store.GetStore()
+	s.Reset()
+
+	zoneHandler := NewZoneHandler(s)
+	zoneHandler.SetClock(func() time.Time { return now })
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /zones/{zone_id}/active", zoneHandler.GetActiveUsers)
+	return mux, s
+}
+
+// addUserActiveAt stores a user whose LastActiveAt is set to the given time.
+func addUserActiveAt(s *store.InMemoryStore, name, zone string, lastActive time.Time) models.User {
+	user := models.User{
+		ID:           uuid.New(),
+		Name:         name,
+		Age:          30,
+		Gender:       "other",
+		ZoneID:       zone,
+		LastActiveAt: lastActive,
+	}
+	s.AddUser(user)
+	return user
+}
+
+func TestGetActiveUsers_OnlyRecentZoneMembers(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	mux, s := setupZoneTest(t, now)
+
+	addUserActiveAt(s, "Alice", "zone-a", now.Add(-1*time.Hour))     // Recent.
+	addUserActiveAt(s, "Bob", "zone-a", now.Add(-48*time.Hour))      // Stale.
+	addUserActiveAt(s, "Charlie", "zone-b", now.Add(-1*time.Minute)) // Other zone.
+
+	rr := doRequest(t, mux, "GET", "/zones/zone-a/active?within=24h", nil)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want %d", rr.Code, http.StatusOK)
+	}
+
+	resp := parseResponse(t, rr)
+	data, ok := resp.Data.([]interface{})
+	if !ok {
+		t.Fatal("expected data to be an array")
+	}
+	if len(data) != 1 {
+		t.Fatalf("expected 1 active user, got %d", len(data))
+	}
+	if name := data[0].(map[string]interface{})["name"]; name != "Alice" {
+		t.Errorf("expected Alice, got %v", name)
+	}
+	if count, ok := resp.Meta["count"].(float64); !ok || int(count) != 1 {
+		t.Errorf("expected meta.count=1, got %v", resp.Meta["count"])
+	}
+}
+
+func TestGetActiveUsers_WindowControlsResult(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	mux, s := setupZoneTest(t, now)
+
+	addUserActiveAt(s, "Alice", "zone-a", now.Add(-30*time.Minute))
+	addUserActiveAt(s, "Bob", "zone-a", now.Add(-3*time.Hour))
+
+	tests := []struct {
+		within string
+		want   int
+	}{
+		{"1h", 1},
+		{"4h", 2},
+		{"10m", 0},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.within, func(t *testing.T) {
+			rr := doRequest(t, mux, "GET", fmt.Sprintf("/zones/zone-a/active?within=%s", tc.within), nil)
+			resp := parseResponse(t, rr)
+			data := resp.Data.([]interface{})
+			if len(data) != tc.want {
+				t.Errorf("expected %d users, got %d", tc.want, len(data))
+			}
+		})
+	}
+}
+
+func TestGetActiveUsers_InvalidWithin(t *testing.T) {
+	mux, _ := setupZoneTest(t, time.Now())
+
+	for _, within := range []string{"yesterday", "-1h", "0s"} {
+		t.Run(within, func(t *testing.T) {
+			rr := doRequest(t, mux, "GET", "/zones/zone-a/active?within="+within, nil)
+			if rr.Code != http.StatusUnprocessableEntity {
+				t.Errorf("status: got %d, want %d", rr.Code, http.StatusUnprocessableEntity)
+			}
+		})
+	}
+}
+
+// zoneMateNames returns the names in a GET /users/{id}/zone-mates response.
+func zoneMateNames(t *testing.T, resp models.APIResponse) []string {
+	t.Helper()
+	data, ok := resp.Data.([]interface{})
+	if !ok {
+		t.Fatalf("expected data to be a list, got %T", resp.Data)
+	}
+	names := make([]string, 0, len(data))
+	for _, item := range data {
+		names = append(names, item.(map[string]interface{})["name"].(string))
+	}
+	return names
+}
+
+func TestGetZoneMates_IgnoresSwipeHistory(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+	bobID, _ := createTestUser(t, mux, "Bob", "male", "zone-a", 30)
+	createTestUser(t, mux, "Carol", "female", "zone-a", 27)
+	createTestUser(t, mux, "Dave", "male", "zone-b", 31)
+
+	// Alice passes on Bob, so he leaves her feed...
+	rr := doRequest(t, mux, "POST", "/swipe", models.CreateSwipeRequest{
+		SwiperID: aliceID.String(), SwipedID: bobID.String(), Action: "PASS",
+	})
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("swipe: got %d, want %d", rr.Code, http.StatusCreated)
+	}
+	feed := parseResponse(t, doRequest(t, mux, "GET", "/feed?user_id="+aliceID.String(), nil))
+	if slices.Contains(zoneMateNames(t, feed), "Bob") {
+		t.Fatal("expected Bob to be filtered out of Alice's feed")
+	}
+
+	// ...but he is still one of her zone-mates.
+	rr = doRequest(t, mux, "GET", "/users/"+aliceID.String()+"/zone-mates", nil)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want %d", rr.Code, http.StatusOK)
+	}
+	resp := parseResponse(t, rr)
+	names := zoneMateNames(t, resp)
+	sort.Strings(names)
+	if want := []string{"Bob", "Carol"}; !slices.Equal(names, want) {
+		t.Errorf("expected zone-mates %v, got %v", want, names)
+	}
+	if total, _ := resp.Meta["total"].(float64); total != 2 {
+		t.Errorf("expected meta.total=2, got %v", resp.Meta["total"])
+	}
+}
+
+func TestGetZoneMates_Pagination(t *testing.T) {
+	mux := setupTestRouter(t)
+
+	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+	for i := 0; i < 5; i++ {
+		createTestUser(t, mux, fmt.Sprintf("User%d", i), "male", "zone-a", 30)
+	}
+
+	// Walking the pages two at a time visits every zone-mate exactly once.
+	var seen []string
+	for offset := 0; offset < 6; offset += 2 {
+		path := fmt.Sprintf("/users/%s/zone-mates?limit=2&offset=%d", aliceID, offset)
+		rr := doRequest(t, mux, "GET", path, nil)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("offset %d: got %d, want %d", offset, rr.Code, http.StatusOK)
+		}
+		resp := parseResponse(t, rr)
+		if total, _ := resp.Meta["total"].(float64); total != 5 {
+			t.Errorf("offset %d: expected meta.total=5, got %v", offset, resp.Meta["total"])
+		}
+		seen = append(seen, zoneMateNames(t, resp)...)
+	}
+	sort.Strings(seen)
+	want := []string{"User0", "User1", "User2", "User3", "User4"}
+	if !slices.Equal(seen, want) {
+		t.Errorf("expected pages to cover %v, got %v", want, seen)
+	}
+}
+
+func TestGetZoneMates_Errors(t *testing.T) {
+	mux := setupTestRouter(t)
+	aliceID, _ := createTestUser(t, mux, "Alice", "female", "zone-a", 28)
+
+	tests := []struct {
+		name string
+		path string
+		want int
+	}{
+		{"unknown user", "/users/" + uuid.New().String() + "/zone-mates", http.StatusNotFound},
+		{"invalid id", "/users/not-a-uuid/zone-mates", http.StatusNotFound},
+		{"limit too large", "/users/" + aliceID.String() + "/zone-mates?limit=101", http.StatusUnprocessableEntity},
+		{"negative offset", "/users/" + aliceID.String() + "/zone-mates?offset=-1", http.StatusUnprocessableEntity},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rr := doRequest(t, mux, "GET", tc.path, nil)
+			if rr.Code != tc.want {
+				t.Errorf("status: got %d, want %d", rr.Code, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+// Package logger configures the server's log output. It is a thin layer
+// over the standard library's log/slog package (Go 1.21+), which writes
+// structured records: a message plus key/value attributes, such as
+//
+//	slog.Info("server starting", "addr", ":8000")
+//
+// The same record can be rendered as human-readable text or as one JSON
+// object per line for log pipelines, and this package picks between them.
+package logger
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+)
+
+// Format selects how log records are written.
+type Format string
+
+const (
+	// FormatText writes key=value lines, e.g.
+	//   time=2024-06-01T12:00:00.000Z level=INFO msg="server starting" addr=:8000
+	FormatText Format = "text"
+
+	// FormatJSON writes one JSON object per line, e.g.
+	//   {"time":"2024-06-01T12:00:00Z","level":"INFO","msg":"server starting","addr":":8000"}
+	FormatJSON Format = "json"
+)
+
+// ParseFormat converts a LOG_FORMAT value into a Format. An empty string
+// means the default, FormatText.
+func ParseFormat(raw string) (Format, error) {
+	switch Format(raw) {
+	case "", FormatText:
+		return FormatText, nil
+	case FormatJSON:
+		return FormatJSON, nil
+	default:
+		return "", fmt.Errorf("log format must be %q or %q, got %q", FormatText, FormatJSON, raw)
+	}
+}
+
+// New returns a logger that writes records to w in the given format. Every
+// record has "time", "level", and "msg" fields followed by its attributes.
+func New(w io.Writer, format Format) *slog.Logger {
+	if format == FormatJSON {
+		return slog.New(slog.NewJSONHandler(w, nil))
+	}
+	return slog.New(slog.NewTextHandler(w, nil))
+}
+
+// Setup makes a logger for w and format the process-wide default. Both the
+// slog package functions (slog.Info, slog.Error, ...) and the older log
+// package (log.Printf, log.Fatalf) then write through it, so every log
+// line shares one format.
+func Setup(w io.Writer, format Format) {
+	slog.SetDefault(New(w, format))
+}
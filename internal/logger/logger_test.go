@@ -0,0 +1,97 @@
+// This file contains tests for the logger package: format parsing and the
+// shape of the records each format writes.
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    Format
+		wantErr bool
+	}{
+		{"", FormatText, false},
+		{"text", FormatText, false},
+		{"json", FormatJSON, false},
+		{"JSON", "", true},
+		{"xml", "", true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.raw, func(t *testing.T) {
+			got, err := ParseFormat(tc.raw)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("error: got %v, want error %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("format: got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNew_JSONFormatWritesParseableLines(t *testing.T) {
+	var buf bytes.Buffer
+	log := New(&buf, FormatJSON)
+
+	log.Info("server starting", "addr", ":8000")
+	log.Error("internal error", "incident_id", "abc", "error", errors.New("boom"))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d: %q", len(lines), buf.String())
+	}
+
+	tests := []struct {
+		name  string
+		line  string
+		level string
+		msg   string
+		attrs map[string]string
+	}{
+		{"info", lines[0], "INFO", "server starting", map[string]string{"addr": ":8000"}},
+		{"error", lines[1], "ERROR", "internal error", map[string]string{"incident_id": "abc", "error": "boom"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var record map[string]any
+			if err := json.Unmarshal([]byte(tc.line), &record); err != nil {
+				t.Fatalf("line is not valid JSON: %v: %s", err, tc.line)
+			}
+			if _, ok := record["time"].(string); !ok {
+				t.Errorf("expected a time field, got %v", record["time"])
+			}
+			if record["level"] != tc.level {
+				t.Errorf("level: got %v, want %s", record["level"], tc.level)
+			}
+			if record["msg"] != tc.msg {
+				t.Errorf("msg: got %v, want %s", record["msg"], tc.msg)
+			}
+			for key, want := range tc.attrs {
+				if record[key] != want {
+					t.Errorf("%s: got %v, want %s", key, record[key], want)
+				}
+			}
+		})
+	}
+}
+
+func TestNew_TextFormatWritesKeyValues(t *testing.T) {
+	var buf bytes.Buffer
+	New(&buf, FormatText).Info("server starting", "addr", ":8000")
+
+	line := buf.String()
+	for _, want := range []string{"level=INFO", `msg="server starting"`, "addr=:8000"} {
+		if !strings.Contains(line, want) {
+			t.Errorf("expected %q in %q", want, line)
+		}
+	}
+	if json.Valid([]byte(line)) {
+		t.Errorf("expected text, not JSON: %q", line)
+	}
+}
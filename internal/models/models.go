@@ -11,7 +11,12 @@
 package models
 
 import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 )
@@ -61,6 +66,152 @@ type User struct {
 	Age    int       `json:"age"`
 	Gender string    `json:"gender"`
 	ZoneID string    `json:"zone_id"`
+
+	// Bio is a short free-text introduction shown on the profile. It is
+	// optional and at most MaxBioLength characters.
+	Bio string `json:"bio,omitempty"`
+
+	// LastActiveAt records when the user was last seen doing something in
+	// the app. It is set when the profile is created and updated whenever
+	// the user swipes or fetches their feed, at most once a minute.
+	LastActiveAt time.Time `json:"last_active_at"`
+
+	// JoinedAt is when the profile was created. Unlike LastActiveAt it
+	// never changes. Users saved before it existed have the zero time.
+	JoinedAt time.Time `json:"joined_at"`
+
+	// NotifyOnLike and NotifyOnMatch are the user's notification
+	// preferences. Go's zero value for bool is false, so the code that
+	// creates users must set them to true explicitly (opted in by default).
+	NotifyOnLike  bool `json:"notify_on_like"`
+	NotifyOnMatch bool `json:"notify_on_match"`
+
+	// InterestedIn is the gender the user wants to see in their feed, or
+	// InterestedInEveryone. An empty value is treated as "everyone".
+	InterestedIn string `json:"interested_in"`
+
+	// MinAge and MaxAge bound the ages the user wants to see in their feed,
+	// inclusive. Zero means no bound on that side.
+	MinAge int `json:"min_age"`
+	MaxAge int `json:"max_age"`
+
+	// Tags are the user's hobbies and interests. The feed can rank
+	// candidates by how many tags they share with the requester.
+	Tags []string `json:"tags,omitempty"`
+
+	// QuietHours is a daily "do not disturb" window during which match
+	// notifications are held back, or nil if the user hasn't set one.
+	QuietHours *QuietHours `json:"quiet_hours,omitempty"`
+
+	// Latitude and Longitude locate the user in decimal degrees. They are
+	// pointers because 0 is a real coordinate: nil means the user hasn't
+	// shared a location. Either both are set or neither is.
+	Latitude  *float64 `json:"latitude,omitempty"`
+	Longitude *float64 `json:"longitude,omitempty"`
+
+	// MaxDistanceKm limits the feed to candidates within this many
+	// kilometers. It only applies when both users have a location; zero
+	// means no distance limit, and the feed falls back to zones.
+	MaxDistanceKm float64 `json:"max_distance_km,omitempty"`
+
+	// BoostUntil is when the user's current boost ends, or nil if they have
+	// never boosted. While it is in the future, the user is shown ahead of
+	// everyone else in feeds; after that it is simply ignored.
+	BoostUntil *time.Time `json:"boost_until,omitempty"`
+}
+
+// BoostedAt reports whether the user has a boost still running at now.
+func (u User) BoostedAt(now time.Time) bool {
+	return u.BoostUntil != nil && now.Before(*u.BoostUntil)
+}
+
+// HasLocation reports whether the user has shared their coordinates.
+func (u User) HasLocation() bool {
+	return u.Latitude != nil && u.Longitude != nil
+}
+
+// InterestedInEveryone is the InterestedIn value that disables gender
+// filtering in the feed.
+const InterestedInEveryone = "everyone"
+
+// QuietHours is a daily window, in the user's own time zone, when they
+// don't want to be notified. Start and End are "HH:MM" on a 24-hour clock.
+// The window includes Start and excludes End, and it may wrap past
+// midnight: 22:00–07:00 covers late evening through early morning.
+type QuietHours struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+
+	// Timezone is an IANA zone name such as "America/New_York". Empty
+	// means UTC.
+	Timezone string `json:"timezone,omitempty"`
+}
+
+// quietHoursLayout is the time.Parse layout for Start and End. Go layouts
+// are written as the reference time, Mon Jan 2 15:04:05 2006.
+const quietHoursLayout = "15:04"
+
+// Validate checks that Start and End are valid, distinct times of day and
+// that Timezone names a known zone.
+func (q QuietHours) Validate() []string {
+	var errs []string
+	start, err := time.Parse(quietHoursLayout, q.Start)
+	if err != nil {
+		errs = append(errs, "quiet_hours.start must be a time of day such as 22:00")
+	}
+	end, err2 := time.Parse(quietHoursLayout, q.End)
+	if err2 != nil {
+		errs = append(errs, "quiet_hours.end must be a time of day such as 07:00")
+	}
+	if err == nil && err2 == nil && start.Equal(end) {
+		errs = append(errs, "quiet_hours.start and quiet_hours.end must differ")
+	}
+	if _, err := time.LoadLocation(q.Timezone); err != nil {
+		errs = append(errs, fmt.Sprintf("quiet_hours.timezone %q is not a known time zone", q.Timezone))
+	}
+	return errs
+}
+
+// Until reports whether t falls inside the quiet window and, if it does,
+// when the window ends. It returns false for windows that fail Validate.
+func (q QuietHours) Until(t time.Time) (time.Time, bool) {
+	loc, err := time.LoadLocation(q.Timezone)
+	if err != nil {
+		return time.Time{}, false
+	}
+	start, err := time.Parse(quietHoursLayout, q.Start)
+	if err != nil {
+		return time.Time{}, false
+	}
+	end, err := time.Parse(quietHoursLayout, q.End)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	// Work in minutes since local midnight.
+	local := t.In(loc)
+	now := local.Hour()*60 + local.Minute()
+	from := start.Hour()*60 + start.Minute()
+	to := end.Hour()*60 + end.Minute()
+
+	// endOn returns the window's end on the given day offset from today.
+	// time.Date normalizes an out-of-range day, so day+1 on the 31st is
+	// the 1st of next month.
+	endOn := func(days int) time.Time {
+		return time.Date(local.Year(), local.Month(), local.Day()+days, end.Hour(), end.Minute(), 0, 0, loc)
+	}
+
+	switch {
+	case from < to && now >= from && now < to:
+		return endOn(0), true
+	case from > to && now >= from:
+		// Wrapping window, evening part: it ends tomorrow.
+		return endOn(1), true
+	case from > to && now < to:
+		// Wrapping window, morning part: it ends today.
+		return endOn(0), true
+	}
+	return time.Time{}, false
 }
 
 // Swipe records a single swipe action — one user expressing interest (LIKE)
@@ -72,12 +223,147 @@ type Swipe struct {
 	Timestamp time.Time   `json:"timestamp"`
 }
 
+// Block records that one user has blocked another. A block works both ways:
+// neither user sees the other in their feed, and neither can swipe on the
+// other.
+type Block struct {
+	BlockerID uuid.UUID `json:"blocker_id"`
+	BlockedID uuid.UUID `json:"blocked_id"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Report is one user flagging another's profile for trust-and-safety
+// review, with a free-text reason.
+type Report struct {
+	ID         uuid.UUID `json:"id"`
+	ReporterID uuid.UUID `json:"reporter_id"`
+	ReportedID uuid.UUID `json:"reported_id"`
+	Reason     string    `json:"reason"`
+	Timestamp  time.Time `json:"timestamp"`
+}
+
 // Match represents a mutual connection between two users. A match is created
 // when both users have LIKED each other (bidirectional match detection).
+//
+// ID is assigned by the server so clients can refer to a specific match
+// (for example, to delete it).
 type Match struct {
+	ID        uuid.UUID `json:"id"`
 	User1ID   uuid.UUID `json:"user1_id"`
 	User2ID   uuid.UUID `json:"user2_id"`
 	Timestamp time.Time `json:"timestamp"`
+
+	// InitiatorID is the user who completed the match: the second of the
+	// two to LIKE the other, whose swipe created it. Matches saved before
+	// this field existed have uuid.Nil here.
+	InitiatorID uuid.UUID `json:"initiator_id"`
+}
+
+// ---------------------------------------------------------------------------
+// Gender normalization
+// ---------------------------------------------------------------------------
+
+// Gender is one of the built-in canonical genders. Like SwipeAction, it is
+// a string type with a fixed set of constants.
+//
+// User.Gender stays a plain string because deployments can add genders of
+// their own with GENDER_SYNONYMS (see NewGenderNormalizer), so the stored
+// value isn't always one of these constants.
+type Gender string
+
+const (
+	GenderMale      Gender = "male"
+	GenderFemale    Gender = "female"
+	GenderNonbinary Gender = "nonbinary"
+	GenderOther     Gender = "other"
+)
+
+// builtinGenders lists every built-in gender. It is the one list both
+// IsValid and NewGenderNormalizer draw on, so they can't disagree.
+var builtinGenders = []Gender{GenderMale, GenderFemale, GenderNonbinary, GenderOther}
+
+// IsValid reports whether g is one of the built-in genders. The comparison
+// is exact: "Male" is not valid until normalized. Request handling goes
+// through a GenderNormalizer instead, which also trims, lowercases, and
+// accepts synonyms and configured genders.
+func (g Gender) IsValid() bool {
+	return slices.Contains(builtinGenders, g)
+}
+
+// DefaultGenderSynonyms maps common spellings to the canonical genders
+// "male", "female", and "nonbinary" (and "everyone", which is only valid as
+// an InterestedIn preference). Canonical values map to themselves implicitly.
+var DefaultGenderSynonyms = map[string]string{
+	"man": "male", "men": "male", "m": "male",
+	"woman": "female", "women": "female", "w": "female", "f": "female",
+	"non-binary": "nonbinary", "nb": "nonbinary", "enby": "nonbinary",
+	"all": InterestedInEveryone, "any": InterestedInEveryone, "both": InterestedInEveryone,
+}
+
+// GenderNormalizer rewrites free-form gender strings to canonical values so
+// that preference filtering compares like with like ("men" and "male" are
+// the same thing). It is read-only after construction, so one instance can
+// be shared by concurrent requests.
+type GenderNormalizer struct {
+	synonyms  map[string]string
+	canonical map[string]struct{}
+}
+
+// NewGenderNormalizer builds a normalizer from DefaultGenderSynonyms plus
+// the given extra synonyms, which take precedence. A synonym may point at a
+// value that isn't one of the defaults; that value then becomes canonical
+// too, which is how deployments add genders. Keys and values are matched
+// case-insensitively.
+func NewGenderNormalizer(extra map[string]string) *GenderNormalizer {
+	n := &GenderNormalizer{
+		synonyms:  make(map[string]string),
+		canonical: make(map[string]struct{}),
+	}
+	// The built-in genders are accepted as-is, even with no synonym entry.
+	for _, g := range builtinGenders {
+		n.canonical[string(g)] = struct{}{}
+	}
+	// Apply defaults first so extra entries overwrite them.
+	for _, m := range []map[string]string{DefaultGenderSynonyms, extra} {
+		for from, to := range m {
+			to = normalizeGenderKey(to)
+			n.synonyms[normalizeGenderKey(from)] = to
+			if to != InterestedInEveryone {
+				n.canonical[to] = struct{}{}
+			}
+		}
+	}
+	return n
+}
+
+// Gender returns the canonical form of a user's gender. The boolean is
+// false if the value isn't recognized.
+func (n *GenderNormalizer) Gender(value string) (string, bool) {
+	key := n.resolve(value)
+	_, ok := n.canonical[key]
+	return key, ok
+}
+
+// InterestedIn is like Gender but also accepts "everyone" (or a synonym).
+func (n *GenderNormalizer) InterestedIn(value string) (string, bool) {
+	if key := n.resolve(value); key == InterestedInEveryone {
+		return key, true
+	}
+	return n.Gender(value)
+}
+
+// resolve normalizes a value and follows its synonym, if it has one.
+func (n *GenderNormalizer) resolve(value string) string {
+	key := normalizeGenderKey(value)
+	if to, ok := n.synonyms[key]; ok {
+		return to
+	}
+	return key
+}
+
+// normalizeGenderKey trims surrounding whitespace and lowercases a value.
+func normalizeGenderKey(value string) string {
+	return strings.ToLower(strings.TrimSpace(value))
 }
 
 // ---------------------------------------------------------------------------
@@ -93,6 +379,52 @@ type CreateUserRequest struct {
 	Age    int    `json:"age"`
 	Gender string `json:"gender"`
 	ZoneID string `json:"zone_id"`
+
+	// Bio is optional; see MaxBioLength.
+	Bio string `json:"bio"`
+
+	// InterestedIn is optional and defaults to InterestedInEveryone.
+	InterestedIn string `json:"interested_in"`
+
+	// MinAge and MaxAge are optional; omitted (zero) means unbounded.
+	MinAge int `json:"min_age"`
+	MaxAge int `json:"max_age"`
+
+	// Tags are optional hobbies and interests. They are stored in
+	// normalized form; see NormalizedTags.
+	Tags []string `json:"tags"`
+
+	// Latitude and Longitude are optional, but must be given together.
+	Latitude  *float64 `json:"latitude"`
+	Longitude *float64 `json:"longitude"`
+
+	// MaxDistanceKm is optional; omitted (zero) means no distance limit.
+	MaxDistanceKm float64 `json:"max_distance_km"`
+}
+
+// MinimumAge is the youngest a user may be. It is a hard safety rule, not a
+// setting: CreateUserRequest.Validate rejects younger users, and the swipe
+// service refuses to pair anyone younger who reaches the store another way,
+// such as through an imported data file.
+const MinimumAge = 18
+
+// MaximumAge is the oldest age CreateUserRequest.Validate accepts. Anything
+// higher is a typo or a joke, not a real person.
+const MaximumAge = 120
+
+// MaxBioLength is the longest a profile bio may be, in characters.
+const MaxBioLength = 500
+
+// Limits on profile tags, checked by CreateUserRequest.Validate.
+const (
+	MaxTags      = 20
+	MaxTagLength = 30
+)
+
+// NormalizeTag puts a tag in the form it is stored and compared in:
+// trimmed and lowercase, so "Hiking " and "hiking" are the same tag.
+func NormalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
 }
 
 // Validate checks that all required fields in a CreateUserRequest are present
@@ -108,6 +440,10 @@ func (r CreateUserRequest) Validate() []string {
 	}
 	if r.Age <= 0 {
 		errs = append(errs, "age must be a positive integer")
+	} else if r.Age < MinimumAge {
+		errs = append(errs, fmt.Sprintf("age must be at least %d", MinimumAge))
+	} else if r.Age > MaximumAge {
+		errs = append(errs, fmt.Sprintf("age must be %d or less", MaximumAge))
 	}
 	if r.Gender == "" {
 		errs = append(errs, "gender is required")
@@ -115,10 +451,108 @@ func (r CreateUserRequest) Validate() []string {
 	if r.ZoneID == "" {
 		errs = append(errs, "zone_id is required")
 	}
+	if utf8.RuneCountInString(r.Bio) > MaxBioLength {
+		errs = append(errs, fmt.Sprintf("bio must be at most %d characters", MaxBioLength))
+	}
+	// InterestedIn may be omitted, but a value made only of spaces is
+	// almost certainly a client bug rather than a real preference.
+	if r.InterestedIn != "" && strings.TrimSpace(r.InterestedIn) == "" {
+		errs = append(errs, "interested_in must not be blank")
+	}
+	// Age bounds are optional, so zero is allowed, but a negative bound or
+	// an inverted range can never match anyone.
+	if r.MinAge < 0 {
+		errs = append(errs, "min_age must be a positive integer")
+	}
+	if r.MaxAge < 0 {
+		errs = append(errs, "max_age must be a positive integer")
+	}
+	if r.MinAge > 0 && r.MaxAge > 0 && r.MinAge > r.MaxAge {
+		errs = append(errs, "min_age must not be greater than max_age")
+	}
+	if (r.Latitude == nil) != (r.Longitude == nil) {
+		errs = append(errs, "latitude and longitude must be given together")
+	}
+	if r.Latitude != nil && (*r.Latitude < -90 || *r.Latitude > 90) {
+		errs = append(errs, "latitude must be between -90 and 90")
+	}
+	if r.Longitude != nil && (*r.Longitude < -180 || *r.Longitude > 180) {
+		errs = append(errs, "longitude must be between -180 and 180")
+	}
+	if r.MaxDistanceKm < 0 {
+		errs = append(errs, "max_distance_km must not be negative")
+	}
+	if len(r.Tags) > MaxTags {
+		errs = append(errs, fmt.Sprintf("tags must have at most %d entries", MaxTags))
+	}
+	for _, tag := range r.Tags {
+		// Length is counted in characters (runes), not bytes, so tags in
+		// any language get the same allowance.
+		normalized := NormalizeTag(tag)
+		if normalized == "" {
+			errs = append(errs, "tags must not contain blank entries")
+		} else if utf8.RuneCountInString(normalized) > MaxTagLength {
+			errs = append(errs, fmt.Sprintf("tag %q must be at most %d characters", tag, MaxTagLength))
+		}
+	}
 
 	return errs
 }
 
+// NormalizedTags returns the request's tags normalized with NormalizeTag,
+// in their original order with duplicates removed. It returns nil if there
+// are no tags. Call it only after Validate has passed.
+func (r CreateUserRequest) NormalizedTags() []string {
+	var tags []string
+	seen := make(map[string]struct{}, len(r.Tags))
+	for _, tag := range r.Tags {
+		normalized := NormalizeTag(tag)
+		if _, dup := seen[normalized]; dup {
+			continue
+		}
+		seen[normalized] = struct{}{}
+		tags = append(tags, normalized)
+	}
+	return tags
+}
+
+// InterestedInOrDefault returns the requested gender preference, or
+// InterestedInEveryone if none was given.
+func (r CreateUserRequest) InterestedInOrDefault() string {
+	if r.InterestedIn == "" {
+		return InterestedInEveryone
+	}
+	return r.InterestedIn
+}
+
+// UpdatePreferencesRequest is the JSON body for updating a user's
+// notification preferences. The fields are pointers so a client can change
+// one preference without resending the other: nil means "leave unchanged".
+//
+// QuietHours with an empty Start and End turns quiet hours off.
+type UpdatePreferencesRequest struct {
+	NotifyOnLike  *bool       `json:"notify_on_like"`
+	NotifyOnMatch *bool       `json:"notify_on_match"`
+	QuietHours    *QuietHours `json:"quiet_hours"`
+}
+
+// Validate checks that at least one preference is being changed and that
+// any quiet hours are valid.
+func (r UpdatePreferencesRequest) Validate() []string {
+	if r.NotifyOnLike == nil && r.NotifyOnMatch == nil && r.QuietHours == nil {
+		return []string{"at least one of notify_on_like, notify_on_match, or quiet_hours is required"}
+	}
+	if r.QuietHours != nil && !r.ClearsQuietHours() {
+		return r.QuietHours.Validate()
+	}
+	return nil
+}
+
+// ClearsQuietHours reports whether the request turns quiet hours off.
+func (r UpdatePreferencesRequest) ClearsQuietHours() bool {
+	return r.QuietHours != nil && r.QuietHours.Start == "" && r.QuietHours.End == ""
+}
+
 // CreateSwipeRequest is the JSON body expected when recording a swipe.
 type CreateSwipeRequest struct {
 	SwiperID string `json:"swiper_id"`
@@ -128,6 +562,14 @@ type CreateSwipeRequest struct {
 
 // Validate checks that the swipe request has valid UUIDs and a recognized action.
 func (r CreateSwipeRequest) Validate() (swiperID, swipedID uuid.UUID, action SwipeAction, errs []string) {
+	return r.ValidateWithAliases(nil)
+}
+
+// ValidateWithAliases is Validate for servers that accept alternative
+// action strings: an action found in aliases is translated to its
+// canonical SwipeAction before being checked. A nil map accepts only LIKE
+// and PASS.
+func (r CreateSwipeRequest) ValidateWithAliases(aliases SwipeActionAliases) (swiperID, swipedID uuid.UUID, action SwipeAction, errs []string) {
 	var err error
 
 	// Parse and validate the swiper UUID.
@@ -142,8 +584,13 @@ func (r CreateSwipeRequest) Validate() (swiperID, swipedID uuid.UUID, action Swi
 		errs = append(errs, "swiped_id must be a valid UUID")
 	}
 
-	// Validate the action is a known SwipeAction.
+	// Validate the action is a known SwipeAction, after translating any
+	// configured alias. The comma-ok lookup on a nil map is safe and
+	// simply reports false.
 	action = SwipeAction(r.Action)
+	if canonical, ok := aliases[r.Action]; ok {
+		action = canonical
+	}
 	if !action.IsValid() {
 		errs = append(errs, "action must be LIKE or PASS")
 	}
@@ -151,6 +598,281 @@ func (r CreateSwipeRequest) Validate() (swiperID, swipedID uuid.UUID, action Swi
 	return swiperID, swipedID, action, errs
 }
 
+// CreateBlockRequest is the JSON body expected by POST /block.
+type CreateBlockRequest struct {
+	BlockerID string `json:"blocker_id"`
+	BlockedID string `json:"blocked_id"`
+}
+
+// Validate checks that the request names two different users by valid
+// UUIDs.
+func (r CreateBlockRequest) Validate() (blockerID, blockedID uuid.UUID, errs []string) {
+	var err error
+
+	blockerID, err = uuid.Parse(r.BlockerID)
+	if err != nil {
+		errs = append(errs, "blocker_id must be a valid UUID")
+	}
+	blockedID, err = uuid.Parse(r.BlockedID)
+	if err != nil {
+		errs = append(errs, "blocked_id must be a valid UUID")
+	}
+	if len(errs) == 0 && blockerID == blockedID {
+		errs = append(errs, "cannot block yourself")
+	}
+
+	return blockerID, blockedID, errs
+}
+
+// MaxReportReasonLength is the longest a report reason may be, in
+// characters.
+const MaxReportReasonLength = 1000
+
+// CreateReportRequest is the JSON body expected by POST /reports.
+type CreateReportRequest struct {
+	ReporterID string `json:"reporter_id"`
+	ReportedID string `json:"reported_id"`
+	Reason     string `json:"reason"`
+}
+
+// Validate checks that the request names two different users by valid
+// UUIDs and gives a reason that isn't blank or too long. The returned
+// reason has surrounding whitespace trimmed.
+func (r CreateReportRequest) Validate() (reporterID, reportedID uuid.UUID, reason string, errs []string) {
+	var err error
+
+	reporterID, err = uuid.Parse(r.ReporterID)
+	if err != nil {
+		errs = append(errs, "reporter_id must be a valid UUID")
+	}
+	reportedID, err = uuid.Parse(r.ReportedID)
+	if err != nil {
+		errs = append(errs, "reported_id must be a valid UUID")
+	}
+	if len(errs) == 0 && reporterID == reportedID {
+		errs = append(errs, "cannot report yourself")
+	}
+
+	reason = strings.TrimSpace(r.Reason)
+	if reason == "" {
+		errs = append(errs, "reason is required")
+	} else if utf8.RuneCountInString(reason) > MaxReportReasonLength {
+		errs = append(errs, fmt.Sprintf("reason must be at most %d characters", MaxReportReasonLength))
+	}
+
+	return reporterID, reportedID, reason, errs
+}
+
+// UndoSwipeRequest is the JSON body expected by POST /swipe/undo.
+type UndoSwipeRequest struct {
+	UserID string `json:"user_id"`
+}
+
+// Validate checks that the request names a user by a valid UUID.
+func (r UndoSwipeRequest) Validate() (uuid.UUID, []string) {
+	if r.UserID == "" {
+		return uuid.Nil, []string{"user_id is required"}
+	}
+	id, err := uuid.Parse(r.UserID)
+	if err != nil {
+		return uuid.Nil, []string{"user_id must be a valid UUID"}
+	}
+	return id, nil
+}
+
+// MarkMatchSeenRequest is the JSON body expected by POST /matches/{id}/seen.
+type MarkMatchSeenRequest struct {
+	UserID string `json:"user_id"`
+}
+
+// Validate checks that the request names a user by a valid UUID.
+func (r MarkMatchSeenRequest) Validate() (uuid.UUID, []string) {
+	if r.UserID == "" {
+		return uuid.Nil, []string{"user_id is required"}
+	}
+	id, err := uuid.Parse(r.UserID)
+	if err != nil {
+		return uuid.Nil, []string{"user_id must be a valid UUID"}
+	}
+	return id, nil
+}
+
+// Boost lengths for POST /boost, in minutes.
+const (
+	DefaultBoostMinutes = 30
+	MaxBoostMinutes     = 24 * 60
+)
+
+// BoostRequest is the JSON body expected by POST /boost. Minutes is
+// optional and defaults to DefaultBoostMinutes.
+type BoostRequest struct {
+	UserID  string `json:"user_id"`
+	Minutes int    `json:"minutes"`
+}
+
+// Validate checks the request and returns the user ID and the boost length
+// (with the default applied).
+func (r BoostRequest) Validate() (userID uuid.UUID, duration time.Duration, errs []string) {
+	if r.UserID == "" {
+		errs = append(errs, "user_id is required")
+	} else if id, err := uuid.Parse(r.UserID); err != nil {
+		errs = append(errs, "user_id must be a valid UUID")
+	} else {
+		userID = id
+	}
+
+	minutes := r.Minutes
+	if minutes == 0 {
+		minutes = DefaultBoostMinutes
+	}
+	if minutes < 1 || minutes > MaxBoostMinutes {
+		errs = append(errs, fmt.Sprintf("minutes must be between 1 and %d", MaxBoostMinutes))
+	}
+
+	return userID, time.Duration(minutes) * time.Minute, errs
+}
+
+// SwipeActionAliases maps action strings sent by some clients, such as
+// "YES" or "1", to the canonical action they mean. Keys match exactly.
+type SwipeActionAliases map[string]SwipeAction
+
+// NewSwipeActionAliases builds SwipeActionAliases from alias → action
+// pairs, such as {"YES": "LIKE"}. Surrounding spaces are trimmed and the
+// action is case-insensitive. It returns an error if an action isn't LIKE
+// or PASS, or if an alias would redefine LIKE or PASS themselves.
+func NewSwipeActionAliases(pairs map[string]string) (SwipeActionAliases, error) {
+	aliases := make(SwipeActionAliases, len(pairs))
+	for alias, target := range pairs {
+		alias = strings.TrimSpace(alias)
+		action := SwipeAction(strings.ToUpper(strings.TrimSpace(target)))
+		if !action.IsValid() {
+			return nil, fmt.Errorf("alias %q maps to %q, which is not LIKE or PASS", alias, target)
+		}
+		if SwipeAction(alias).IsValid() {
+			return nil, fmt.Errorf("alias %q would redefine a canonical action", alias)
+		}
+		aliases[alias] = action
+	}
+	return aliases, nil
+}
+
+// FeedEntry is a single candidate in a discovery feed. It embeds User, so
+// the user's fields are "promoted": entry.Name works just like user.Name, and
+// encoding/json flattens them into the same JSON object as the extra fields.
+type FeedEntry struct {
+	User
+
+	// Reasons explains why the candidate is being shown (for example,
+	// "same zone" or "liked you"). It is only populated on request and is
+	// omitted from the JSON output when empty.
+	Reasons []string `json:"reasons,omitempty"`
+
+	// Online reports whether the candidate was active within the feed
+	// service's online window at the time the feed was built.
+	Online bool `json:"online"`
+
+	// DistanceKm is how far the candidate is from the requester, rounded
+	// to 0.1 km. It is omitted unless both users have a location.
+	DistanceKm *float64 `json:"distance_km,omitempty"`
+}
+
+// UserExport is the data-portability document returned by
+// GET /users/{id}/export. It gathers everything the system stores about a
+// single user.
+type UserExport struct {
+	Profile       User    `json:"profile"`
+	Swipes        []Swipe `json:"swipes"`
+	LikesReceived []Swipe `json:"likes_received"`
+	Matches       []Match `json:"matches"`
+}
+
+// MatchWithProfile is one entry in a user's match list: the match joined
+// with the profile of the other person, so clients don't need a second
+// request per match to show who it is.
+//
+// Seen reports whether the requesting user has marked the match seen; the
+// partner's receipt is separate.
+type MatchWithProfile struct {
+	MatchID   uuid.UUID `json:"match_id"`
+	User      User      `json:"user"`
+	MatchedAt time.Time `json:"matched_at"`
+	Seen      bool      `json:"seen"`
+}
+
+// RecentMatch is an anonymized match for the public "recent matches"
+// ticker. It deliberately carries no names or IDs: only where and when.
+type RecentMatch struct {
+	// Zones are the participants' zones, sorted and without duplicates,
+	// so usually a single zone.
+	Zones     []string  `json:"zones"`
+	MatchedAt time.Time `json:"matched_at"`
+}
+
+// Stats holds store-wide totals for a dashboard, as returned by
+// GET /stats.
+type Stats struct {
+	TotalUsers   int `json:"total_users"`
+	TotalSwipes  int `json:"total_swipes"`
+	Likes        int `json:"likes"`
+	Passes       int `json:"passes"`
+	TotalMatches int `json:"total_matches"`
+
+	// UsersByZone counts users per zone ID, as stored (zone aliases are
+	// not resolved).
+	UsersByZone map[string]int `json:"users_by_zone"`
+}
+
+// MatchTrail explains how a match came about: the match record plus the
+// LIKE from each side that produced it, oldest first.
+type MatchTrail struct {
+	Match  Match   `json:"match"`
+	Swipes []Swipe `json:"swipes"`
+}
+
+// MaxBatchUsers is the most IDs one POST /users/batch request may ask for,
+// so a single request can't dump the whole user table.
+const MaxBatchUsers = 100
+
+// BatchUsersRequest is the JSON body for POST /users/batch.
+type BatchUsersRequest struct {
+	IDs []string `json:"ids"`
+}
+
+// Validate parses every ID, reporting each malformed one. Duplicate IDs are
+// collapsed, keeping the order in which each ID first appears. At most
+// MaxBatchUsers IDs are allowed, counting duplicates.
+func (r BatchUsersRequest) Validate() (ids []uuid.UUID, errs []string) {
+	if len(r.IDs) == 0 {
+		return nil, []string{"ids must contain at least one id"}
+	}
+	if len(r.IDs) > MaxBatchUsers {
+		return nil, []string{fmt.Sprintf("ids must contain at most %d ids", MaxBatchUsers)}
+	}
+
+	seen := make(map[uuid.UUID]struct{}, len(r.IDs))
+	for _, raw := range r.IDs {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("ids contains an invalid UUID: %q", raw))
+			continue
+		}
+		if _, dup := seen[id]; dup {
+			continue
+		}
+		seen[id] = struct{}{}
+		ids = append(ids, id)
+	}
+	return ids, errs
+}
+
+// BatchUsersResponse is returned by POST /users/batch. Users holds the
+// profiles that were found, in request order; Missing lists the IDs that
+// didn't match any user.
+type BatchUsersResponse struct {
+	Users   []User      `json:"users"`
+	Missing []uuid.UUID `json:"missing"`
+}
+
 // ---------------------------------------------------------------------------
 // API response envelope
 // ---------------------------------------------------------------------------
@@ -166,6 +888,28 @@ type APIResponse struct {
 	Data   interface{}    `json:"data"`
 	Meta   map[string]any `json:"meta"`
 	Errors []APIError     `json:"errors"`
+
+	// OmitNullData drops the "data" key from the JSON when Data is nil,
+	// for clients that can't handle "data": null. The `json:"-"` tag keeps
+	// the flag itself out of the output.
+	OmitNullData bool `json:"-"`
+}
+
+// MarshalJSON implements json.Marshaler so that OmitNullData can remove the
+// "data" key. A plain `omitempty` tag can't be used because it would be
+// unconditional, and by default the envelope always includes "data".
+func (r APIResponse) MarshalJSON() ([]byte, error) {
+	if r.OmitNullData && r.Data == nil {
+		return json.Marshal(struct {
+			Meta   map[string]any `json:"meta"`
+			Errors []APIError     `json:"errors"`
+		}{r.Meta, r.Errors})
+	}
+
+	// envelope has the same fields but none of the methods, so marshaling
+	// it uses the default encoding instead of calling MarshalJSON again.
+	type envelope APIResponse
+	return json.Marshal(envelope(r))
 }
 
 // APIError represents a single error message in the response envelope.
@@ -188,6 +932,27 @@ func NewSuccessResponse(data interface{}, meta map[string]any) APIResponse {
 	}
 }
 
+// NewPaginatedResponse builds a successful response for one page of a list.
+// data is the page itself: the items from offset up to offset+limit of a
+// list of total items. The meta block says where the page sits in the list:
+//   - count: how many items are on this page
+//   - total: how many items the whole list holds
+//   - limit, offset: the page window that was asked for
+//   - has_more: whether a later page has items
+//
+// count is worked out from the window rather than from data, which can be a
+// slice of any type; callers must not trim the page any further.
+func NewPaginatedResponse(data interface{}, total, limit, offset int) APIResponse {
+	count := max(0, min(limit, total-offset))
+	return NewSuccessResponse(data, map[string]any{
+		"count":    count,
+		"total":    total,
+		"limit":    limit,
+		"offset":   offset,
+		"has_more": offset+count < total,
+	})
+}
+
 // NewErrorResponse is a helper that builds an error API response with one
 // or more error messages.
 func NewErrorResponse(messages ...string) APIResponse {
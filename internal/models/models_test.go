@@ -0,0 +1,68 @@
+// This file contains unit tests for the model helpers.
+package models
+
+import "testing"
+
+func TestGender_IsValid(t *testing.T) {
+	tests := []struct {
+		gender Gender
+		want   bool
+	}{
+		{GenderMale, true},
+		{GenderFemale, true},
+		{GenderNonbinary, true},
+		{GenderOther, true},
+		{"Male", false},
+		{"man", false},
+		{"", false},
+	}
+	for _, tc := range tests {
+		t.Run(string(tc.gender), func(t *testing.T) {
+			if got := tc.gender.IsValid(); got != tc.want {
+				t.Errorf("IsValid(%q): got %v, want %v", tc.gender, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGenderNormalizer_AcceptsEveryValidGender(t *testing.T) {
+	n := NewGenderNormalizer(nil)
+	for _, g := range builtinGenders {
+		if got, ok := n.Gender(string(g)); !ok || got != string(g) {
+			t.Errorf("Gender(%q): got (%q, %v), want (%q, true)", g, got, ok, g)
+		}
+	}
+}
+
+func TestNewPaginatedResponse(t *testing.T) {
+	tests := []struct {
+		name                 string
+		total, limit, offset int
+		wantCount            int
+		wantMore             bool
+	}{
+		{"first page", 25, 10, 0, 10, true},
+		{"partial last page", 25, 10, 20, 5, false},
+		{"last page ends at the total", 20, 10, 10, 10, false},
+		{"one short of the total", 21, 10, 10, 10, true},
+		{"past the end", 25, 10, 30, 0, false},
+		{"empty list", 0, 10, 0, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			meta := NewPaginatedResponse(nil, tt.total, tt.limit, tt.offset).Meta
+			want := map[string]any{
+				"count":    tt.wantCount,
+				"total":    tt.total,
+				"limit":    tt.limit,
+				"offset":   tt.offset,
+				"has_more": tt.wantMore,
+			}
+			for key, value := range want {
+				if meta[key] != value {
+					t.Errorf("meta.%s: got %v, want %v", key, meta[key], value)
+				}
+			}
+		})
+	}
+}
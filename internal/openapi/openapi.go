@@ -0,0 +1,362 @@
+// Package openapi describes the HTTP API as an OpenAPI 3 document, served
+// at GET /openapi.json so front-end developers have a machine-readable
+// contract.
+//
+// The spec is built by hand from a route table rather than by reflection.
+// Every route registered in cmd/server must appear in that table; a test
+// parses main.go and fails if the two disagree, so the spec can't silently
+// fall behind the real routes.
+//
+// Request bodies for user creation and swipes are described field by
+// field. Other bodies are listed as JSON objects, with their fields given
+// in the summary. Every JSON response uses the APIResponse envelope.
+package openapi
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Version is the OpenAPI version the document follows.
+const Version = "3.0.3"
+
+// Document is the root of an OpenAPI document. Only the parts of the
+// specification this API uses are modeled.
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+}
+
+// Info holds the API's title and version.
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// PathItem maps a lowercase HTTP method, such as "get", to the operation
+// served for it on one path.
+type PathItem map[string]*Operation
+
+// Operation describes one method on one path.
+type Operation struct {
+	Summary     string                `json:"summary"`
+	Tags        []string              `json:"tags,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]Response   `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+}
+
+// Parameter is a path or query parameter.
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// RequestBody describes the JSON body an operation accepts.
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// Response describes one possible response status.
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType pairs a content type with the schema of its body.
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Schema is a JSON Schema, in the subset OpenAPI 3.0 supports. Ref, when
+// set, points at a schema in Components and the other fields are left empty.
+type Schema struct {
+	Ref         string             `json:"$ref,omitempty"`
+	Type        string             `json:"type,omitempty"`
+	Format      string             `json:"format,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Nullable    bool               `json:"nullable,omitempty"`
+	Enum        []string           `json:"enum,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+	Items       *Schema            `json:"items,omitempty"`
+}
+
+// Components holds the schemas and security schemes that operations refer
+// to by name.
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes"`
+}
+
+// SecurityScheme describes how a client authenticates.
+type SecurityScheme struct {
+	Type        string `json:"type"`
+	In          string `json:"in"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// route is one entry of the route table: a ServeMux pattern and what the
+// spec says about it. Path parameters are taken from the pattern itself.
+type route struct {
+	pattern  string // As registered with ServeMux, e.g. "GET /users/{id}"
+	tag      string
+	summary  string
+	query    []Parameter
+	body     *Schema // nil if the operation takes no body
+	statuses []int
+	admin    bool // Requires the X-Admin-Token header
+}
+
+// Schema shorthands used by the route table.
+var (
+	uuidSchema    = &Schema{Type: "string", Format: "uuid"}
+	stringSchema  = &Schema{Type: "string"}
+	intSchema     = &Schema{Type: "integer"}
+	boolSchema    = &Schema{Type: "boolean"}
+	objectSchema  = &Schema{Type: "object"}
+	pagingParams  = []Parameter{queryParam("limit", "Page size", intSchema), queryParam("offset", "Entries to skip", intSchema)}
+	userIDParam   = requiredQueryParam("user_id", "The requesting user", uuidSchema)
+	envelopeRef   = ref("APIResponse")
+	adminSecurity = []map[string][]string{{"adminToken": {}}}
+)
+
+// routes lists every route the server registers, in main.go's order.
+var routes = []route{
+	{pattern: "GET /", tag: "health", summary: "Health check with uptime and store sizes", statuses: []int{200, 503}},
+	{pattern: "GET /openapi.json", tag: "health", summary: "This OpenAPI document (not wrapped in the response envelope)", statuses: []int{200}},
+	{pattern: "GET /stats", tag: "health", summary: "Totals of users, swipes, and matches", statuses: []int{200}},
+	{pattern: "GET /metrics", tag: "health", summary: "Request counters and latency histograms per route", statuses: []int{200}},
+
+	{pattern: "POST /users/", tag: "users", summary: "Create a user", body: ref("CreateUserRequest"), statuses: []int{201, 422}},
+	{pattern: "GET /users", tag: "users", summary: "List users by name", query: pagingParams, statuses: []int{200, 422}},
+	{pattern: "POST /users/batch", tag: "users", summary: `Fetch up to 100 users by {"ids": [...]}`, body: objectSchema, statuses: []int{200, 422}},
+	{pattern: "GET /users/{id}", tag: "users", summary: "Get a user; send the ETag back in If-None-Match to get 304 while unchanged", statuses: []int{200, 304, 404}},
+	{pattern: "PUT /users/{id}", tag: "users", summary: "Update profile details", body: objectSchema, statuses: []int{200, 404, 409, 422}},
+	{pattern: "DELETE /users/{id}", tag: "users", summary: "Delete a user with their swipes and matches", statuses: []int{204, 404}},
+	{pattern: "GET /users/{id}/export", tag: "users", summary: "Export a user's profile, swipes, likes received, and matches", statuses: []int{200, 404}},
+	{pattern: "GET /users/{id}/throughput", tag: "users", summary: "Swipes per day for the last week and match rate", statuses: []int{200, 404}},
+	{pattern: "PUT /users/{id}/preferences", tag: "users", summary: "Update notification preferences and quiet hours", body: objectSchema, statuses: []int{200, 404, 422}},
+	{pattern: "GET /users/{id}/zone-mates", tag: "users", summary: "Everyone else in the user's zone", query: pagingParams, statuses: []int{200, 404, 422}},
+	{pattern: "POST /boost", tag: "users", summary: `Show {"user_id", "minutes"} first in feeds for a while`, body: objectSchema, statuses: []int{200, 404, 422}},
+
+	{pattern: "GET /feed", tag: "feed", summary: "Get a discovery feed", query: append([]Parameter{
+		userIDParam,
+		queryParam("cursor", "Switch to cursor paging; pass meta.next_cursor for the next page", stringSchema),
+		queryParam("mode", "network for the second-degree feed", &Schema{Type: "string", Enum: []string{"network"}}),
+		queryParam("sort", "Feed order", &Schema{Type: "string", Enum: []string{"interests", "newest", "age_asc", "age_desc", "distance", "recent_activity", "least_liked"}}),
+		queryParam("freshness_weight", "From 0 to 1, how much to favor recently joined users", &Schema{Type: "number"}),
+		queryParam("reasons", "Annotate entries with why they are shown", boolSchema),
+		queryParam("exclude", "Comma-separated user IDs to omit", stringSchema),
+		queryParam("require_tags", "Comma-separated tags every candidate must have", stringSchema),
+		queryParam("include_self", "Return only the requester's own profile", boolSchema),
+		queryParam("online_only", "Keep only online candidates", boolSchema),
+	}, pagingParams...), statuses: []int{200, 404, 422}},
+	{pattern: "GET /feed/count", tag: "feed", summary: "Count feed candidates", query: []Parameter{
+		userIDParam,
+		queryParam("explain", "Add meta.stages, the users left after each filter", boolSchema),
+	}, statuses: []int{200, 404, 422}},
+
+	{pattern: "POST /swipe", tag: "swipes", summary: "Record a swipe", body: ref("CreateSwipeRequest"), statuses: []int{201, 400, 403, 404, 409, 422, 429}},
+	{pattern: "POST /swipe/undo", tag: "swipes", summary: `Undo the most recent swipe of {"user_id"}`, body: objectSchema, statuses: []int{200, 404, 422}},
+	{pattern: "GET /swipes", tag: "swipes", summary: "A user's swipes, newest first", query: append([]Parameter{
+		userIDParam,
+		queryParam("action", "Keep only this action", &Schema{Type: "string", Enum: []string{"LIKE", "PASS"}}),
+	}, pagingParams...), statuses: []int{200, 404, 422}},
+	{pattern: "POST /block", tag: "safety", summary: `Block a user with {"blocker_id", "blocked_id"}`, body: objectSchema, statuses: []int{200, 201, 404, 422}},
+	{pattern: "POST /reports", tag: "safety", summary: `Report a user with {"reporter_id", "reported_id", "reason"}`, body: objectSchema, statuses: []int{201, 404, 422}},
+
+	{pattern: "GET /matches", tag: "matches", summary: "A user's matches", query: []Parameter{
+		userIDParam,
+		queryParam("before", "Cursor from meta.next_cursor", stringSchema),
+		queryParam("limit", "Page size", intSchema),
+	}, statuses: []int{200, 404, 422}},
+	{pattern: "GET /matches/check", tag: "matches", summary: "The match between two users", query: []Parameter{
+		requiredQueryParam("user_a", "One user", uuidSchema),
+		requiredQueryParam("user_b", "The other user", uuidSchema),
+	}, statuses: []int{200, 404, 422}},
+	{pattern: "GET /matches/trail", tag: "matches", summary: "A match and the two likes behind it", query: []Parameter{
+		requiredQueryParam("user_id", "One user", uuidSchema),
+		requiredQueryParam("other_user_id", "The other user", uuidSchema),
+	}, statuses: []int{200, 404, 422}},
+	{pattern: "GET /matches/recent", tag: "matches", summary: "Newest matches platform-wide, anonymized", query: []Parameter{
+		queryParam("limit", "Page size", intSchema),
+	}, statuses: []int{200, 422}},
+	{pattern: "GET /matches/{id}", tag: "matches", summary: "Get a match", statuses: []int{200, 404}},
+	{pattern: "DELETE /matches/{id}", tag: "matches", summary: "Unmatch", statuses: []int{204, 404}},
+	{pattern: "POST /matches/{id}/seen", tag: "matches", summary: `Mark a match seen for {"user_id"}`, body: objectSchema, statuses: []int{204, 404, 422}},
+
+	{pattern: "GET /notifications/matches", tag: "notifications", summary: "Poll for new matches", query: []Parameter{
+		userIDParam,
+		queryParam("since", "RFC 3339 time to poll from", stringSchema),
+	}, statuses: []int{200, 404, 422}},
+	{pattern: "GET /zones/{zone_id}/active", tag: "zones", summary: "Users in a zone active recently", query: []Parameter{
+		queryParam("within", "Window, such as 24h", stringSchema),
+	}, statuses: []int{200, 422}},
+
+	{pattern: "POST /admin/simulate", tag: "admin", summary: `Generate {"users", "swipes"} of synthetic load`, body: objectSchema, statuses: []int{200, 403, 422}, admin: true},
+	{pattern: "POST /admin/read-only", tag: "admin", summary: `Toggle read-only mode with {"enabled"}`, body: objectSchema, statuses: []int{200, 403, 422}, admin: true},
+	{pattern: "GET /admin/analytics/swipes", tag: "admin", summary: "Swipe counts per time bucket", query: []Parameter{
+		queryParam("bucket", "Bucket width, such as 1h", stringSchema),
+	}, statuses: []int{200, 403, 422}, admin: true},
+	{pattern: "GET /admin/digest", tag: "admin", summary: "Per-user matches and likes since a time", query: []Parameter{
+		requiredQueryParam("since", "RFC 3339 time", stringSchema),
+	}, statuses: []int{200, 403, 422}, admin: true},
+	{pattern: "DELETE /swipe", tag: "admin", summary: "Remove one user's swipe on another, and any match it formed", query: []Parameter{
+		requiredQueryParam("swiper_id", "The user who swiped", uuidSchema),
+		requiredQueryParam("swiped_id", "The user who was swiped on", uuidSchema),
+	}, statuses: []int{204, 403, 404, 422}, admin: true},
+	{pattern: "GET /admin/validate", tag: "admin", summary: "Store integrity problems", statuses: []int{200, 403}, admin: true},
+	{pattern: "GET /admin/unseen-users", tag: "admin", summary: "Users nobody has swiped on yet", query: pagingParams, statuses: []int{200, 403, 422}, admin: true},
+	{pattern: "GET /reports", tag: "admin", summary: "All abuse reports", statuses: []int{200, 403}, admin: true},
+
+	{pattern: "POST /debug/seed", tag: "debug", summary: "Generate demo users across zones, and optionally random swipes; 404 unless ENABLE_DEBUG is on", query: []Parameter{
+		queryParam("users", "How many users to create (default 20)", intSchema),
+		queryParam("zones", "How many zones to spread them over (default 3)", intSchema),
+		queryParam("swipes", "How many random swipes to record (default 0)", intSchema),
+	}, statuses: []int{201, 404, 422}},
+}
+
+// Spec builds the OpenAPI document for the API.
+func Spec() *Document {
+	doc := &Document{
+		OpenAPI: Version,
+		Info: Info{
+			Title:       "Tinder-Claude API",
+			Description: "Location-based matching: users, discovery feeds, swipes, and matches.",
+			Version:     "1.0.0",
+		},
+		Paths: make(map[string]PathItem),
+		Components: Components{
+			Schemas: schemas(),
+			SecuritySchemes: map[string]SecurityScheme{
+				"adminToken": {Type: "apiKey", In: "header", Name: "X-Admin-Token", Description: "The server's ADMIN_TOKEN"},
+			},
+		},
+	}
+
+	for _, rt := range routes {
+		method, path, _ := strings.Cut(rt.pattern, " ")
+		op := &Operation{
+			Summary:    rt.summary,
+			Tags:       []string{rt.tag},
+			Parameters: append(pathParams(path), rt.query...),
+			Responses:  make(map[string]Response, len(rt.statuses)),
+		}
+		statuses := rt.statuses
+		if rt.body != nil {
+			op.RequestBody = &RequestBody{
+				Required: true,
+				Content:  map[string]MediaType{"application/json": {Schema: rt.body}},
+			}
+			// Every JSON body goes through the same size and Content-Type
+			// checks.
+			statuses = append(slices.Clip(statuses), http.StatusRequestEntityTooLarge, http.StatusUnsupportedMediaType)
+		}
+		for _, status := range statuses {
+			resp := Response{Description: http.StatusText(status)}
+			// 204 and 304 have no body; everything else is a JSON envelope.
+			if status != http.StatusNoContent && status != http.StatusNotModified {
+				resp.Content = map[string]MediaType{"application/json": {Schema: envelopeRef}}
+			}
+			op.Responses[strconv.Itoa(status)] = resp
+		}
+		if rt.admin {
+			op.Security = adminSecurity
+		}
+
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = make(PathItem)
+		}
+		doc.Paths[path][strings.ToLower(method)] = op
+	}
+	return doc
+}
+
+// pathParams returns a required string parameter for every {name} segment
+// of a ServeMux path.
+func pathParams(path string) []Parameter {
+	var params []Parameter
+	for _, segment := range strings.Split(path, "/") {
+		if name, ok := strings.CutPrefix(segment, "{"); ok {
+			name = strings.TrimSuffix(name, "}")
+			params = append(params, Parameter{Name: name, In: "path", Required: true, Schema: stringSchema})
+		}
+	}
+	return params
+}
+
+// queryParam returns an optional query parameter.
+func queryParam(name, description string, schema *Schema) Parameter {
+	return Parameter{Name: name, In: "query", Description: description, Schema: schema}
+}
+
+// requiredQueryParam returns a required query parameter.
+func requiredQueryParam(name, description string, schema *Schema) Parameter {
+	return Parameter{Name: name, In: "query", Description: description, Required: true, Schema: schema}
+}
+
+// ref returns a schema that points at the named component schema.
+func ref(name string) *Schema {
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+// schemas returns the component schemas. Field names follow the JSON tags
+// of the matching types in the models package.
+func schemas() map[string]*Schema {
+	return map[string]*Schema{
+		"APIResponse": {
+			Type:        "object",
+			Description: "The envelope around every JSON response. Successful responses fill data and meta; errors fill errors.",
+			Properties: map[string]*Schema{
+				"data":   {Description: "The response payload; null on errors", Nullable: true},
+				"meta":   {Type: "object", Description: "Extra details such as paging counts"},
+				"errors": {Type: "array", Items: ref("APIError")},
+			},
+			Required: []string{"meta", "errors"},
+		},
+		"APIError": {
+			Type:       "object",
+			Properties: map[string]*Schema{"message": stringSchema},
+			Required:   []string{"message"},
+		},
+		"CreateUserRequest": {
+			Type: "object",
+			Properties: map[string]*Schema{
+				"name":            {Type: "string"},
+				"age":             {Type: "integer", Description: "18 to 120"},
+				"gender":          {Type: "string", Description: "male, female, nonbinary, or other, in any case"},
+				"zone_id":         {Type: "string"},
+				"bio":             {Type: "string", Description: "At most 500 characters"},
+				"interested_in":   {Type: "string", Description: "A gender, or everyone (the default)"},
+				"min_age":         {Type: "integer"},
+				"max_age":         {Type: "integer"},
+				"tags":            {Type: "array", Items: stringSchema, Description: "Up to 20 tags of at most 30 characters"},
+				"latitude":        {Type: "number", Description: "Set together with longitude"},
+				"longitude":       {Type: "number", Description: "Set together with latitude"},
+				"max_distance_km": {Type: "number"},
+			},
+			Required: []string{"name", "age", "gender", "zone_id"},
+		},
+		"CreateSwipeRequest": {
+			Type: "object",
+			Properties: map[string]*Schema{
+				"swiper_id": uuidSchema,
+				"swiped_id": uuidSchema,
+				"action":    {Type: "string", Enum: []string{"LIKE", "PASS"}},
+			},
+			Required: []string{"swiper_id", "swiped_id", "action"},
+		},
+	}
+}
@@ -0,0 +1,150 @@
+// Package openapi contains tests that keep the OpenAPI document in step with
+// the routes the server actually registers.
+package openapi
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// registeredRoutes parses cmd/server/main.go and returns the pattern of
+// every mux.HandleFunc and mux.Handle call, such as "GET /users/{id}".
+// Reading the source means a route added there without a spec entry fails
+// the test below.
+func registeredRoutes(t *testing.T) []string {
+	t.Helper()
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "../../cmd/server/main.go", nil, 0)
+	if err != nil {
+		t.Fatalf("parsing main.go: %v", err)
+	}
+
+	var patterns []string
+	ast.Inspect(file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || len(call.Args) == 0 {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || (sel.Sel.Name != "HandleFunc" && sel.Sel.Name != "Handle") {
+			return true
+		}
+		if recv, ok := sel.X.(*ast.Ident); !ok || recv.Name != "mux" {
+			return true
+		}
+		lit, ok := call.Args[0].(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			t.Errorf("%s: route pattern must be a string literal", fset.Position(call.Pos()))
+			return true
+		}
+		pattern, err := strconv.Unquote(lit.Value)
+		if err != nil {
+			t.Fatalf("unquoting %s: %v", lit.Value, err)
+		}
+		patterns = append(patterns, pattern)
+		return true
+	})
+
+	if len(patterns) == 0 {
+		t.Fatal("found no routes in main.go")
+	}
+	return patterns
+}
+
+func TestSpec_MatchesRegisteredRoutes(t *testing.T) {
+	// Round-trip through JSON so the test checks what clients receive.
+	raw, err := json.Marshal(Spec())
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var doc struct {
+		OpenAPI string                                `json:"openapi"`
+		Paths   map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if !strings.HasPrefix(doc.OpenAPI, "3.") {
+		t.Errorf("openapi: got %q, want 3.x", doc.OpenAPI)
+	}
+
+	registered := registeredRoutes(t)
+	for _, pattern := range registered {
+		method, path, _ := strings.Cut(pattern, " ")
+		if _, ok := doc.Paths[path][strings.ToLower(method)]; !ok {
+			t.Errorf("%s is registered but missing from the spec", pattern)
+		}
+	}
+
+	// The other direction: the spec must not describe routes that no
+	// longer exist.
+	for path, item := range doc.Paths {
+		for method := range item {
+			pattern := strings.ToUpper(method) + " " + path
+			if !slices.Contains(registered, pattern) {
+				t.Errorf("%s is in the spec but not registered", pattern)
+			}
+		}
+	}
+}
+
+func TestSpec_ReferencesResolve(t *testing.T) {
+	doc := Spec()
+
+	check := func(where string, s *Schema) {
+		if s == nil || s.Ref == "" {
+			return
+		}
+		name := strings.TrimPrefix(s.Ref, "#/components/schemas/")
+		if _, ok := doc.Components.Schemas[name]; !ok {
+			t.Errorf("%s refers to unknown schema %q", where, s.Ref)
+		}
+	}
+	for path, item := range doc.Paths {
+		for method, op := range item {
+			where := method + " " + path
+			if op.RequestBody != nil {
+				for _, media := range op.RequestBody.Content {
+					check(where, media.Schema)
+				}
+			}
+			for _, resp := range op.Responses {
+				for _, media := range resp.Content {
+					check(where, media.Schema)
+				}
+			}
+		}
+	}
+}
+
+func TestPathParams(t *testing.T) {
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{"/users", nil},
+		{"/users/{id}", []string{"id"}},
+		{"/zones/{zone_id}/active", []string{"zone_id"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			var got []string
+			for _, p := range pathParams(tt.path) {
+				if p.In != "path" || !p.Required {
+					t.Errorf("%s: expected a required path parameter", p.Name)
+				}
+				got = append(got, p.Name)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
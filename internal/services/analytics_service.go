@@ -0,0 +1,146 @@
+// This file implements the AnalyticsService, which computes aggregate
+// engagement figures from the stored swipes, both for admin reporting and
+// for a user's own stats card.
+package services
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/dlfelps/tinder-go-claude/internal/models"
+	"github.com/dlfelps/tinder-go-claude/internal/store"
+	"github.com/google/uuid"
+)
+
+// AnalyticsService computes reporting aggregates over the store.
+type AnalyticsService struct {
+	store *store.InMemoryStore
+
+	// now is the clock used for "the last N days" windows. Tests replace it
+	// with SetClock.
+	now func() time.Time
+}
+
+// NewAnalyticsService creates a new AnalyticsService connected to the given store.
+func NewAnalyticsService(s *store.InMemoryStore) *AnalyticsService {
+	return &AnalyticsService{store: s, now: time.Now}
+}
+
+// SetClock replaces the service's time source, so tests can pin "now".
+func (as *AnalyticsService) SetClock(now func() time.Time) {
+	as.now = now
+}
+
+// SwipeBucket is the number of swipes recorded in one time window.
+type SwipeBucket struct {
+	Start  time.Time `json:"start"`
+	Count  int       `json:"count"`
+	Likes  int       `json:"likes"`
+	Passes int       `json:"passes"`
+}
+
+// SwipeRate groups all stored swipes into consecutive windows of the given
+// size and counts each window. Windows are aligned with time.Truncate (for
+// a 1h bucket, 10:00–11:00, 11:00–12:00, and so on, in UTC). Only windows
+// containing at least one swipe are returned, oldest first.
+func (as *AnalyticsService) SwipeRate(bucket time.Duration) ([]SwipeBucket, error) {
+	if bucket <= 0 {
+		return nil, &ValidationError{Message: "bucket must be a positive duration"}
+	}
+
+	// Accumulate counts keyed by window start, then flatten and sort.
+	byStart := make(map[time.Time]*SwipeBucket)
+	for _, swipe := range as.store.GetAllSwipes() {
+		start := swipe.Timestamp.UTC().Truncate(bucket)
+		b, ok := byStart[start]
+		if !ok {
+			b = &SwipeBucket{Start: start}
+			byStart[start] = b
+		}
+		b.Count++
+		switch swipe.Action {
+		case models.SwipeActionLike:
+			b.Likes++
+		case models.SwipeActionPass:
+			b.Passes++
+		}
+	}
+
+	buckets := make([]SwipeBucket, 0, len(byStart))
+	for _, b := range byStart {
+		buckets = append(buckets, *b)
+	}
+	sort.Slice(buckets, func(i, j int) bool {
+		return buckets[i].Start.Before(buckets[j].Start)
+	})
+	return buckets, nil
+}
+
+// ThroughputDays is how many days of history UserThroughput reports.
+const ThroughputDays = 7
+
+// UserThroughput summarizes one user's swiping for a personal stats card.
+type UserThroughput struct {
+	UserID uuid.UUID `json:"user_id"`
+
+	// Daily has exactly ThroughputDays entries, one per UTC day, oldest
+	// first and ending with today. Days without swipes have zero counts.
+	Daily []SwipeBucket `json:"daily"`
+
+	// The remaining fields are all-time totals.
+	TotalSwipes int `json:"total_swipes"`
+	LikesSent   int `json:"likes_sent"`
+	Matches     int `json:"matches"`
+
+	// MatchRate is Matches divided by LikesSent: the share of the user's
+	// LIKEs that turned into a match. It is 0 if they have never liked
+	// anyone.
+	MatchRate float64 `json:"match_rate"`
+}
+
+// UserThroughput reports the user's swipes per day over the last
+// ThroughputDays days and their all-time match rate. It returns a
+// NotFoundError if the user doesn't exist.
+func (as *AnalyticsService) UserThroughput(userID uuid.UUID) (*UserThroughput, error) {
+	if _, exists := as.store.GetUser(userID); !exists {
+		return nil, &NotFoundError{Message: fmt.Sprintf("user %s not found", userID)}
+	}
+
+	// Lay out every day up front so quiet days still appear, as zeros.
+	const day = 24 * time.Hour
+	today := as.now().UTC().Truncate(day)
+	first := today.Add(-(ThroughputDays - 1) * day)
+	daily := make([]SwipeBucket, ThroughputDays)
+	for i := range daily {
+		daily[i].Start = first.Add(time.Duration(i) * day)
+	}
+
+	result := &UserThroughput{UserID: userID, Daily: daily}
+	for _, swipe := range as.store.GetSwipesByUser(userID) {
+		result.TotalSwipes++
+		if swipe.Action == models.SwipeActionLike {
+			result.LikesSent++
+		}
+
+		// Swipes before the window, or stamped after "now", are counted in
+		// the totals above but not in any day.
+		i := int(swipe.Timestamp.UTC().Truncate(day).Sub(first) / day)
+		if swipe.Timestamp.Before(first) || i >= ThroughputDays {
+			continue
+		}
+		daily[i].Count++
+		switch swipe.Action {
+		case models.SwipeActionLike:
+			daily[i].Likes++
+		case models.SwipeActionPass:
+			daily[i].Passes++
+		}
+	}
+
+	result.Matches = len(as.store.GetMatchesForUser(userID))
+	if result.LikesSent > 0 {
+		result.MatchRate = float64(result.Matches) / float64(result.LikesSent)
+	}
+	return result, nil
+}
@@ -0,0 +1,195 @@
+// This file contains unit tests for the AnalyticsService: store-wide swipe
+// bucketing and per-user throughput.
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dlfelps/tinder-go-claude/internal/models"
+	"github.com/dlfelps/tinder-go-claude/internal/store"
+	"github.com/google/uuid"
+)
+
+// setupAnalyticsTest resets the store and creates an AnalyticsService.
+func setupAnalyticsTest(t *testing.T) (*AnalyticsService, *store.InMemoryStore) {
+	t.Helper()
+	s := store.GetStore()
+	s.Reset()
+	return NewAnalyticsService(s), s
+}
+
+// addSwipeAt records a swipe with a fixed timestamp, bypassing SwipeService
+// so the test controls exactly when each swipe happened.
+func addSwipeAt(s *store.InMemoryStore, action models.SwipeAction, at time.Time) {
+	s.AddSwipe(models.Swipe{
+		SwiperID:  uuid.New(),
+		SwipedID:  uuid.New(),
+		Action:    action,
+		Timestamp: at,
+	})
+}
+
+func TestSwipeRate_BucketsByWindow(t *testing.T) {
+	as, s := setupAnalyticsTest(t)
+
+	base := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	addSwipeAt(s, models.SwipeActionLike, base.Add(5*time.Minute))  // 10:00 bucket
+	addSwipeAt(s, models.SwipeActionPass, base.Add(59*time.Minute)) // 10:00 bucket
+	addSwipeAt(s, models.SwipeActionLike, base.Add(60*time.Minute)) // 11:00 bucket
+	addSwipeAt(s, models.SwipeActionLike, base.Add(3*time.Hour))    // 13:00 bucket (12:00 empty)
+
+	tests := []struct {
+		name   string
+		bucket time.Duration
+		want   []int // counts per returned bucket, oldest first
+	}{
+		{"hourly", time.Hour, []int{2, 1, 1}},
+		{"two hours", 2 * time.Hour, []int{3, 1}},
+		{"daily", 24 * time.Hour, []int{4}},
+		{"half hour", 30 * time.Minute, []int{1, 1, 1, 1}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			buckets, err := as.SwipeRate(tc.bucket)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(buckets) != len(tc.want) {
+				t.Fatalf("expected %d buckets, got %d", len(tc.want), len(buckets))
+			}
+			for i, want := range tc.want {
+				if buckets[i].Count != want {
+					t.Errorf("bucket %d: got count %d, want %d", i, buckets[i].Count, want)
+				}
+				if i > 0 && !buckets[i-1].Start.Before(buckets[i].Start) {
+					t.Errorf("buckets not in chronological order at %d", i)
+				}
+			}
+		})
+	}
+}
+
+func TestSwipeRate_SplitsLikesAndPasses(t *testing.T) {
+	as, s := setupAnalyticsTest(t)
+
+	base := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	addSwipeAt(s, models.SwipeActionLike, base)
+	addSwipeAt(s, models.SwipeActionLike, base.Add(time.Minute))
+	addSwipeAt(s, models.SwipeActionPass, base.Add(2*time.Minute))
+
+	buckets, err := as.SwipeRate(time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(buckets) != 1 {
+		t.Fatalf("expected 1 bucket, got %d", len(buckets))
+	}
+	b := buckets[0]
+	if !b.Start.Equal(base) {
+		t.Errorf("start: got %v, want %v", b.Start, base)
+	}
+	if b.Likes != 2 || b.Passes != 1 || b.Count != 3 {
+		t.Errorf("got likes=%d passes=%d count=%d, want 2/1/3", b.Likes, b.Passes, b.Count)
+	}
+}
+
+func TestSwipeRate_EmptyStore(t *testing.T) {
+	as, _ := setupAnalyticsTest(t)
+
+	buckets, err := as.SwipeRate(time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if buckets == nil || len(buckets) != 0 {
+		t.Errorf("expected empty non-nil slice, got %v", buckets)
+	}
+}
+
+func TestSwipeRate_RejectsNonPositiveBucket(t *testing.T) {
+	as, _ := setupAnalyticsTest(t)
+
+	for _, bucket := range []time.Duration{0, -time.Hour} {
+		_, err := as.SwipeRate(bucket)
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Errorf("bucket %v: expected ValidationError, got %v", bucket, err)
+		}
+	}
+}
+
+func TestUserThroughput_DailyBuckets(t *testing.T) {
+	as, s := setupAnalyticsTest(t)
+
+	now := time.Date(2024, 3, 10, 15, 0, 0, 0, time.UTC)
+	as.SetClock(func() time.Time { return now })
+
+	alice := makeTestUser(s, "Alice", "zone-a")
+	bob := makeTestUser(s, "Bob", "zone-a")
+	swipe := func(action models.SwipeAction, at time.Time) {
+		s.AddSwipe(models.Swipe{SwiperID: alice.ID, SwipedID: bob.ID, Action: action, Timestamp: at})
+	}
+	today := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+	swipe(models.SwipeActionLike, today.Add(9*time.Hour))                 // today
+	swipe(models.SwipeActionPass, today.Add(-time.Minute))                // yesterday, 23:59
+	swipe(models.SwipeActionLike, today.Add(-24*time.Hour))               // yesterday, 00:00
+	swipe(models.SwipeActionPass, today.Add(-6*24*time.Hour))             // first day of the window
+	swipe(models.SwipeActionLike, today.Add(-6*24*time.Hour-time.Second)) // just before the window
+	// Someone else's swipe must not count.
+	s.AddSwipe(models.Swipe{SwiperID: bob.ID, SwipedID: alice.ID, Action: models.SwipeActionLike, Timestamp: today})
+	s.AddMatch(models.Match{User1ID: alice.ID, User2ID: bob.ID, Timestamp: today})
+
+	got, err := as.UserThroughput(alice.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(got.Daily) != ThroughputDays {
+		t.Fatalf("expected %d days, got %d", ThroughputDays, len(got.Daily))
+	}
+	wantCounts := []int{1, 0, 0, 0, 0, 2, 1}
+	for i, want := range wantCounts {
+		day := got.Daily[i]
+		if wantStart := today.Add(time.Duration(i-6) * 24 * time.Hour); !day.Start.Equal(wantStart) {
+			t.Errorf("day %d: expected start %s, got %s", i, wantStart, day.Start)
+		}
+		if day.Count != want {
+			t.Errorf("day %d: expected %d swipes, got %d", i, want, day.Count)
+		}
+	}
+	if y := got.Daily[5]; y.Likes != 1 || y.Passes != 1 {
+		t.Errorf("yesterday: expected 1 like and 1 pass, got %+v", y)
+	}
+
+	if got.TotalSwipes != 5 || got.LikesSent != 3 || got.Matches != 1 {
+		t.Errorf("unexpected totals: %+v", got)
+	}
+	if got.MatchRate != 1.0/3 {
+		t.Errorf("expected match rate 1/3, got %v", got.MatchRate)
+	}
+}
+
+func TestUserThroughput_NoSwipes(t *testing.T) {
+	as, s := setupAnalyticsTest(t)
+	alice := makeTestUser(s, "Alice", "zone-a")
+
+	got, err := as.UserThroughput(alice.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got.Daily) != ThroughputDays || got.MatchRate != 0 {
+		t.Errorf("expected %d empty days and a zero match rate, got %+v", ThroughputDays, got)
+	}
+}
+
+func TestUserThroughput_UnknownUser(t *testing.T) {
+	as, _ := setupAnalyticsTest(t)
+
+	_, err := as.UserThroughput(uuid.New())
+	var notFoundErr *NotFoundError
+	if !errors.As(err, &notFoundErr) {
+		t.Errorf("expected NotFoundError, got %v", err)
+	}
+}
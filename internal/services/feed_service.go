@@ -3,15 +3,30 @@
 // enforcing business rules and performing complex operations.
 //
 // This file implements the FeedService, which generates a personalized
-// discovery feed for a user by applying a three-tier filtering pipeline:
+// discovery feed for a user by applying a four-tier filtering pipeline:
 //
-//  1. Zone Filter — only show users in the same geographic zone
-//  2. Self-Exclusion — don't show the user their own profile
-//  3. Seen-State Filter — don't show users already swiped on
+//  1. Zone Filter — only show users in the same geographic zone or a
+//     neighboring one (see SetZoneAdjacency), or, when both users have
+//     coordinates and the requester has a distance limit, users within
+//     that distance
+//  2. Preferences — only show users matching the requester's gender and
+//     age preferences
+//  3. Self-Exclusion — don't show the user their own profile
+//  4. Seen-State Filter — don't show users already swiped on (a PASS can be
+//     set to expire; see SetPassExpiry)
+//
+// Users blocked in either direction are always left out as well.
 package services
 
 import (
+	"bytes"
+	"encoding/base64"
 	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/dlfelps/tinder-go-claude/internal/models"
 	"github.com/dlfelps/tinder-go-claude/internal/store"
@@ -25,6 +40,35 @@ import (
 // you can swap in a mock store during testing.
 type FeedService struct {
 	store *store.InMemoryStore
+
+	// zoneAliases maps lowercased zone IDs to their canonical zone. When it
+	// is nil (the default), zones are compared exactly as stored.
+	zoneAliases map[string]string
+
+	// zoneAdjacency maps zone IDs to their neighbors, as configured. Nil
+	// (the default) means a feed covers only the requester's own zone.
+	zoneAdjacency map[string][]string
+
+	// exposureDemotion is the number of top-of-feed appearances after which
+	// a candidate is demoted by one tier. Zero (the default) disables
+	// fairness demotion.
+	exposureDemotion int
+
+	// onlineWindow is how recently a user must have been active to count
+	// as online; see SetOnlineWindow.
+	onlineWindow time.Duration
+
+	// passExpiry is how long a PASS hides a candidate; see SetPassExpiry.
+	// Zero (the default) means forever.
+	passExpiry time.Duration
+
+	// maxCandidates caps how many passing candidates GetFeed collects; see
+	// SetMaxFeedCandidates. Zero (the default) means no cap.
+	maxCandidates int
+
+	// now returns the current time. It defaults to time.Now but can be
+	// replaced (see SetClock) so tests can control who counts as online.
+	now func() time.Time
 }
 
 // NewFeedService creates a new FeedService connected to the given store.
@@ -32,67 +76,1037 @@ type FeedService struct {
 // struct instances. Unlike Python's __init__, Go doesn't have constructors
 // built into the language; we use plain functions by convention.
 func NewFeedService(s *store.InMemoryStore) *FeedService {
-	return &FeedService{store: s}
+	return &FeedService{
+		store:        s,
+		onlineWindow: DefaultOnlineWindow,
+		now:          time.Now,
+	}
+}
+
+// DefaultOnlineWindow is how recently a user must have been active to be
+// shown as online, unless SetOnlineWindow changes it.
+const DefaultOnlineWindow = 5 * time.Minute
+
+// SetOnlineWindow sets how recently a user must have been active (by
+// LastActiveAt) to count as online. Non-positive values restore the default.
+func (fs *FeedService) SetOnlineWindow(window time.Duration) {
+	if window <= 0 {
+		window = DefaultOnlineWindow
+	}
+	fs.onlineWindow = window
+}
+
+// SetPassExpiry sets how long a PASS keeps a candidate out of the feed.
+// Once a PASS is older than expiry, the candidate can appear again, so
+// people get a second look after a while; a LIKE hides its candidate for
+// good. Zero or less, the default, means a PASS never expires.
+func (fs *FeedService) SetPassExpiry(expiry time.Duration) {
+	if expiry < 0 {
+		expiry = 0
+	}
+	fs.passExpiry = expiry
+}
+
+// SetMaxFeedCandidates caps how many candidates GetFeed collects per
+// request: the filter pipeline stops scanning once limit candidates have
+// passed it, which bounds the work of sorting and ranking a feed in a large
+// zone. The trade-off is that the feed may leave out candidates who would
+// have passed. Which ones is still deterministic: when capped, candidates
+// are scanned boosted users first and then in user ID order, so the same
+// store always gives the same feed and offset pages don't overlap or skip
+// anyone. meta.total never exceeds the cap. Zero or less, the default,
+// means no cap.
+//
+// Cursor paging (GetFeedPage) and CountFeed are not capped: cursor pages
+// must be able to reach every candidate, and a count should be exact.
+func (fs *FeedService) SetMaxFeedCandidates(limit int) {
+	if limit < 0 {
+		limit = 0
+	}
+	fs.maxCandidates = limit
+}
+
+// SetClock replaces the service's time source, so tests can pin "now".
+func (fs *FeedService) SetClock(now func() time.Time) {
+	fs.now = now
+}
+
+// SetZoneAliases configures zone aliasing. Each key is treated as another
+// name for its value, so {"nyc": "new-york"} makes users in "nyc" and
+// "new-york" share a feed. Once aliases are configured, zone comparison is
+// also case-insensitive.
+//
+// Passing nil or an empty map restores exact zone matching. This should be
+// called during startup, before the service handles requests.
+func (fs *FeedService) SetZoneAliases(aliases map[string]string) {
+	if len(aliases) == 0 {
+		fs.zoneAliases = nil
+		return
+	}
+
+	// Normalize keys and values up front so lookups are a single map access.
+	normalized := make(map[string]string, len(aliases))
+	for alias, canonical := range aliases {
+		normalized[normalizeZone(alias)] = normalizeZone(canonical)
+	}
+	fs.zoneAliases = normalized
+}
+
+// canonicalZone returns the zone ID used for feed comparisons.
+func (fs *FeedService) canonicalZone(zoneID string) string {
+	if fs.zoneAliases == nil {
+		return zoneID
+	}
+	zone := normalizeZone(zoneID)
+	if canonical, ok := fs.zoneAliases[zone]; ok {
+		return canonical
+	}
+	return zone
+}
+
+// SetZoneAdjacency configures neighboring zones, so that users in small
+// zones aren't cut off: a feed also includes candidates from every zone
+// next to the requester's. Each key lists its neighbors, and adjacency works
+// both ways, so {"zone-a": ["zone-b"]} lets users in either zone see each
+// other. It is not transitive: a neighbor's neighbors are not included.
+// Zone names are resolved through any aliases, like users' zones.
+//
+// Passing nil or an empty map restores single-zone feeds. This should be
+// called during startup, before the service handles requests.
+func (fs *FeedService) SetZoneAdjacency(adjacency map[string][]string) {
+	if len(adjacency) == 0 {
+		fs.zoneAdjacency = nil
+		return
+	}
+	fs.zoneAdjacency = adjacency
+}
+
+// neighborZones returns the set of canonical zones adjacent to zone, which
+// must already be canonical. The adjacency is resolved per call rather than
+// once in SetZoneAdjacency so that it always agrees with the aliases,
+// whichever setter ran first; the configuration is small.
+func (fs *FeedService) neighborZones(zone string) map[string]struct{} {
+	if fs.zoneAdjacency == nil {
+		return nil
+	}
+	neighbors := make(map[string]struct{})
+	for from, tos := range fs.zoneAdjacency {
+		from = fs.canonicalZone(from)
+		for _, to := range tos {
+			to = fs.canonicalZone(to)
+			if from == zone && to != zone {
+				neighbors[to] = struct{}{}
+			}
+			if to == zone && from != zone {
+				neighbors[from] = struct{}{}
+			}
+		}
+	}
+	return neighbors
+}
+
+// normalizeZone trims surrounding whitespace and lowercases a zone ID.
+func normalizeZone(zoneID string) string {
+	return strings.ToLower(strings.TrimSpace(zoneID))
+}
+
+// SetExposureDemotion enables feed fairness. Every time a candidate has
+// topped `threshold` more feeds, they drop one tier below less-exposed
+// candidates, so a single popular profile can't hold the top slot forever.
+// A threshold of zero or less disables demotion, and with it the counting
+// of exposures, which would otherwise take the store's write lock on every
+// feed request. The counts live only in memory: persist.go doesn't save
+// them, so they start from zero after a restart.
+func (fs *FeedService) SetExposureDemotion(threshold int) {
+	if threshold < 0 {
+		threshold = 0
+	}
+	fs.exposureDemotion = threshold
+}
+
+// Human-readable reasons attached to feed entries when
+// FeedOptions.IncludeReasons is set.
+const (
+	ReasonSameZone     = "same zone"
+	ReasonNeighborZone = "neighboring zone"
+	ReasonNearby       = "nearby"
+	ReasonLikedYou     = "liked you"
+	ReasonAgeRange     = "within your age range"
+)
+
+// FeedOptions holds per-request feed settings. Using an options struct
+// (instead of a growing list of parameters) keeps call sites readable and
+// lets new options be added without touching every caller; the zero value
+// means "default behavior".
+type FeedOptions struct {
+	// IncludeReasons annotates each entry with why it appears in the feed.
+	IncludeReasons bool
+
+	// Exclude lists user IDs to leave out of this response only, such as
+	// cards the client has already rendered. No swipes are recorded.
+	Exclude []uuid.UUID
+
+	// IncludeSelf returns only the requester's own profile, for a "how
+	// others see me" preview. Every other candidate is omitted.
+	IncludeSelf bool
+
+	// OnlineOnly keeps only candidates who are currently online.
+	OnlineOnly bool
+
+	// RequireTags keeps only candidates who have every one of these tags.
+	// The tags must already be normalized (see models.NormalizeTag).
+	RequireTags []string
+
+	// Sort picks the feed order. The zero value, FeedSortDefault, orders
+	// by user ID.
+	Sort FeedSort
+
+	// FreshnessWeight, between 0 and 1, boosts recently joined users by
+	// blending each candidate's rank in the Sort order with their rank by
+	// JoinedAt (see blendFreshness). Zero leaves the order alone; 1 orders
+	// purely newest first.
+	FreshnessWeight float64
+
+	// Offset and Limit select a window of the ordered feed: skip the first
+	// Offset candidates, then return at most Limit. A zero Limit means no
+	// limit. Callers are expected to pass non-negative values.
+	Offset int
+	Limit  int
+}
+
+// FeedSort names an ordering for GetFeed.
+type FeedSort string
+
+// The feed orders. Every order breaks ties by user ID, so the same store
+// contents always produce the same feed.
+const (
+	// FeedSortDefault orders candidates by user ID. The IDs are random, so
+	// this favors nobody, but unlike map order it is reproducible.
+	FeedSortDefault FeedSort = ""
+
+	// FeedSortInterests puts candidates who share more tags with the
+	// requester first (see tagOverlap).
+	FeedSortInterests FeedSort = "interests"
+
+	// FeedSortNewest puts the most recently joined candidates first.
+	FeedSortNewest FeedSort = "newest"
+
+	// FeedSortAgeAsc and FeedSortAgeDesc order candidates by age, youngest
+	// or oldest first.
+	FeedSortAgeAsc  FeedSort = "age_asc"
+	FeedSortAgeDesc FeedSort = "age_desc"
+
+	// FeedSortDistance puts the nearest candidates first. Candidates
+	// without a distance (because either side has no coordinates) come
+	// after all those with one.
+	FeedSortDistance FeedSort = "distance"
+
+	// FeedSortRecentActivity puts the most recently active candidates
+	// (by LastActiveAt) first, so stale profiles sink.
+	FeedSortRecentActivity FeedSort = "recent_activity"
+
+	// FeedSortLeastLiked puts the candidates who have received the fewest
+	// LIKEs first, so popular profiles stop crowding the top of every feed
+	// and newer or less-seen ones get a chance (see sortByLikesReceived).
+	FeedSortLeastLiked FeedSort = "least_liked"
+)
+
+// feedSorts lists every valid FeedSort except the default.
+var feedSorts = []FeedSort{
+	FeedSortInterests, FeedSortNewest, FeedSortAgeAsc, FeedSortAgeDesc, FeedSortDistance,
+	FeedSortRecentActivity, FeedSortLeastLiked,
+}
+
+// IsValid reports whether s is a sort order GetFeed understands.
+func (s FeedSort) IsValid() bool {
+	return s == FeedSortDefault || slices.Contains(feedSorts, s)
+}
+
+// FeedSortNames returns the names accepted for FeedOptions.Sort, for error
+// messages.
+func FeedSortNames() []string {
+	names := make([]string, len(feedSorts))
+	for i, s := range feedSorts {
+		names[i] = string(s)
+	}
+	return names
 }
 
 // GetFeed generates a discovery feed for the given user by applying the
-// three-tier filtering pipeline. It returns a slice of User models that
-// the requesting user has not yet seen and who are in the same zone.
+// four-tier filtering pipeline. It returns the candidates the requesting
+// user has not yet seen, who are in the same zone, and whose gender the
+// requester is interested in and whose age is in the requester's range.
+// Candidates with an active boost come first.
+//
+// Only the window selected by opts.Offset and opts.Limit is returned. The
+// second return value is the total number of candidates before that
+// window was applied, so clients can tell how many pages there are.
 //
 // The function returns an error if the requesting user doesn't exist.
 // In Go, we return errors as values rather than throwing exceptions.
 // The caller is expected to check the error before using the result.
-func (fs *FeedService) GetFeed(userID uuid.UUID) ([]models.User, error) {
-	// Step 0: Verify the requesting user exists.
-	// The comma-ok idiom (value, ok := ...) is how Go handles lookups
-	// that might fail — no exceptions needed.
-	requestingUser, exists := fs.store.GetUser(userID)
-	if !exists {
-		return nil, fmt.Errorf("user %s not found", userID)
+func (fs *FeedService) GetFeed(userID uuid.UUID, opts FeedOptions) ([]models.FeedEntry, int, error) {
+	// Steps 0 and 1: Snapshot the store and apply the filter pipeline.
+	feed, err := fs.filterCandidates(userID, opts, fs.maxCandidates)
+	if err != nil {
+		return nil, 0, err
 	}
 
-	// Step 1: Get all users from the store.
-	allUsers := fs.store.GetAllUsers()
+	// A self-preview isn't a real feed, so it must not skew exposure counts.
+	if opts.IncludeSelf {
+		return window(feed, opts.Offset, opts.Limit), len(feed), nil
+	}
 
-	// Step 2: Build a set of already-swiped user IDs for O(1) lookup.
-	// Go doesn't have a built-in Set type, so we use a map with empty struct
-	// values. The empty struct (struct{}) takes zero bytes of memory, making
-	// it the most efficient "set element" in Go.
-	swipes := fs.store.GetSwipesByUser(userID)
-	seenSet := make(map[uuid.UUID]struct{}, len(swipes))
-	for _, swipe := range swipes {
-		seenSet[swipe.SwipedID] = struct{}{}
+	// Step 2: Order the feed as requested. Demotion below is stable, so it
+	// keeps this order within each exposure tier.
+	requester, _ := fs.store.GetUser(userID)
+	sortFeed(feed, opts.Sort, requester)
+	if opts.Sort == FeedSortLeastLiked {
+		fs.sortByLikesReceived(feed)
+	}
+	if opts.FreshnessWeight > 0 {
+		blendFreshness(feed, opts.FreshnessWeight)
 	}
 
-	// Step 3: Apply the three-tier filter pipeline.
-	// We iterate through all users once (O(N)) and apply each filter in order.
-	var feed []models.User
-	for _, candidate := range allUsers {
-		// Tier 1: Zone Filter — only include users in the same zone.
-		if candidate.ZoneID != requestingUser.ZoneID {
-			continue // Skip users in different zones.
-		}
+	// Step 3: Apply fairness demotion. Exposures are recorded below only
+	// while it is on, since nothing else reads them.
+	if fs.exposureDemotion > 0 {
+		fs.demoteOverexposed(feed)
+	}
+
+	// Step 3b: Boosted users go ahead of everyone else. This comes after
+	// demotion because a boost is a paid promise to be seen; the stable
+	// sort keeps the order above within the boosted and unboosted groups.
+	promoteBoosted(feed, fs.now())
+	// Record who is being shown at the top of this feed so future feeds can
+	// spread exposure. Only the first page shows the top of the feed; later
+	// pages are the same ordering viewed further down, so they record
+	// nothing.
+	if fs.exposureDemotion > 0 && len(feed) > 0 && opts.Offset == 0 {
+		fs.store.RecordExposure(feed[0].ID)
+	}
 
-		// Tier 2: Self-Exclusion — don't include the requesting user.
-		if candidate.ID == userID {
-			continue // Skip self.
+	// Step 4: Cut out the requested page.
+	return window(feed, opts.Offset, opts.Limit), len(feed), nil
+}
+
+// window returns feed[offset:offset+limit], clamped to the feed's bounds.
+// A zero limit means "everything from offset on". The result shares the
+// backing array with feed and is never nil.
+func window(feed []models.FeedEntry, offset, limit int) []models.FeedEntry {
+	start := min(offset, len(feed))
+	end := len(feed)
+	if limit > 0 {
+		end = min(start+limit, len(feed))
+	}
+	return feed[start:end]
+}
+
+// filterCandidates runs the filter pipeline shared by GetFeed and
+// GetFeedPage and returns every candidate that passes, in no particular
+// order, stopping early once it has maxResults of them (zero means no
+// limit). With a limit, the candidates are scanned in a fixed order (see
+// SetMaxFeedCandidates) so the same ones are kept every time. It never
+// returns a nil slice on success.
+func (fs *FeedService) filterCandidates(userID uuid.UUID, opts FeedOptions, maxResults int) ([]models.FeedEntry, error) {
+	// Fetching a feed counts as activity. Touch the requester first so the
+	// snapshot below already sees them as active.
+	fs.store.TouchUser(userID, fs.now().UTC())
+
+	// Step 0: Snapshot the store and set up the filter.
+	filter, candidates, err := fs.newFeedFilter(userID, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// A self-preview bypasses the pipeline entirely: the requester is the
+	// one profile that self-exclusion would always remove.
+	if opts.IncludeSelf {
+		return []models.FeedEntry{{User: filter.requester, Online: filter.isOnline(filter.requester)}}, nil
+	}
+
+	// Reasons need to know who has liked the requester. Build that set only
+	// when reasons were asked for, so the default path does no extra work.
+	var likedBy map[uuid.UUID]struct{}
+	if opts.IncludeReasons {
+		likes := fs.store.GetLikesReceived(userID)
+		likedBy = make(map[uuid.UUID]struct{}, len(likes))
+		for _, like := range likes {
+			likedBy[like.SwiperID] = struct{}{}
 		}
+	}
+
+	// The snapshot lists users in map order, which changes from call to
+	// call. That doesn't matter when every candidate is kept and sorted
+	// later, but a cap would keep a different random subset each time.
+	if maxResults > 0 {
+		now := fs.now()
+		sort.Slice(candidates, func(i, j int) bool {
+			bi, bj := candidates[i].BoostedAt(now), candidates[j].BoostedAt(now)
+			if bi != bj {
+				return bi
+			}
+			return bytes.Compare(candidates[i].ID[:], candidates[j].ID[:]) < 0
+		})
+	}
 
-		// Tier 3: Seen-State Filter — don't include already-swiped users.
-		// The underscore (_) discards the value; we only care if the key exists.
-		if _, alreadySeen := seenSet[candidate.ID]; alreadySeen {
-			continue // Skip users we've already swiped on.
+	// Step 1: Apply the four-tier filter pipeline.
+	// We iterate through all users once (O(N)) and apply each filter in order.
+	var feed []models.FeedEntry
+	for _, candidate := range candidates {
+		if filter.reject(candidate) != "" {
+			continue
 		}
 
-		// The candidate passed all three filters — add them to the feed.
-		feed = append(feed, candidate)
+		// The candidate passed every filter — add them to the feed.
+		entry := models.FeedEntry{
+			User:       candidate,
+			Online:     filter.isOnline(candidate),
+			DistanceKm: filter.distanceTo(candidate),
+		}
+		if opts.IncludeReasons {
+			entry.Reasons = feedReasons(filter.requester, candidate, likedBy, filter.locationReason(candidate))
+		}
+		feed = append(feed, entry)
+		if maxResults > 0 && len(feed) == maxResults {
+			break
+		}
 	}
 
 	// Return an empty slice instead of nil so JSON serialization produces
 	// "[]" instead of "null". This is a common Go idiom for API responses.
 	if feed == nil {
-		feed = []models.User{}
+		feed = []models.FeedEntry{}
 	}
 
 	return feed, nil
 }
+
+// FeedStage names one step of the filter pipeline, for CountFeed's
+// breakdown and for reporting which step rejected a candidate.
+type FeedStage string
+
+// The pipeline stages, in the order they are applied.
+const (
+	FeedStageZone        FeedStage = "zone"
+	FeedStageDistance    FeedStage = "distance"
+	FeedStagePreferences FeedStage = "preferences"
+	FeedStageSelf        FeedStage = "self"
+	FeedStageSeen        FeedStage = "seen"
+	FeedStageBlocked     FeedStage = "blocked"
+	FeedStageExclude     FeedStage = "exclude"
+	FeedStageOnline      FeedStage = "online"
+	FeedStageTags        FeedStage = "tags"
+)
+
+// feedStages lists every FeedStage in pipeline order.
+var feedStages = []FeedStage{
+	FeedStageZone, FeedStageDistance, FeedStagePreferences, FeedStageSelf, FeedStageSeen,
+	FeedStageBlocked, FeedStageExclude, FeedStageOnline, FeedStageTags,
+}
+
+// feedFilter holds everything the pipeline needs to judge one candidate,
+// computed once per request.
+type feedFilter struct {
+	fs            *FeedService
+	opts          FeedOptions
+	requester     models.User
+	requesterZone string
+
+	// neighborZones is the set of zones adjacent to requesterZone; see
+	// SetZoneAdjacency.
+	neighborZones map[string]struct{}
+
+	// seen is the set of users the requester has swiped on. It is a map
+	// with empty struct values: Go doesn't have a built-in Set type, and
+	// the empty struct (struct{}) takes zero bytes of memory, making it the
+	// most efficient "set element" in Go.
+	seen map[uuid.UUID]struct{}
+
+	// blocked is the set of users the requester has blocked or been
+	// blocked by.
+	blocked map[uuid.UUID]struct{}
+
+	// excluded holds the per-request exclusions, as a set for O(1) lookup.
+	excluded map[uuid.UUID]struct{}
+
+	// onlineCutoff is computed once so every candidate is judged against
+	// the same instant.
+	onlineCutoff time.Time
+}
+
+// newFeedFilter takes a consistent snapshot of the requesting user, all
+// candidates, and the set of users already swiped on, and builds the
+// filter for them.
+//
+// These are read under one store lock so a swipe that completes while the
+// feed is being built is either fully visible (the swiped user is in the
+// seen-set) or not at all. The comma-ok idiom tells us whether the
+// requesting user exists — no exceptions needed.
+func (fs *FeedService) newFeedFilter(userID uuid.UUID, opts FeedOptions) (*feedFilter, []models.User, error) {
+	// A zero cutoff keeps every PASS.
+	var passesSince time.Time
+	if fs.passExpiry > 0 {
+		passesSince = fs.now().Add(-fs.passExpiry)
+	}
+	snapshot, exists := fs.store.SnapshotForFeed(userID, passesSince)
+	if !exists {
+		return nil, nil, &NotFoundError{Message: fmt.Sprintf("user %s not found", userID)}
+	}
+
+	excluded := make(map[uuid.UUID]struct{}, len(opts.Exclude))
+	for _, id := range opts.Exclude {
+		excluded[id] = struct{}{}
+	}
+
+	requesterZone := fs.canonicalZone(snapshot.User.ZoneID)
+	return &feedFilter{
+		fs:            fs,
+		opts:          opts,
+		requester:     snapshot.User,
+		requesterZone: requesterZone,
+		neighborZones: fs.neighborZones(requesterZone),
+		seen:          snapshot.Seen,
+		blocked:       snapshot.Blocked,
+		excluded:      excluded,
+		onlineCutoff:  fs.now().Add(-fs.onlineWindow),
+	}, snapshot.Candidates, nil
+}
+
+// isOnline reports whether user was active within the online window. A
+// user active exactly at the cutoff counts.
+func (f *feedFilter) isOnline(user models.User) bool {
+	return !user.LastActiveAt.Before(f.onlineCutoff)
+}
+
+// distanceTo returns the distance in kilometers from the requester to
+// candidate, rounded to 0.1 km, or nil if either hasn't shared a location.
+// Rounding keeps the feed from revealing exact positions.
+func (f *feedFilter) distanceTo(candidate models.User) *float64 {
+	if !f.requester.HasLocation() || !candidate.HasLocation() {
+		return nil
+	}
+	km := haversineKm(*f.requester.Latitude, *f.requester.Longitude, *candidate.Latitude, *candidate.Longitude)
+	km = math.Round(km*10) / 10
+	return &km
+}
+
+// byDistance reports whether candidate is judged by distance rather than
+// by zone: both users must have a location and the requester a distance
+// limit.
+func (f *feedFilter) byDistance(candidate models.User) bool {
+	return f.requester.MaxDistanceKm > 0 && f.requester.HasLocation() && candidate.HasLocation()
+}
+
+// reject returns the first stage that filters candidate out, or "" if the
+// candidate belongs in the feed.
+func (f *feedFilter) reject(candidate models.User) FeedStage {
+	// Tier 1: Location — when both users have coordinates and the
+	// requester has set a distance limit, keep only candidates within it.
+	// Otherwise fall back to the zone: only include users in the same zone
+	// or a neighboring one (after resolving any configured aliases).
+	if f.byDistance(candidate) {
+		if *f.distanceTo(candidate) > f.requester.MaxDistanceKm {
+			return FeedStageDistance
+		}
+	} else if zone := f.fs.canonicalZone(candidate.ZoneID); zone != f.requesterZone {
+		if _, neighbor := f.neighborZones[zone]; !neighbor {
+			return FeedStageZone
+		}
+	}
+
+	// Tier 2: Preferences — only include users whose gender the
+	// requester is interested in ("everyone" lets all through) and
+	// whose age is within the requester's range, and who would in turn
+	// be interested in the requester. Showing someone who could never
+	// match back would only waste a swipe.
+	if !preferencesCompatible(f.requester, candidate) {
+		return FeedStagePreferences
+	}
+
+	// Tier 3: Self-Exclusion — don't include the requesting user.
+	if candidate.ID == f.requester.ID {
+		return FeedStageSelf
+	}
+
+	// Tier 4: Seen-State Filter — don't include already-swiped users.
+	// The underscore (_) discards the value; we only care if the key exists.
+	if _, alreadySeen := f.seen[candidate.ID]; alreadySeen {
+		return FeedStageSeen
+	}
+
+	// Blocks work both ways: neither user ever sees the other.
+	if _, isBlocked := f.blocked[candidate.ID]; isBlocked {
+		return FeedStageBlocked
+	}
+
+	// Extra filter: skip anyone the client asked to exclude this time.
+	if _, skip := f.excluded[candidate.ID]; skip {
+		return FeedStageExclude
+	}
+
+	// Extra filter: when asked, skip anyone who isn't online right now.
+	if f.opts.OnlineOnly && !f.isOnline(candidate) {
+		return FeedStageOnline
+	}
+
+	// Extra filter: skip anyone missing one of the required tags.
+	if !hasAllTags(candidate.Tags, f.opts.RequireTags) {
+		return FeedStageTags
+	}
+
+	return ""
+}
+
+// FeedCount is the result of CountFeed.
+type FeedCount struct {
+	// Total is how many candidates GetFeed would return in all, before
+	// paging and without any SetMaxFeedCandidates cap.
+	Total int `json:"total"`
+
+	// Stages lists, in pipeline order, how many users remain after each
+	// stage. The first entry's input is every user in the store.
+	Stages []FeedStageCount `json:"stages"`
+}
+
+// FeedStageCount is the number of users left after one pipeline stage.
+type FeedStageCount struct {
+	Stage     FeedStage `json:"stage"`
+	Remaining int       `json:"remaining"`
+}
+
+// CountFeed returns how many candidates GetFeed would return for the same
+// inputs, without building feed entries, computing reasons, sorting, or
+// recording exposure. Paging fields in opts are ignored. It returns an
+// error if the requesting user doesn't exist.
+func (fs *FeedService) CountFeed(userID uuid.UUID, opts FeedOptions) (FeedCount, error) {
+	filter, candidates, err := fs.newFeedFilter(userID, opts)
+	if err != nil {
+		return FeedCount{}, err
+	}
+
+	// A self-preview always shows exactly one profile.
+	if opts.IncludeSelf {
+		return FeedCount{Total: 1, Stages: []FeedStageCount{}}, nil
+	}
+
+	rejected := make(map[FeedStage]int, len(feedStages))
+	for _, candidate := range candidates {
+		if stage := filter.reject(candidate); stage != "" {
+			rejected[stage]++
+		}
+	}
+
+	// Each candidate is rejected by at most one stage, so subtracting in
+	// pipeline order gives how many are left after each.
+	remaining := len(candidates)
+	stages := make([]FeedStageCount, len(feedStages))
+	for i, stage := range feedStages {
+		remaining -= rejected[stage]
+		stages[i] = FeedStageCount{Stage: stage, Remaining: remaining}
+	}
+	return FeedCount{Total: remaining, Stages: stages}, nil
+}
+
+// FeedPage is one page of a cursor-paginated feed. NextCursor is empty on
+// the last page.
+type FeedPage struct {
+	Entries    []models.FeedEntry
+	NextCursor string
+}
+
+// feedCursorPrefix tags cursors with the sort order they belong to, so a
+// cursor issued for one ordering is rejected rather than misread by another.
+const feedCursorPrefix = "id:"
+
+// GetFeedPage returns up to limit feed entries that come after cursor, plus
+// the cursor for the next page. An empty cursor starts from the beginning.
+//
+// Offset paging ("skip the first 40") breaks when the pool changes between
+// requests: an insert shifts every later candidate and one gets shown twice
+// or skipped. A cursor instead records the last candidate's position in a
+// fixed order — here, ascending user ID — and the next page resumes
+// strictly after it, so candidates already paged past are never repeated
+// and new ones simply appear wherever their ID falls.
+//
+// Because the order must stay fixed between pages, the paged feed is not
+// subject to fairness demotion or boosts and does not record exposures.
+func (fs *FeedService) GetFeedPage(userID uuid.UUID, opts FeedOptions, cursor string, limit int) (FeedPage, error) {
+	if limit <= 0 {
+		return FeedPage{}, &ValidationError{Message: "limit must be positive"}
+	}
+	if opts.Sort != FeedSortDefault || opts.FreshnessWeight > 0 {
+		return FeedPage{}, &ValidationError{Message: "cursor paging only supports the default sort order"}
+	}
+
+	var after *uuid.UUID
+	if cursor != "" {
+		id, err := decodeFeedCursor(cursor)
+		if err != nil {
+			return FeedPage{}, err
+		}
+		after = &id
+	}
+
+	feed, err := fs.filterCandidates(userID, opts, 0)
+	if err != nil {
+		return FeedPage{}, err
+	}
+
+	// uuid.UUID is a [16]byte array, so bytes.Compare on its slice gives a
+	// total order that matches the order of the string forms.
+	sort.Slice(feed, func(i, j int) bool {
+		return bytes.Compare(feed[i].ID[:], feed[j].ID[:]) < 0
+	})
+
+	// Skip everything up to and including the cursor position.
+	start := 0
+	if after != nil {
+		start = sort.Search(len(feed), func(i int) bool {
+			return bytes.Compare(feed[i].ID[:], after[:]) > 0
+		})
+	}
+
+	end := min(start+limit, len(feed))
+	page := FeedPage{Entries: feed[start:end]}
+	if end < len(feed) {
+		page.NextCursor = encodeFeedCursor(feed[end-1].ID)
+	}
+	return page, nil
+}
+
+// encodeFeedCursor turns a sort position into an opaque, URL-safe token.
+// Clients should treat it as a black box; base64 discourages them from
+// building their own.
+func encodeFeedCursor(id uuid.UUID) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(feedCursorPrefix + id.String()))
+}
+
+// decodeFeedCursor reverses encodeFeedCursor, returning a ValidationError
+// for anything that isn't a cursor this service issued.
+func decodeFeedCursor(cursor string) (uuid.UUID, error) {
+	invalid := &ValidationError{Message: "cursor is invalid"}
+
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return uuid.Nil, invalid
+	}
+	idStr, ok := strings.CutPrefix(string(raw), feedCursorPrefix)
+	if !ok {
+		return uuid.Nil, invalid
+	}
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return uuid.Nil, invalid
+	}
+	return id, nil
+}
+
+// GetNetworkFeed returns the "second-degree" feed for the given user: people
+// matched with the user's own matches. It walks the match graph two hops out
+// and ignores zones and distance, so it can reach users the normal feed never
+// shows.
+//
+// A candidate reachable through several matches appears once. The user,
+// their direct matches, anyone they have already swiped on, and anyone
+// blocked in either direction are left out, and candidates must still fit
+// both users' gender and age preferences. Entries are in user ID order.
+func (fs *FeedService) GetNetworkFeed(userID uuid.UUID) ([]models.FeedEntry, error) {
+	fs.store.TouchUser(userID, fs.now().UTC())
+	filter, _, err := fs.newFeedFilter(userID, FeedOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	// Hop 1: the user's matches. They have already been seen, but a match
+	// can outlive the swipe that made it (see SetMaxSwipes), so they are
+	// excluded explicitly.
+	direct := make(map[uuid.UUID]struct{})
+	for _, match := range fs.store.GetMatchesForUser(userID) {
+		direct[otherUser(match, userID)] = struct{}{}
+	}
+
+	// Hop 2: the matches of those matches. Collecting IDs in a set
+	// deduplicates candidates reachable through more than one match.
+	second := make(map[uuid.UUID]struct{})
+	for friendID := range direct {
+		for _, match := range fs.store.GetMatchesForUser(friendID) {
+			id := otherUser(match, friendID)
+			if id == userID {
+				continue
+			}
+			if _, isDirect := direct[id]; isDirect {
+				continue
+			}
+			if _, swiped := filter.seen[id]; swiped {
+				continue
+			}
+			if _, blocked := filter.blocked[id]; blocked {
+				continue
+			}
+			second[id] = struct{}{}
+		}
+	}
+
+	feed := []models.FeedEntry{}
+	for id := range second {
+		// A user deleted since the match was made is skipped.
+		candidate, exists := fs.store.GetUser(id)
+		if !exists || !preferencesCompatible(filter.requester, candidate) {
+			continue
+		}
+		feed = append(feed, models.FeedEntry{
+			User:       candidate,
+			Online:     filter.isOnline(candidate),
+			DistanceKm: filter.distanceTo(candidate),
+		})
+	}
+
+	// Map iteration order is random, so sort for a stable response.
+	sort.Slice(feed, func(i, j int) bool {
+		return bytes.Compare(feed[i].ID[:], feed[j].ID[:]) < 0
+	})
+	return feed, nil
+}
+
+// matchesPreference reports whether a candidate's gender satisfies the
+// requester's InterestedIn preference. An empty preference predates the
+// field and means "everyone". Comparison ignores case.
+func matchesPreference(interestedIn, gender string) bool {
+	if interestedIn == "" || strings.EqualFold(interestedIn, models.InterestedInEveryone) {
+		return true
+	}
+	return strings.EqualFold(interestedIn, gender)
+}
+
+// inAgeRange reports whether age is within the user's [MinAge, MaxAge]
+// preference. A zero bound is unset and doesn't constrain that side.
+func inAgeRange(user models.User, age int) bool {
+	if user.MinAge > 0 && age < user.MinAge {
+		return false
+	}
+	if user.MaxAge > 0 && age > user.MaxAge {
+		return false
+	}
+	return true
+}
+
+// wouldLike reports whether candidate fits viewer's gender and age
+// preferences.
+func wouldLike(viewer, candidate models.User) bool {
+	return matchesPreference(viewer.InterestedIn, candidate.Gender) && inAgeRange(viewer, candidate.Age)
+}
+
+// preferencesCompatible reports whether a and b each fit the other's
+// preferences. The feed only shows compatible candidates and the swipe
+// service only matches compatible pairs.
+func preferencesCompatible(a, b models.User) bool {
+	return wouldLike(a, b) && wouldLike(b, a)
+}
+
+// earthRadiusKm is the Earth's mean radius, used by haversineKm.
+const earthRadiusKm = 6371.0
+
+// haversineKm returns the great-circle distance in kilometers between two
+// points given in decimal degrees. The haversine formula treats the Earth
+// as a sphere, which is accurate to within about 0.5% — plenty for "how
+// far away is this person".
+func haversineKm(lat1, lon1, lat2, lon2 float64) float64 {
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+	dLat := toRad(lat2 - lat1)
+	dLon := toRad(lon2 - lon1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(a))
+}
+
+// hasAllTags reports whether tags contains every tag in required. An empty
+// required list is always satisfied.
+func hasAllTags(tags, required []string) bool {
+	for _, want := range required {
+		if !slices.Contains(tags, want) {
+			return false
+		}
+	}
+	return true
+}
+
+// sortFeed orders the feed in place as by asks. It first sorts by user ID,
+// and every later sort is stable, so ties keep ID order.
+func sortFeed(feed []models.FeedEntry, by FeedSort, requester models.User) {
+	sort.Slice(feed, func(i, j int) bool {
+		return bytes.Compare(feed[i].ID[:], feed[j].ID[:]) < 0
+	})
+
+	switch by {
+	case FeedSortInterests:
+		sortByTagOverlap(feed, requester.Tags)
+	case FeedSortNewest:
+		sort.SliceStable(feed, func(i, j int) bool {
+			return feed[i].JoinedAt.After(feed[j].JoinedAt)
+		})
+	case FeedSortAgeAsc:
+		sort.SliceStable(feed, func(i, j int) bool {
+			return feed[i].Age < feed[j].Age
+		})
+	case FeedSortAgeDesc:
+		sort.SliceStable(feed, func(i, j int) bool {
+			return feed[i].Age > feed[j].Age
+		})
+	case FeedSortDistance:
+		sort.SliceStable(feed, func(i, j int) bool {
+			di, dj := feed[i].DistanceKm, feed[j].DistanceKm
+			if di == nil || dj == nil {
+				return di != nil && dj == nil
+			}
+			return *di < *dj
+		})
+	case FeedSortRecentActivity:
+		sort.SliceStable(feed, func(i, j int) bool {
+			return feed[i].LastActiveAt.After(feed[j].LastActiveAt)
+		})
+	}
+}
+
+// sortByLikesReceived reorders the feed in place so candidates with fewer
+// LIKEs received come first. It is a method, unlike the sorts in sortFeed,
+// because the counts come from the store rather than the feed entries; they
+// are fetched for the whole feed at once. The sort is stable, so candidates
+// with equal counts keep ID order.
+func (fs *FeedService) sortByLikesReceived(feed []models.FeedEntry) {
+	ids := make([]uuid.UUID, len(feed))
+	for i, entry := range feed {
+		ids[i] = entry.ID
+	}
+	likes := fs.store.CountLikesReceived(ids)
+	sort.SliceStable(feed, func(i, j int) bool {
+		return likes[feed[i].ID] < likes[feed[j].ID]
+	})
+}
+
+// promoteBoosted moves candidates with a boost running at now to the front
+// of the feed, keeping the existing order within each group. Expired boosts
+// are treated like no boost at all.
+func promoteBoosted(feed []models.FeedEntry, now time.Time) {
+	sort.SliceStable(feed, func(i, j int) bool {
+		return feed[i].BoostedAt(now) && !feed[j].BoostedAt(now)
+	})
+}
+
+// sortByTagOverlap orders the feed in place by descending tag overlap with
+// the requester's tags. Ties, including the common case of no tags at all,
+// fall back to user ID so the order is stable across requests.
+func sortByTagOverlap(feed []models.FeedEntry, tags []string) {
+	scores := make(map[uuid.UUID]float64, len(feed))
+	for _, entry := range feed {
+		scores[entry.ID] = tagOverlap(tags, entry.Tags)
+	}
+	sort.Slice(feed, func(i, j int) bool {
+		si, sj := scores[feed[i].ID], scores[feed[j].ID]
+		if si != sj {
+			return si > sj
+		}
+		return bytes.Compare(feed[i].ID[:], feed[j].ID[:]) < 0
+	})
+}
+
+// blendFreshness reorders the feed in place so newer users rise by an
+// amount set by weight (0–1). Each candidate gets the score
+//
+//	(1-weight)*rank + weight*recencyRank
+//
+// where rank is their current position and recencyRank their position when
+// sorted by JoinedAt, newest first. Sorting by that score moves a new user
+// part of the way toward the top instead of straight to it, so fresh
+// profiles get seen without crowding out everyone else. Equal scores keep
+// the current order.
+func blendFreshness(feed []models.FeedEntry, weight float64) {
+	byRecency := make([]int, len(feed))
+	for i := range byRecency {
+		byRecency[i] = i
+	}
+	sort.SliceStable(byRecency, func(a, b int) bool {
+		return feed[byRecency[a]].JoinedAt.After(feed[byRecency[b]].JoinedAt)
+	})
+
+	scores := make(map[uuid.UUID]float64, len(feed))
+	for recencyRank, i := range byRecency {
+		scores[feed[i].ID] = (1-weight)*float64(i) + weight*float64(recencyRank)
+	}
+	sort.SliceStable(feed, func(i, j int) bool {
+		return scores[feed[i].ID] < scores[feed[j].ID]
+	})
+}
+
+// tagOverlap returns the Jaccard index of two tag lists: the number of
+// shared tags divided by the number of distinct tags across both. It ranges
+// from 0 (nothing in common, or no tags) to 1 (identical sets). Dividing by
+// the union keeps users with very long tag lists from winning just by
+// listing everything.
+func tagOverlap(a, b []string) float64 {
+	set := make(map[string]struct{}, len(a))
+	for _, tag := range a {
+		set[tag] = struct{}{}
+	}
+
+	union := len(set)
+	shared := 0
+	counted := make(map[string]struct{}, len(b))
+	for _, tag := range b {
+		if _, dup := counted[tag]; dup {
+			continue
+		}
+		counted[tag] = struct{}{}
+		if _, ok := set[tag]; ok {
+			shared++
+		} else {
+			union++
+		}
+	}
+
+	if union == 0 {
+		return 0
+	}
+	return float64(shared) / float64(union)
+}
+
+// demoteOverexposed reorders the feed in place so candidates with fewer
+// top-of-feed exposures come first. Candidates are grouped into tiers of
+// exposureDemotion appearances; sort.SliceStable keeps the existing order
+// within a tier, so the demotion is mild rather than a full re-ranking.
+func (fs *FeedService) demoteOverexposed(feed []models.FeedEntry) {
+	exposures := fs.store.GetExposureCounts()
+	sort.SliceStable(feed, func(i, j int) bool {
+		return exposures[feed[i].ID]/fs.exposureDemotion < exposures[feed[j].ID]/fs.exposureDemotion
+	})
+}
+
+// locationReason says why a candidate that passed the filters counts as
+// close by: they are within the requester's distance limit, in the same
+// zone, or in a neighboring zone.
+func (f *feedFilter) locationReason(candidate models.User) string {
+	switch {
+	case f.byDistance(candidate):
+		return ReasonNearby
+	case f.fs.canonicalZone(candidate.ZoneID) == f.requesterZone:
+		return ReasonSameZone
+	default:
+		return ReasonNeighborZone
+	}
+}
+
+// feedReasons explains why a candidate that passed the filters is shown.
+// The first reason is always where they are, from locationReason; other
+// reasons depend on the candidate's relationship to the requester. The age
+// range reason is only given when the requester has set a MinAge or MaxAge:
+// without either, every age is in range and saying so explains nothing.
+func feedReasons(requester, candidate models.User, likedBy map[uuid.UUID]struct{}, location string) []string {
+	reasons := []string{location}
+	if (requester.MinAge > 0 || requester.MaxAge > 0) && inAgeRange(requester, candidate.Age) {
+		reasons = append(reasons, ReasonAgeRange)
+	}
+	if _, liked := likedBy[candidate.ID]; liked {
+		reasons = append(reasons, ReasonLikedYou)
+	}
+	return reasons
+}
@@ -1,12 +1,21 @@
 // Package services contains tests for the FeedService.
 //
-// These unit tests verify the three-tier filtering pipeline:
+// These unit tests verify the four-tier filtering pipeline:
 //  1. Zone filter — only same-zone users appear
-//  2. Self-exclusion — the requesting user is removed
-//  3. Seen-state filter — already-swiped users are removed
+//  2. Preferences — only the genders and ages the requester wants appear
+//  3. Self-exclusion — the requesting user is removed
+//  4. Seen-state filter — already-swiped users are removed
 package services
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -25,14 +34,17 @@ func setupFeedTest(t *testing.T) (*FeedService, *store.InMemoryStore) {
 }
 
 // makeTestUser creates and stores a user with the given name and zone.
+// Notification preferences default to on, matching the create-user handler.
 // It returns the created User for use in assertions.
 func makeTestUser(s *store.InMemoryStore, name, zone string) models.User {
 	user := models.User{
-		ID:     uuid.New(),
-		Name:   name,
-		Age:    25,
-		Gender: "other",
-		ZoneID: zone,
+		ID:            uuid.New(),
+		Name:          name,
+		Age:           25,
+		Gender:        "other",
+		ZoneID:        zone,
+		NotifyOnLike:  true,
+		NotifyOnMatch: true,
 	}
 	s.AddUser(user)
 	return user
@@ -46,7 +58,7 @@ func TestGetFeed_UserNotFound(t *testing.T) {
 	fs, _ := setupFeedTest(t)
 
 	// Requesting a feed for a non-existent user should return an error.
-	_, err := fs.GetFeed(uuid.New())
+	_, _, err := fs.GetFeed(uuid.New(), FeedOptions{})
 	if err == nil {
 		t.Fatal("expected error for non-existent user")
 	}
@@ -60,7 +72,7 @@ func TestGetFeed_ZoneFiltering(t *testing.T) {
 	makeTestUser(s, "Bob", "zone-a")     // Same zone as Alice.
 	makeTestUser(s, "Charlie", "zone-b") // Different zone.
 
-	feed, err := fs.GetFeed(alice.ID)
+	feed, _, err := fs.GetFeed(alice.ID, FeedOptions{})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -80,7 +92,7 @@ func TestGetFeed_SelfExclusion(t *testing.T) {
 	// Create a single user — their feed should be empty (only themselves in zone).
 	alice := makeTestUser(s, "Alice", "zone-a")
 
-	feed, err := fs.GetFeed(alice.ID)
+	feed, _, err := fs.GetFeed(alice.ID, FeedOptions{})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -107,7 +119,7 @@ func TestGetFeed_SeenStateFiltering(t *testing.T) {
 		Timestamp: time.Now().UTC(),
 	})
 
-	feed, err := fs.GetFeed(alice.ID)
+	feed, _, err := fs.GetFeed(alice.ID, FeedOptions{})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -135,7 +147,7 @@ func TestGetFeed_PassAlsoFiltersSeen(t *testing.T) {
 		Timestamp: time.Now().UTC(),
 	})
 
-	feed, err := fs.GetFeed(alice.ID)
+	feed, _, err := fs.GetFeed(alice.ID, FeedOptions{})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -152,7 +164,7 @@ func TestGetFeed_EmptyFeedReturnsEmptySlice(t *testing.T) {
 	// This is important for JSON serialization: [] vs null.
 	alice := makeTestUser(s, "Alice", "zone-a")
 
-	feed, err := fs.GetFeed(alice.ID)
+	feed, _, err := fs.GetFeed(alice.ID, FeedOptions{})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -175,7 +187,7 @@ func TestGetFeed_MultipleZones(t *testing.T) {
 	makeTestUser(s, "Diana", "zone-c")
 	makeTestUser(s, "Eve", "zone-a")
 
-	feed, err := fs.GetFeed(alice.ID)
+	feed, _, err := fs.GetFeed(alice.ID, FeedOptions{})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -192,3 +204,1519 @@ func TestGetFeed_MultipleZones(t *testing.T) {
 		}
 	}
 }
+
+func TestGetFeed_ExcludeOption(t *testing.T) {
+	fs, s := setupFeedTest(t)
+
+	alice := makeTestUser(s, "Alice", "zone-a")
+	bob := makeTestUser(s, "Bob", "zone-a")
+	charlie := makeTestUser(s, "Charlie", "zone-a")
+	dave := makeTestUser(s, "Dave", "zone-a")
+
+	feed, _, err := fs.GetFeed(alice.ID, FeedOptions{Exclude: []uuid.UUID{bob.ID, charlie.ID}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(feed) != 1 || feed[0].ID != dave.ID {
+		t.Fatalf("expected only Dave in feed, got %+v", feed)
+	}
+
+	// Exclusion is per-call: nothing was recorded as a swipe.
+	if swipes := s.GetSwipesByUser(alice.ID); len(swipes) != 0 {
+		t.Errorf("expected no swipes recorded, got %d", len(swipes))
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Reason annotation tests
+// ---------------------------------------------------------------------------
+
+// hasReason reports whether the entry carries the given reason.
+func hasReason(entry models.FeedEntry, reason string) bool {
+	for _, r := range entry.Reasons {
+		if r == reason {
+			return true
+		}
+	}
+	return false
+}
+
+func TestGetFeed_ReasonsIncludeLikedYou(t *testing.T) {
+	fs, s := setupFeedTest(t)
+
+	alice := makeTestUser(s, "Alice", "zone-a")
+	bob := makeTestUser(s, "Bob", "zone-a")
+	charlie := makeTestUser(s, "Charlie", "zone-a")
+
+	// Bob likes Alice; Charlie has not swiped on her.
+	s.AddSwipe(models.Swipe{
+		SwiperID:  bob.ID,
+		SwipedID:  alice.ID,
+		Action:    models.SwipeActionLike,
+		Timestamp: time.Now().UTC(),
+	})
+
+	feed, _, err := fs.GetFeed(alice.ID, FeedOptions{IncludeReasons: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(feed) != 2 {
+		t.Fatalf("expected 2 users in feed, got %d", len(feed))
+	}
+
+	for _, entry := range feed {
+		if !hasReason(entry, ReasonSameZone) {
+			t.Errorf("%s: expected %q reason", entry.Name, ReasonSameZone)
+		}
+		switch entry.ID {
+		case bob.ID:
+			if !hasReason(entry, ReasonLikedYou) {
+				t.Errorf("expected Bob to carry %q, got %v", ReasonLikedYou, entry.Reasons)
+			}
+		case charlie.ID:
+			if hasReason(entry, ReasonLikedYou) {
+				t.Errorf("Charlie should not carry %q", ReasonLikedYou)
+			}
+		}
+	}
+}
+
+func TestGetFeed_ReasonsIncludeAgeRange(t *testing.T) {
+	fs, s := setupFeedTest(t)
+
+	alice := makeTestUser(s, "Alice", "zone-a")
+	bob := makeTestUser(s, "Bob", "zone-a")
+
+	// Without an age preference every age is in range, so there's no reason.
+	feed, _, err := fs.GetFeed(alice.ID, FeedOptions{IncludeReasons: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(feed) != 1 || hasReason(feed[0], ReasonAgeRange) {
+		t.Fatalf("expected Bob without %q, got %v", ReasonAgeRange, feed)
+	}
+
+	s.ModifyUser(alice.ID, func(u *models.User) {
+		u.MinAge = bob.Age - 1
+		u.MaxAge = bob.Age + 1
+	})
+	feed, _, err = fs.GetFeed(alice.ID, FeedOptions{IncludeReasons: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(feed) != 1 || !hasReason(feed[0], ReasonAgeRange) {
+		t.Errorf("expected Bob to carry %q, got %v", ReasonAgeRange, feed)
+	}
+}
+
+func TestGetFeed_NoReasonsByDefault(t *testing.T) {
+	fs, s := setupFeedTest(t)
+
+	alice := makeTestUser(s, "Alice", "zone-a")
+	makeTestUser(s, "Bob", "zone-a")
+
+	feed, _, err := fs.GetFeed(alice.ID, FeedOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(feed) != 1 || feed[0].Reasons != nil {
+		t.Errorf("expected no reasons without IncludeReasons, got %+v", feed)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Zone alias tests
+// ---------------------------------------------------------------------------
+
+// makeTestUserWithGender stores a user with the given gender and gender
+// preference, building on makeTestUser's defaults.
+func makeTestUserWithGender(s *store.InMemoryStore, name, gender, interestedIn string) models.User {
+	user := makeTestUser(s, name, "zone-a")
+	user.Gender = gender
+	user.InterestedIn = interestedIn
+	s.UpdateUser(user)
+	return user
+}
+
+func TestGetFeed_GenderPreference(t *testing.T) {
+	fs, s := setupFeedTest(t)
+
+	makeTestUserWithGender(s, "Alice", "female", models.InterestedInEveryone)
+	makeTestUserWithGender(s, "Beth", "female", models.InterestedInEveryone)
+	makeTestUserWithGender(s, "Carl", "male", models.InterestedInEveryone)
+	makeTestUserWithGender(s, "Sam", "nonbinary", models.InterestedInEveryone)
+
+	tests := []struct {
+		name         string
+		interestedIn string
+		want         []string
+	}{
+		{"only female", "female", []string{"Alice", "Beth"}},
+		{"only male", "male", []string{"Carl"}},
+		{"case-insensitive", "Female", []string{"Alice", "Beth"}},
+		{"everyone", models.InterestedInEveryone, []string{"Alice", "Beth", "Carl", "Sam"}},
+		{"unset means everyone", "", []string{"Alice", "Beth", "Carl", "Sam"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			viewer := makeTestUserWithGender(s, "Viewer", "male", tc.interestedIn)
+			defer s.DeleteUser(viewer.ID) // Keep the viewer out of later subtests.
+
+			feed, _, err := fs.GetFeed(viewer.ID, FeedOptions{})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got := make(map[string]bool)
+			for _, entry := range feed {
+				got[entry.Name] = true
+			}
+			if len(got) != len(tc.want) {
+				t.Errorf("expected %v, got %v", tc.want, got)
+			}
+			for _, name := range tc.want {
+				if !got[name] {
+					t.Errorf("expected %s in feed, got %v", name, got)
+				}
+			}
+		})
+	}
+}
+
+func TestGetFeed_AgeRange(t *testing.T) {
+	fs, s := setupFeedTest(t)
+
+	viewer := makeTestUser(s, "Viewer", "zone-a")
+	viewer.Age = 25
+	viewer.MinAge = 30
+	viewer.MaxAge = 40
+	s.UpdateUser(viewer)
+
+	for name, age := range map[string]int{"Young": 29, "Low": 30, "Mid": 35, "High": 40, "Old": 41} {
+		user := makeTestUser(s, name, "zone-a")
+		user.Age = age
+		s.UpdateUser(user)
+	}
+
+	feed, _, err := fs.GetFeed(viewer.ID, FeedOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := make(map[string]bool)
+	for _, entry := range feed {
+		got[entry.Name] = true
+	}
+	if len(got) != 3 || !got["Low"] || !got["Mid"] || !got["High"] {
+		t.Errorf("expected only Low, Mid and High (ages 30–40), got %v", got)
+	}
+}
+
+func TestGetFeed_MutualPreferences(t *testing.T) {
+	fs, s := setupFeedTest(t)
+
+	// The viewer is a 25-year-old man open to everyone; each candidate
+	// fits his preferences, but not all of them would want him.
+	viewer := makeTestUserWithGender(s, "Viewer", "male", models.InterestedInEveryone)
+	makeTestUserWithGender(s, "Alice", "female", "male")
+	beth := makeTestUserWithGender(s, "Beth", "female", "female")
+	makeTestUserWithGender(s, "Cleo", "female", models.InterestedInEveryone)
+	dana := makeTestUserWithGender(s, "Dana", "female", models.InterestedInEveryone)
+	dana.MinAge = 30
+	s.UpdateUser(dana)
+
+	feed, _, err := fs.GetFeed(viewer.ID, FeedOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := make(map[string]bool)
+	for _, entry := range feed {
+		got[entry.Name] = true
+	}
+	if len(got) != 2 || !got["Alice"] || !got["Cleo"] {
+		t.Errorf("expected only Alice and Cleo, got %v", got)
+	}
+
+	// It works the same from the other side: Beth doesn't see the viewer.
+	feed, _, err = fs.GetFeed(beth.ID, FeedOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, entry := range feed {
+		if entry.ID == viewer.ID {
+			t.Error("expected the viewer to be hidden from Beth")
+		}
+	}
+}
+
+func TestInAgeRange_UnsetBounds(t *testing.T) {
+	tests := []struct {
+		name     string
+		min, max int
+		age      int
+		want     bool
+	}{
+		{"no bounds", 0, 0, 99, true},
+		{"min only, below", 30, 0, 29, false},
+		{"min only, above", 30, 0, 80, true},
+		{"max only, above", 0, 40, 41, false},
+		{"max only, below", 0, 40, 18, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			user := models.User{MinAge: tc.min, MaxAge: tc.max}
+			if got := inAgeRange(user, tc.age); got != tc.want {
+				t.Errorf("inAgeRange(%d–%d, %d) = %v, want %v", tc.min, tc.max, tc.age, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGetFeed_IncludeSelfReturnsOnlyRequester(t *testing.T) {
+	fs, s := setupFeedTest(t)
+	// Exposures are only recorded while demotion is on.
+	fs.SetExposureDemotion(100)
+
+	alice := makeTestUser(s, "Alice", "zone-a")
+	makeTestUser(s, "Bob", "zone-a")
+	makeTestUser(s, "Charlie", "zone-a")
+
+	feed, _, err := fs.GetFeed(alice.ID, FeedOptions{IncludeSelf: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(feed) != 1 || feed[0].ID != alice.ID {
+		t.Fatalf("expected exactly Alice, got %+v", feed)
+	}
+
+	// The preview must not count as a top-of-feed exposure.
+	if n := s.GetExposureCounts()[alice.ID]; n != 0 {
+		t.Errorf("expected no exposure recorded for a preview, got %d", n)
+	}
+}
+
+func TestGetFeed_OnlineFlagAndFilter(t *testing.T) {
+	fs, s := setupFeedTest(t)
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	fs.SetClock(func() time.Time { return now })
+	fs.SetOnlineWindow(5 * time.Minute)
+
+	alice := makeTestUser(s, "Alice", "zone-a")
+
+	// addActive stores a candidate whose last activity was `ago` before now.
+	addActive := func(name string, ago time.Duration) uuid.UUID {
+		user := makeTestUser(s, name, "zone-a")
+		user.LastActiveAt = now.Add(-ago)
+		s.UpdateUser(user)
+		return user.ID
+	}
+	wantOnline := map[uuid.UUID]bool{
+		addActive("Recent", time.Minute):                     true,
+		addActive("AtBoundary", 5*time.Minute):               true,
+		addActive("JustPast", 5*time.Minute+time.Nanosecond): false,
+		addActive("Stale", time.Hour):                        false,
+	}
+
+	t.Run("flag on every entry", func(t *testing.T) {
+		feed, _, err := fs.GetFeed(alice.ID, FeedOptions{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(feed) != len(wantOnline) {
+			t.Fatalf("expected %d entries, got %d", len(wantOnline), len(feed))
+		}
+		for _, entry := range feed {
+			if entry.Online != wantOnline[entry.ID] {
+				t.Errorf("%s: online=%v, want %v", entry.Name, entry.Online, wantOnline[entry.ID])
+			}
+		}
+	})
+
+	t.Run("online_only filter", func(t *testing.T) {
+		feed, _, err := fs.GetFeed(alice.ID, FeedOptions{OnlineOnly: true})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(feed) != 2 {
+			t.Fatalf("expected 2 online candidates, got %d", len(feed))
+		}
+		for _, entry := range feed {
+			if !wantOnline[entry.ID] {
+				t.Errorf("%s should have been filtered out", entry.Name)
+			}
+		}
+	})
+}
+
+func TestGetFeed_ZoneAliasesShareFeed(t *testing.T) {
+	fs, s := setupFeedTest(t)
+	fs.SetZoneAliases(map[string]string{"nyc": "new-york"})
+
+	alice := makeTestUser(s, "Alice", "nyc")
+	makeTestUser(s, "Bob", "New-York") // Canonical zone, different case.
+	makeTestUser(s, "Charlie", "boston")
+
+	feed, _, err := fs.GetFeed(alice.ID, FeedOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(feed) != 1 {
+		t.Fatalf("expected 1 user in feed, got %d", len(feed))
+	}
+	if feed[0].Name != "Bob" {
+		t.Errorf("expected Bob in feed, got %s", feed[0].Name)
+	}
+}
+
+func TestGetFeed_UnaliasedZonesStaySeparate(t *testing.T) {
+	fs, s := setupFeedTest(t)
+	fs.SetZoneAliases(map[string]string{"nyc": "new-york"})
+
+	alice := makeTestUser(s, "Alice", "boston")
+	makeTestUser(s, "Bob", "new-york")
+	makeTestUser(s, "Charlie", "nyc")
+
+	feed, _, err := fs.GetFeed(alice.ID, FeedOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(feed) != 0 {
+		t.Errorf("expected empty feed for unaliased zone, got %d users", len(feed))
+	}
+}
+
+func TestGetFeed_NoAliasesKeepsExactMatching(t *testing.T) {
+	fs, s := setupFeedTest(t)
+
+	// Without aliases, zones are compared exactly (including case).
+	alice := makeTestUser(s, "Alice", "zone-a")
+	makeTestUser(s, "Bob", "Zone-A")
+
+	feed, _, err := fs.GetFeed(alice.ID, FeedOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(feed) != 0 {
+		t.Errorf("expected empty feed without aliases, got %d users", len(feed))
+	}
+}
+
+func TestGetFeed_ZoneAdjacency(t *testing.T) {
+	fs, s := setupFeedTest(t)
+	fs.SetZoneAdjacency(map[string][]string{"zone-a": {"zone-b"}})
+
+	alice := makeTestUser(s, "Alice", "zone-a")
+	makeTestUser(s, "Bob", "zone-a")
+	carol := makeTestUser(s, "Carol", "zone-b")
+	dave := makeTestUser(s, "Dave", "zone-c")
+
+	tests := []struct {
+		name      string
+		requester models.User
+		want      []string
+	}{
+		{"own zone and neighbor", alice, []string{"Bob", "Carol"}},
+		{"adjacency works both ways", carol, []string{"Alice", "Bob"}},
+		{"zone without neighbors", dave, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			feed, _, err := fs.GetFeed(tt.requester.ID, FeedOptions{})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got := feedNames(feed)
+			sort.Strings(got)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGetFeed_ZoneAdjacencyReasons(t *testing.T) {
+	fs, s := setupFeedTest(t)
+	fs.SetZoneAdjacency(map[string][]string{"zone-a": {"zone-b"}})
+
+	alice := makeTestUser(s, "Alice", "zone-a")
+	makeTestUser(s, "Bob", "zone-a")
+	makeTestUser(s, "Carol", "zone-b")
+
+	feed, _, err := fs.GetFeed(alice.ID, FeedOptions{IncludeReasons: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]string{"Bob": ReasonSameZone, "Carol": ReasonNeighborZone}
+	for _, entry := range feed {
+		if !hasReason(entry, want[entry.Name]) {
+			t.Errorf("%s: expected %q, got %v", entry.Name, want[entry.Name], entry.Reasons)
+		}
+	}
+}
+
+func TestGetFeed_ZoneAdjacencyUsesAliases(t *testing.T) {
+	fs, s := setupFeedTest(t)
+	fs.SetZoneAdjacency(map[string][]string{"new-york": {"jersey-city"}})
+	fs.SetZoneAliases(map[string]string{"nyc": "new-york"})
+
+	alice := makeTestUser(s, "Alice", "nyc")
+	makeTestUser(s, "Bob", "Jersey-City")
+
+	feed, _, err := fs.GetFeed(alice.ID, FeedOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if names := feedNames(feed); !slices.Equal(names, []string{"Bob"}) {
+		t.Errorf("expected [Bob], got %v", names)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Distance tests
+// ---------------------------------------------------------------------------
+
+// makeTestUserAt creates a user with coordinates in the given zone.
+func makeTestUserAt(s *store.InMemoryStore, name, zone string, lat, lon float64) models.User {
+	user := makeTestUser(s, name, zone)
+	user.Latitude, user.Longitude = &lat, &lon
+	s.UpdateUser(user)
+	return user
+}
+
+// feedNames lists the names in a feed, for failure messages.
+func feedNames(feed []models.FeedEntry) []string {
+	names := make([]string, len(feed))
+	for i, entry := range feed {
+		names[i] = entry.Name
+	}
+	return names
+}
+
+func TestGetFeed_Boost(t *testing.T) {
+	fs, s := setupFeedTest(t)
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	fs.SetClock(func() time.Time { return now })
+
+	viewer := makeTestUser(s, "Viewer", "zone-a")
+	var users []models.User
+	for _, name := range []string{"Alice", "Bob", "Carol", "Dave"} {
+		users = append(users, makeTestUser(s, name, "zone-a"))
+	}
+	boost := func(user models.User, until time.Time) {
+		user.BoostUntil = &until
+		s.UpdateUser(user)
+	}
+
+	// The unboosted order is by ID; the test doesn't depend on what it is.
+	feed, _, _ := fs.GetFeed(viewer.ID, FeedOptions{})
+	plain := feedNames(feed)
+	last := users[slices.IndexFunc(users, func(u models.User) bool { return u.Name == plain[3] })]
+	expired := users[slices.IndexFunc(users, func(u models.User) bool { return u.Name == plain[2] })]
+
+	boost(last, now.Add(10*time.Minute))
+	boost(expired, now) // Ends exactly now, so no longer active.
+
+	feed, _, err := fs.GetFeed(viewer.ID, FeedOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{plain[3], plain[0], plain[1], plain[2]}
+	if got := feedNames(feed); !slices.Equal(got, want) {
+		t.Errorf("expected the boosted user first and the rest in order: got %v, want %v", got, want)
+	}
+
+	// Once the boost runs out, the feed goes back to normal.
+	now = now.Add(10 * time.Minute)
+	feed, _, _ = fs.GetFeed(viewer.ID, FeedOptions{})
+	if got := feedNames(feed); !slices.Equal(got, plain) {
+		t.Errorf("expected no effect from expired boosts: got %v, want %v", got, plain)
+	}
+}
+
+func TestHaversineKm(t *testing.T) {
+	tests := []struct {
+		name                   string
+		lat1, lon1, lat2, lon2 float64
+		want                   float64
+	}{
+		{"same point", 40.7128, -74.0060, 40.7128, -74.0060, 0},
+		{"London to Paris", 51.5074, -0.1278, 48.8566, 2.3522, 343.6},
+		{"New York to Los Angeles", 40.7128, -74.0060, 34.0522, -118.2437, 3935.7},
+		{"across the antimeridian", 0, 179.5, 0, -179.5, 111.2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := haversineKm(tt.lat1, tt.lon1, tt.lat2, tt.lon2)
+			if math.Abs(got-tt.want) > 1 {
+				t.Errorf("haversineKm = %.1f, want about %.1f", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetFeed_MaxDistance(t *testing.T) {
+	fs, s := setupFeedTest(t)
+
+	// Alice is in central London and wants people within 50 km.
+	alice := makeTestUserAt(s, "Alice", "london", 51.5074, -0.1278)
+	alice.MaxDistanceKm = 50
+	s.UpdateUser(alice)
+
+	makeTestUserAt(s, "Near", "surrey", 51.3148, -0.5600) // ~36 km, other zone
+	makeTestUserAt(s, "Far", "london", 48.8566, 2.3522)   // Paris, same zone
+	makeTestUser(s, "NoCoordsSameZone", "london")
+	makeTestUser(s, "NoCoordsOtherZone", "surrey")
+
+	feed, _, err := fs.GetFeed(alice.ID, FeedOptions{IncludeReasons: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := make(map[string]models.FeedEntry)
+	for _, entry := range feed {
+		got[entry.Name] = entry
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected Near and NoCoordsSameZone, got %v", feedNames(feed))
+	}
+
+	near, ok := got["Near"]
+	if !ok {
+		t.Fatal("expected Near (within 50 km) in the feed")
+	}
+	if near.DistanceKm == nil || *near.DistanceKm < 30 || *near.DistanceKm > 40 {
+		t.Errorf("expected Near about 36 km away, got %v", near.DistanceKm)
+	}
+	if !hasReason(near, ReasonNearby) {
+		t.Errorf("expected Near to carry %q, got %v", ReasonNearby, near.Reasons)
+	}
+
+	// Without coordinates, the zone decides and no distance is reported.
+	fallback, ok := got["NoCoordsSameZone"]
+	if !ok {
+		t.Fatal("expected the same-zone user without coordinates in the feed")
+	}
+	if fallback.DistanceKm != nil {
+		t.Errorf("expected no distance without coordinates, got %v", *fallback.DistanceKm)
+	}
+	if !hasReason(fallback, ReasonSameZone) {
+		t.Errorf("expected %q, got %v", ReasonSameZone, fallback.Reasons)
+	}
+}
+
+func TestGetFeed_NoMaxDistanceUsesZones(t *testing.T) {
+	fs, s := setupFeedTest(t)
+
+	// Both have coordinates, but Alice has no distance limit, so zones
+	// still apply; the distance is reported anyway.
+	alice := makeTestUserAt(s, "Alice", "london", 51.5074, -0.1278)
+	makeTestUserAt(s, "Bob", "london", 51.5007, -0.1246)
+	makeTestUserAt(s, "Carol", "surrey", 51.3148, -0.5600)
+
+	feed, _, err := fs.GetFeed(alice.ID, FeedOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(feed) != 1 || feed[0].Name != "Bob" {
+		t.Fatalf("expected only Bob, got %v", feedNames(feed))
+	}
+	if feed[0].DistanceKm == nil || *feed[0].DistanceKm != 0.8 {
+		t.Errorf("expected Bob 0.8 km away, got %v", feed[0].DistanceKm)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Fairness tests
+// ---------------------------------------------------------------------------
+
+func TestGetFeed_RecordsTopExposure(t *testing.T) {
+	fs, s := setupFeedTest(t)
+
+	alice := makeTestUser(s, "Alice", "zone-a")
+	bob := makeTestUser(s, "Bob", "zone-a")
+
+	// With demotion off nothing reads the counts, so none are recorded.
+	if _, _, err := fs.GetFeed(alice.ID, FeedOptions{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if counts := s.GetExposureCounts(); len(counts) != 0 {
+		t.Errorf("expected no exposures with demotion off, got %v", counts)
+	}
+
+	fs.SetExposureDemotion(100)
+	for i := 0; i < 3; i++ {
+		if _, _, err := fs.GetFeed(alice.ID, FeedOptions{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if got := s.GetExposureCounts()[bob.ID]; got != 3 {
+		t.Errorf("expected Bob to have 3 exposures, got %d", got)
+	}
+}
+
+func TestGetFeed_DemotesOverexposedCandidate(t *testing.T) {
+	fs, s := setupFeedTest(t)
+	fs.SetExposureDemotion(2)
+
+	alice := makeTestUser(s, "Alice", "zone-a")
+	popular := makeTestUser(s, "Popular", "zone-a")
+	fresh := makeTestUser(s, "Fresh", "zone-a")
+
+	// Popular has already topped several feeds.
+	for i := 0; i < 3; i++ {
+		s.RecordExposure(popular.ID)
+	}
+
+	feed, _, err := fs.GetFeed(alice.ID, FeedOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(feed) != 2 {
+		t.Fatalf("expected 2 users in feed, got %d", len(feed))
+	}
+	if feed[0].ID != fresh.ID {
+		t.Errorf("expected less-exposed Fresh first, got %s", feed[0].Name)
+	}
+}
+
+func TestGetFeed_TopCandidateRotatesAfterServing(t *testing.T) {
+	fs, s := setupFeedTest(t)
+	fs.SetExposureDemotion(1)
+
+	alice := makeTestUser(s, "Alice", "zone-a")
+	dave := makeTestUser(s, "Dave", "zone-a")
+	makeTestUser(s, "Bob", "zone-a")
+	makeTestUser(s, "Carol", "zone-a")
+
+	// Whoever tops the first feed has now been exposed once and should be
+	// demoted below the unexposed peer in the next feeds.
+	first, _, err := fs.GetFeed(alice.ID, FeedOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, _, err := fs.GetFeed(dave.ID, FeedOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if second[0].ID == first[0].ID {
+		t.Errorf("expected %s to be demoted after topping a feed", first[0].Name)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Concurrency tests
+// ---------------------------------------------------------------------------
+
+// TestGetFeed_ConsistentWithConcurrentSwipes interleaves swipes and feed
+// fetches for the same user. Once ProcessSwipe has returned, no later feed
+// may contain the swiped user. Run with `go test -race` to also check for
+// data races.
+func TestGetFeed_ConsistentWithConcurrentSwipes(t *testing.T) {
+	fs, s := setupFeedTest(t)
+	ss := NewSwipeService(s)
+
+	alice := makeTestUser(s, "Alice", "zone-a")
+	candidates := make([]models.User, 50)
+	for i := range candidates {
+		candidates[i] = makeTestUser(s, "Candidate", "zone-a")
+	}
+
+	// completed counts swipes that have fully returned. Candidates are
+	// swiped in slice order, so candidates[:completed] must never appear.
+	var completed atomic.Int64
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for _, c := range candidates {
+			if _, err := ss.ProcessSwipe(alice.ID, c.ID, models.SwipeActionPass); err != nil {
+				t.Errorf("unexpected swipe error: %v", err)
+				return
+			}
+			completed.Add(1)
+		}
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for completed.Load() < int64(len(candidates)) {
+			done := completed.Load()
+			feed, _, err := fs.GetFeed(alice.ID, FeedOptions{})
+			if err != nil {
+				t.Errorf("unexpected feed error: %v", err)
+				return
+			}
+
+			inFeed := make(map[uuid.UUID]struct{}, len(feed))
+			for _, u := range feed {
+				inFeed[u.ID] = struct{}{}
+			}
+			for _, c := range candidates[:done] {
+				if _, ok := inFeed[c.ID]; ok {
+					t.Errorf("feed contains %s after its swipe completed", c.ID)
+					return
+				}
+			}
+		}
+	}()
+
+	wg.Wait()
+}
+
+func TestGetFeedPage_VisitsEveryCandidateOnce(t *testing.T) {
+	fs, s := setupFeedTest(t)
+
+	alice := makeTestUser(s, "Alice", "zone-a")
+	want := make(map[uuid.UUID]bool)
+	for i := 0; i < 10; i++ {
+		want[makeTestUser(s, "Candidate", "zone-a").ID] = true
+	}
+
+	seen := make(map[uuid.UUID]int)
+	cursor := ""
+	for pages := 0; ; pages++ {
+		if pages > 10 {
+			t.Fatal("paging did not terminate")
+		}
+		page, err := fs.GetFeedPage(alice.ID, FeedOptions{}, cursor, 3)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, entry := range page.Entries {
+			seen[entry.ID]++
+		}
+
+		// After the first page, a new user joins with the highest possible
+		// ID, i.e. after the cursor. They should show up exactly once.
+		if pages == 0 {
+			late := models.User{
+				ID: uuid.MustParse("ffffffff-ffff-ffff-ffff-ffffffffffff"), Name: "Late",
+				Age: 25, Gender: "other", ZoneID: "zone-a",
+			}
+			s.AddUser(late)
+			want[late.ID] = true
+		}
+
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+
+	for id := range want {
+		if seen[id] != 1 {
+			t.Errorf("candidate %s visited %d times, want 1", id, seen[id])
+		}
+	}
+	if len(seen) != len(want) {
+		t.Errorf("visited %d candidates, want %d", len(seen), len(want))
+	}
+}
+
+func TestGetFeedPage_LastPageHasNoCursor(t *testing.T) {
+	fs, s := setupFeedTest(t)
+
+	alice := makeTestUser(s, "Alice", "zone-a")
+	makeTestUser(s, "Bob", "zone-a")
+	makeTestUser(s, "Charlie", "zone-a")
+
+	page, err := fs.GetFeedPage(alice.ID, FeedOptions{}, "", 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(page.Entries) != 2 {
+		t.Errorf("expected 2 entries, got %d", len(page.Entries))
+	}
+	if page.NextCursor != "" {
+		t.Errorf("expected no next cursor, got %q", page.NextCursor)
+	}
+}
+
+func TestGetFeedPage_RejectsInvalidCursor(t *testing.T) {
+	fs, s := setupFeedTest(t)
+	alice := makeTestUser(s, "Alice", "zone-a")
+
+	for _, cursor := range []string{"not-base64!", "bm9wZQ", encodeFeedCursor(uuid.New())[:5]} {
+		_, err := fs.GetFeedPage(alice.ID, FeedOptions{}, cursor, 5)
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Errorf("cursor %q: expected ValidationError, got %v", cursor, err)
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Interest-overlap ordering tests
+// ---------------------------------------------------------------------------
+
+// makeTestUserWithTags creates and stores a zone-a user with the given tags.
+func makeTestUserWithTags(s *store.InMemoryStore, name string, tags ...string) models.User {
+	user := makeTestUser(s, name, "zone-a")
+	user.Tags = tags
+	s.UpdateUser(user)
+	return user
+}
+
+func TestTagOverlap(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []string
+		want float64
+	}{
+		{"both empty", nil, nil, 0},
+		{"one empty", []string{"hiking"}, nil, 0},
+		{"disjoint", []string{"hiking"}, []string{"chess"}, 0},
+		{"identical", []string{"hiking", "music"}, []string{"music", "hiking"}, 1},
+		{"half shared", []string{"hiking", "music"}, []string{"hiking", "chess"}, 1.0 / 3},
+		{"duplicates ignored", []string{"hiking"}, []string{"hiking", "hiking"}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tagOverlap(tt.a, tt.b); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGetFeed_SortByInterests(t *testing.T) {
+	fs, s := setupFeedTest(t)
+
+	alice := makeTestUserWithTags(s, "Alice", "hiking", "music", "cooking")
+	none := makeTestUserWithTags(s, "None", "chess")
+	all := makeTestUserWithTags(s, "All", "hiking", "music", "cooking")
+	one := makeTestUserWithTags(s, "One", "hiking", "chess", "golf")
+	two := makeTestUserWithTags(s, "Two", "hiking", "music")
+
+	feed, _, err := fs.GetFeed(alice.ID, FeedOptions{Sort: FeedSortInterests})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Overlaps with Alice: All 3/3, Two 2/3, One 1/5, None 0/4.
+	want := []uuid.UUID{all.ID, two.ID, one.ID, none.ID}
+	if len(feed) != len(want) {
+		t.Fatalf("expected %d entries, got %d", len(want), len(feed))
+	}
+	for i, id := range want {
+		if feed[i].ID != id {
+			t.Errorf("position %d: expected %s, got %s", i, id, feed[i].Name)
+		}
+	}
+}
+
+func TestGetFeed_SortByInterestsWithoutTagsUsesIDOrder(t *testing.T) {
+	fs, s := setupFeedTest(t)
+
+	alice := makeTestUser(s, "Alice", "zone-a")
+	for _, name := range []string{"Bob", "Charlie", "Dana"} {
+		makeTestUser(s, name, "zone-a")
+	}
+
+	feed, _, err := fs.GetFeed(alice.ID, FeedOptions{Sort: FeedSortInterests})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := 1; i < len(feed); i++ {
+		if bytes.Compare(feed[i-1].ID[:], feed[i].ID[:]) > 0 {
+			t.Fatalf("expected ties to be ordered by ID, got %s before %s", feed[i-1].ID, feed[i].ID)
+		}
+	}
+}
+
+func TestGetFeed_FreshnessWeight(t *testing.T) {
+	fs, s := setupFeedTest(t)
+
+	alice := makeTestUser(s, "Alice", "zone-a")
+	var users []models.User
+	for _, name := range []string{"B", "C", "D", "E", "F"} {
+		users = append(users, makeTestUser(s, name, "zone-a"))
+	}
+	// Sort by interests so the base order is fixed (no tags, so ID order).
+	sort.Slice(users, func(i, j int) bool {
+		return bytes.Compare(users[i].ID[:], users[j].ID[:]) < 0
+	})
+
+	// The last user in ID order joined most recently; the rest joined in
+	// ID order, oldest last, so recency order is u4, u0, u1, u2, u3.
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	joined := []int{4, 3, 2, 1, 5} // days after base
+	for i, user := range users {
+		user.JoinedAt = base.Add(time.Duration(joined[i]) * 24 * time.Hour)
+		s.UpdateUser(user)
+		users[i] = user
+	}
+
+	// order returns the feed as indexes into users.
+	order := func(weight float64) []int {
+		t.Helper()
+		feed, _, err := fs.GetFeed(alice.ID, FeedOptions{Sort: FeedSortInterests, FreshnessWeight: weight})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got := make([]int, len(feed))
+		for i, entry := range feed {
+			got[i] = slices.IndexFunc(users, func(u models.User) bool { return u.ID == entry.ID })
+		}
+		return got
+	}
+
+	tests := []struct {
+		weight float64
+		want   []int
+	}{
+		{0, []int{0, 1, 2, 3, 4}},   // the default order
+		{0.5, []int{0, 1, 4, 2, 3}}, // the newest moves part way up
+		{1, []int{4, 0, 1, 2, 3}},   // newest first
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("weight %v", tt.weight), func(t *testing.T) {
+			if got := order(tt.weight); !slices.Equal(got, tt.want) {
+				t.Errorf("order: got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetFeed_SortOrders(t *testing.T) {
+	fs, s := setupFeedTest(t)
+
+	alice := makeTestUserAt(s, "Alice", "zone-a", 51.5074, -0.1278)
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	// Each candidate differs in age, join date, and distance from Alice.
+	// Dee has no coordinates, so she has no distance.
+	candidates := []struct {
+		name        string
+		age         int
+		joinDays    int
+		lat         float64
+		activeHours int
+	}{
+		{"Ann", 30, 2, 51.60, 3},
+		{"Bea", 22, 5, 51.52, 10},
+		{"Cat", 41, 1, 51.90, 1},
+		{"Dee", 35, 4, 0, 5},
+	}
+	for _, c := range candidates {
+		var user models.User
+		if c.lat != 0 {
+			user = makeTestUserAt(s, c.name, "zone-a", c.lat, -0.1278)
+		} else {
+			user = makeTestUser(s, c.name, "zone-a")
+		}
+		user.Age = c.age
+		user.JoinedAt = base.Add(time.Duration(c.joinDays) * 24 * time.Hour)
+		user.LastActiveAt = base.Add(10 * 24 * time.Hour).Add(-time.Duration(c.activeHours) * time.Hour)
+		s.UpdateUser(user)
+	}
+
+	tests := []struct {
+		sort FeedSort
+		want []string
+	}{
+		{FeedSortNewest, []string{"Bea", "Dee", "Ann", "Cat"}},
+		{FeedSortAgeAsc, []string{"Bea", "Ann", "Dee", "Cat"}},
+		{FeedSortAgeDesc, []string{"Cat", "Dee", "Ann", "Bea"}},
+		{FeedSortDistance, []string{"Bea", "Ann", "Cat", "Dee"}},
+		{FeedSortRecentActivity, []string{"Cat", "Ann", "Dee", "Bea"}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.sort), func(t *testing.T) {
+			feed, _, err := fs.GetFeed(alice.ID, FeedOptions{Sort: tt.sort})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := feedNames(feed); !slices.Equal(got, tt.want) {
+				t.Errorf("order: got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("default", func(t *testing.T) {
+		feed, _, err := fs.GetFeed(alice.ID, FeedOptions{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for i := 1; i < len(feed); i++ {
+			if bytes.Compare(feed[i-1].ID[:], feed[i].ID[:]) > 0 {
+				t.Fatalf("expected ID order, got %s before %s", feed[i-1].ID, feed[i].ID)
+			}
+		}
+	})
+}
+
+func TestGetFeed_SortLeastLiked(t *testing.T) {
+	fs, s := setupFeedTest(t)
+
+	alice := makeTestUser(s, "Alice", "zone-a")
+	popular := makeTestUser(s, "Popular", "zone-a")
+	rare := makeTestUser(s, "Rare", "zone-a")
+
+	// The likers live in another zone so they stay out of Alice's feed.
+	for i := 0; i < 3; i++ {
+		liker := makeTestUser(s, fmt.Sprintf("Liker%d", i), "zone-b")
+		s.AddSwipe(models.Swipe{SwiperID: liker.ID, SwipedID: popular.ID, Action: models.SwipeActionLike, Timestamp: time.Now()})
+		if i == 0 {
+			s.AddSwipe(models.Swipe{SwiperID: liker.ID, SwipedID: rare.ID, Action: models.SwipeActionLike, Timestamp: time.Now()})
+		} else {
+			// PASSes are not likes and must not count against Rare.
+			s.AddSwipe(models.Swipe{SwiperID: liker.ID, SwipedID: rare.ID, Action: models.SwipeActionPass, Timestamp: time.Now()})
+		}
+	}
+
+	feed, _, err := fs.GetFeed(alice.ID, FeedOptions{Sort: FeedSortLeastLiked})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, want := feedNames(feed), []string{"Rare", "Popular"}; !slices.Equal(got, want) {
+		t.Errorf("order: got %v, want %v", got, want)
+	}
+}
+
+func TestFeedSort_IsValid(t *testing.T) {
+	for _, name := range append(FeedSortNames(), "") {
+		if !FeedSort(name).IsValid() {
+			t.Errorf("expected %q to be valid", name)
+		}
+	}
+	if FeedSort("popularity").IsValid() {
+		t.Error("expected an unknown sort to be invalid")
+	}
+}
+
+func TestGetFeedPage_RejectsNonDefaultSort(t *testing.T) {
+	fs, s := setupFeedTest(t)
+	alice := makeTestUser(s, "Alice", "zone-a")
+
+	_, err := fs.GetFeedPage(alice.ID, FeedOptions{Sort: FeedSortInterests}, "", 5)
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Errorf("expected ValidationError, got %v", err)
+	}
+}
+
+func TestGetFeed_RequireTags(t *testing.T) {
+	fs, s := setupFeedTest(t)
+
+	alice := makeTestUser(s, "Alice", "zone-a")
+	both := makeTestUserWithTags(s, "Both", "hiking", "music")
+	makeTestUserWithTags(s, "HikingOnly", "hiking")
+	makeTestUser(s, "NoTags", "zone-a")
+
+	feed, _, err := fs.GetFeed(alice.ID, FeedOptions{RequireTags: []string{"music", "hiking"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(feed) != 1 || feed[0].ID != both.ID {
+		t.Errorf("expected only Both, got %+v", feed)
+	}
+
+	feed, _, err = fs.GetFeed(alice.ID, FeedOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(feed) != 3 {
+		t.Errorf("expected no tag filtering by default, got %d entries", len(feed))
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Offset/limit paging tests
+// ---------------------------------------------------------------------------
+
+func TestGetFeed_OffsetAndLimit(t *testing.T) {
+	fs, s := setupFeedTest(t)
+
+	alice := makeTestUser(s, "Alice", "zone-a")
+	for _, name := range []string{"B", "C", "D", "E", "F"} {
+		makeTestUser(s, name, "zone-a")
+	}
+
+	// Sort by interests so the order is fixed (no tags, so ID order) and
+	// pages can be compared against the full feed.
+	full, total, err := fs.GetFeed(alice.ID, FeedOptions{Sort: FeedSortInterests})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if total != 5 || len(full) != 5 {
+		t.Fatalf("expected 5 candidates, got %d (total %d)", len(full), total)
+	}
+
+	tests := []struct {
+		name          string
+		offset, limit int
+		wantStart     int
+		wantLen       int
+	}{
+		{"first page", 0, 2, 0, 2},
+		{"middle page", 2, 2, 2, 2},
+		{"short last page", 4, 2, 4, 1},
+		{"offset past the end", 10, 2, 5, 0},
+		{"no limit", 1, 0, 1, 4},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			page, total, err := fs.GetFeed(alice.ID, FeedOptions{Sort: FeedSortInterests, Offset: tc.offset, Limit: tc.limit})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if total != 5 {
+				t.Errorf("expected total 5, got %d", total)
+			}
+			if page == nil || len(page) != tc.wantLen {
+				t.Fatalf("expected %d entries, got %d", tc.wantLen, len(page))
+			}
+			for i, entry := range page {
+				if entry.ID != full[tc.wantStart+i].ID {
+					t.Errorf("entry %d: expected %s, got %s", i, full[tc.wantStart+i].Name, entry.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestGetFeed_LaterPagesRecordNoExposure(t *testing.T) {
+	fs, s := setupFeedTest(t)
+	// Exposures are only recorded while demotion is on.
+	fs.SetExposureDemotion(100)
+
+	alice := makeTestUser(s, "Alice", "zone-a")
+	makeTestUser(s, "Bob", "zone-a")
+	makeTestUser(s, "Charlie", "zone-a")
+
+	if _, _, err := fs.GetFeed(alice.ID, FeedOptions{Offset: 1, Limit: 1}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if counts := s.GetExposureCounts(); len(counts) != 0 {
+		t.Errorf("expected no exposures from a later page, got %v", counts)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Feed count tests
+// ---------------------------------------------------------------------------
+
+func TestCountFeed_MatchesGetFeed(t *testing.T) {
+	fs, s := setupFeedTest(t)
+
+	alice := makeTestUserWithGender(s, "Alice", "female", "male")
+	bob := makeTestUserWithGender(s, "Bob", "male", "")
+	makeTestUserWithGender(s, "Carl", "male", "")
+	makeTestUserWithGender(s, "Dave", "male", "")
+	makeTestUserWithGender(s, "Erin", "female", "") // filtered by preference
+	makeTestUser(s, "Faraway", "zone-b")
+	tagged := makeTestUserWithGender(s, "Tagged", "male", "")
+	tagged.Tags = []string{"hiking"}
+	s.UpdateUser(tagged)
+	s.AddSwipe(models.Swipe{SwiperID: alice.ID, SwipedID: bob.ID, Action: models.SwipeActionPass, Timestamp: time.Now()})
+
+	tests := []struct {
+		name string
+		opts FeedOptions
+	}{
+		{"defaults", FeedOptions{}},
+		{"exclude", FeedOptions{Exclude: []uuid.UUID{tagged.ID}}},
+		{"require tags", FeedOptions{RequireTags: []string{"hiking"}}},
+		{"online only", FeedOptions{OnlineOnly: true}},
+		{"include self", FeedOptions{IncludeSelf: true}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			feed, _, err := fs.GetFeed(alice.ID, tc.opts)
+			if err != nil {
+				t.Fatalf("GetFeed: %v", err)
+			}
+			count, err := fs.CountFeed(alice.ID, tc.opts)
+			if err != nil {
+				t.Fatalf("CountFeed: %v", err)
+			}
+			if count.Total != len(feed) {
+				t.Errorf("expected count %d to match the feed, got %d", len(feed), count.Total)
+			}
+		})
+	}
+}
+
+func TestCountFeed_StageBreakdown(t *testing.T) {
+	fs, s := setupFeedTest(t)
+	// Exposures are only recorded while demotion is on.
+	fs.SetExposureDemotion(100)
+
+	alice := makeTestUserWithGender(s, "Alice", "female", "male")
+	bob := makeTestUserWithGender(s, "Bob", "male", "")
+	makeTestUserWithGender(s, "Carl", "male", "")
+	makeTestUserWithGender(s, "Erin", "female", "")
+	makeTestUser(s, "Faraway", "zone-b")
+	s.AddSwipe(models.Swipe{SwiperID: alice.ID, SwipedID: bob.ID, Action: models.SwipeActionLike, Timestamp: time.Now()})
+
+	count, err := fs.CountFeed(alice.ID, FeedOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// 5 users: Faraway fails zone; Erin and Alice herself fail preferences
+	// (Alice only wants men), so nobody is left for the self stage to
+	// remove; Bob is seen; Carl remains.
+	want := map[FeedStage]int{
+		FeedStageZone: 4, FeedStageDistance: 4, FeedStagePreferences: 2, FeedStageSelf: 2, FeedStageSeen: 1,
+		FeedStageBlocked: 1, FeedStageExclude: 1, FeedStageOnline: 1, FeedStageTags: 1,
+	}
+	if len(count.Stages) != len(feedStages) {
+		t.Fatalf("expected %d stages, got %d", len(feedStages), len(count.Stages))
+	}
+	for i, stage := range count.Stages {
+		if stage.Stage != feedStages[i] {
+			t.Errorf("stage %d: expected %s, got %s", i, feedStages[i], stage.Stage)
+		}
+		if stage.Remaining != want[stage.Stage] {
+			t.Errorf("%s: expected %d remaining, got %d", stage.Stage, want[stage.Stage], stage.Remaining)
+		}
+	}
+	if count.Total != 1 {
+		t.Errorf("expected total 1, got %d", count.Total)
+	}
+
+	// Counting must not record exposure.
+	if counts := s.GetExposureCounts(); len(counts) != 0 {
+		t.Errorf("expected no exposures, got %v", counts)
+	}
+}
+
+func TestCountFeed_UserNotFound(t *testing.T) {
+	fs, _ := setupFeedTest(t)
+
+	_, err := fs.CountFeed(uuid.New(), FeedOptions{})
+	var notFoundErr *NotFoundError
+	if !errors.As(err, &notFoundErr) {
+		t.Errorf("expected NotFoundError, got %v", err)
+	}
+}
+
+func TestGetFeed_MaxFeedCandidates(t *testing.T) {
+	fs, s := setupFeedTest(t)
+	fs.SetMaxFeedCandidates(5)
+
+	alice := makeTestUser(s, "Alice", "zone-a")
+	for i := 0; i < 50; i++ {
+		makeTestUser(s, fmt.Sprintf("User%d", i), "zone-a")
+	}
+
+	// The cap bounds the whole feed, so every page and the total stay
+	// within it, whatever the page size.
+	for _, opts := range []FeedOptions{{}, {Limit: 20}, {Limit: 3, Offset: 3}} {
+		feed, total, err := fs.GetFeed(alice.ID, opts)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if total != 5 {
+			t.Errorf("%+v: expected a total of 5, got %d", opts, total)
+		}
+		if len(feed) > 5 {
+			t.Errorf("%+v: expected at most 5 entries, got %d", opts, len(feed))
+		}
+	}
+
+	// Counting isn't capped.
+	count, err := fs.CountFeed(alice.ID, FeedOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if count.Total != 50 {
+		t.Errorf("expected CountFeed to see all 50 candidates, got %d", count.Total)
+	}
+
+	// Zero removes the cap.
+	fs.SetMaxFeedCandidates(0)
+	if _, total, _ := fs.GetFeed(alice.ID, FeedOptions{}); total != 50 {
+		t.Errorf("expected all 50 candidates without a cap, got %d", total)
+	}
+}
+
+func TestGetFeed_MaxFeedCandidatesIsDeterministic(t *testing.T) {
+	fs, s := setupFeedTest(t)
+	fs.SetMaxFeedCandidates(5)
+
+	alice := makeTestUser(s, "Alice", "zone-a")
+	for i := 0; i < 50; i++ {
+		makeTestUser(s, fmt.Sprintf("User%d", i), "zone-a")
+	}
+	// A boosted candidate is always among the ones kept.
+	boosted := makeTestUser(s, "Boosted", "zone-a")
+	until := time.Now().Add(time.Hour)
+	boosted.BoostUntil = &until
+	s.UpdateUser(boosted)
+
+	first, _, err := fs.GetFeed(alice.ID, FeedOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Contains(feedNames(first), "Boosted") {
+		t.Errorf("expected the boosted user in the capped feed, got %v", feedNames(first))
+	}
+	// Map order varies between calls, so repeat enough to catch a random
+	// subset.
+	for i := 0; i < 10; i++ {
+		again, _, err := fs.GetFeed(alice.ID, FeedOptions{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !slices.Equal(feedNames(again), feedNames(first)) {
+			t.Fatalf("capped feed changed between requests: %v then %v", feedNames(first), feedNames(again))
+		}
+	}
+}
+
+func TestGetFeed_PassExpiry(t *testing.T) {
+	fs, s := setupFeedTest(t)
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	fs.SetClock(func() time.Time { return now })
+
+	alice := makeTestUser(s, "Alice", "zone-a")
+	swipe := func(target models.User, action models.SwipeAction, ago time.Duration) {
+		s.AddSwipe(models.Swipe{SwiperID: alice.ID, SwipedID: target.ID, Action: action, Timestamp: now.Add(-ago)})
+	}
+	bob := makeTestUser(s, "Bob", "zone-a")
+	carol := makeTestUser(s, "Carol", "zone-a")
+	dave := makeTestUser(s, "Dave", "zone-a")
+	erin := makeTestUser(s, "Erin", "zone-a")
+	swipe(bob, models.SwipeActionPass, time.Hour)       // Recent PASS.
+	swipe(carol, models.SwipeActionPass, 48*time.Hour)  // Expired PASS.
+	swipe(dave, models.SwipeActionLike, 48*time.Hour)   // LIKEs never expire.
+	swipe(erin, models.SwipeActionPass, 48*time.Hour)   // Expired PASS...
+	swipe(erin, models.SwipeActionPass, 30*time.Minute) // ...then passed again.
+
+	tests := []struct {
+		name   string
+		expiry time.Duration
+		want   []string
+	}{
+		{"no expiry by default", 0, []string{}},
+		{"24h expiry", 24 * time.Hour, []string{"Carol"}},
+		{"30m expiry", 30 * time.Minute, []string{"Bob", "Carol"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs.SetPassExpiry(tt.expiry)
+			feed, _, err := fs.GetFeed(alice.ID, FeedOptions{})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			names := feedNames(feed)
+			sort.Strings(names)
+			if !slices.Equal(names, tt.want) {
+				t.Errorf("feed: got %v, want %v", names, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetFeed_UpdatesRequesterActivity(t *testing.T) {
+	fs, s := setupFeedTest(t)
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	fs.SetClock(func() time.Time { return now })
+
+	alice := makeTestUser(s, "Alice", "zone-a")
+	bob := makeTestUser(s, "Bob", "zone-a")
+
+	if _, _, err := fs.GetFeed(alice.ID, FeedOptions{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, _ := s.GetUser(alice.ID); !got.LastActiveAt.Equal(now) {
+		t.Errorf("Alice's LastActiveAt: got %v, want %v", got.LastActiveAt, now)
+	}
+	// Appearing in someone's feed isn't activity.
+	if got, _ := s.GetUser(bob.ID); !got.LastActiveAt.IsZero() {
+		t.Errorf("Bob's LastActiveAt should be unchanged, got %v", got.LastActiveAt)
+	}
+}
+
+func TestGetNetworkFeed(t *testing.T) {
+	fs, s := setupFeedTest(t)
+
+	// Alice is matched with Bob and Carol, who are matched with each other
+	// and with Dave, so Dave is reachable two ways. Bob is also matched with
+	// Erin, whom Alice has already swiped on, and Carol with Fred, whom
+	// Alice has blocked. Hank, matched with Dave, is three hops out.
+	alice := makeTestUser(s, "Alice", "zone-a")
+	bob := makeTestUser(s, "Bob", "zone-a")
+	carol := makeTestUser(s, "Carol", "zone-a")
+	dave := makeTestUser(s, "Dave", "zone-b") // Another zone on purpose.
+	erin := makeTestUser(s, "Erin", "zone-a")
+	fred := makeTestUser(s, "Fred", "zone-a")
+	hank := makeTestUser(s, "Hank", "zone-a")
+	makeTestUser(s, "Ivy", "zone-a") // Not connected at all.
+
+	for _, pair := range [][2]models.User{
+		{alice, bob}, {alice, carol}, {bob, carol}, {bob, dave}, {carol, dave},
+		{bob, erin}, {carol, fred}, {dave, hank},
+	} {
+		s.AddMatch(models.Match{ID: uuid.New(), User1ID: pair[0].ID, User2ID: pair[1].ID, Timestamp: time.Now()})
+	}
+	s.AddSwipe(models.Swipe{SwiperID: alice.ID, SwipedID: erin.ID, Action: models.SwipeActionPass, Timestamp: time.Now()})
+	s.AddBlock(models.Block{BlockerID: alice.ID, BlockedID: fred.ID, Timestamp: time.Now()})
+
+	feed, err := fs.GetNetworkFeed(alice.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if names := feedNames(feed); !slices.Equal(names, []string{"Dave"}) {
+		t.Errorf("expected only Dave, got %v", names)
+	}
+
+	// Once Alice swipes on Dave, her network feed is empty.
+	s.AddSwipe(models.Swipe{SwiperID: alice.ID, SwipedID: dave.ID, Action: models.SwipeActionLike, Timestamp: time.Now()})
+	feed, err = fs.GetNetworkFeed(alice.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(feed) != 0 {
+		t.Errorf("expected an empty feed, got %v", feedNames(feed))
+	}
+
+	// Seen from Hank, the network is Dave's other matches: Bob and Carol.
+	feed, err = fs.GetNetworkFeed(hank.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	names := feedNames(feed)
+	sort.Strings(names)
+	if !slices.Equal(names, []string{"Bob", "Carol"}) {
+		t.Errorf("expected Bob and Carol, got %v", names)
+	}
+}
+
+func TestGetNetworkFeed_RespectsPreferences(t *testing.T) {
+	fs, s := setupFeedTest(t)
+
+	alice := makeTestUserWithGender(s, "Alice", "female", "female")
+	bob := makeTestUserWithGender(s, "Bob", "male", models.InterestedInEveryone)
+	beth := makeTestUserWithGender(s, "Beth", "female", models.InterestedInEveryone)
+	carl := makeTestUserWithGender(s, "Carl", "male", models.InterestedInEveryone)
+	for _, other := range []models.User{beth, carl} {
+		s.AddMatch(models.Match{ID: uuid.New(), User1ID: bob.ID, User2ID: other.ID, Timestamp: time.Now()})
+	}
+	s.AddMatch(models.Match{ID: uuid.New(), User1ID: alice.ID, User2ID: bob.ID, Timestamp: time.Now()})
+
+	feed, err := fs.GetNetworkFeed(alice.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if names := feedNames(feed); !slices.Equal(names, []string{"Beth"}) {
+		t.Errorf("expected only Beth, got %v", names)
+	}
+}
+
+func TestGetNetworkFeed_UserNotFound(t *testing.T) {
+	fs, _ := setupFeedTest(t)
+
+	_, err := fs.GetNetworkFeed(uuid.New())
+	var notFoundErr *NotFoundError
+	if !errors.As(err, &notFoundErr) {
+		t.Errorf("expected NotFoundError, got %v", err)
+	}
+}
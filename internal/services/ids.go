@@ -0,0 +1,42 @@
+// This file defines IDGenerator, the source of IDs for new records. The
+// default generates random UUIDs; tests can swap in SequentialIDs so the IDs
+// they get back are predictable and can be asserted exactly.
+package services
+
+import (
+	"encoding/binary"
+	"sync/atomic"
+
+	"github.com/google/uuid"
+)
+
+// IDGenerator creates the IDs of new records. It is an interface, rather
+// than a bare func, so that a generator with state such as SequentialIDs
+// can be passed around as one value.
+type IDGenerator interface {
+	New() uuid.UUID
+}
+
+// RandomIDs generates random (version 4) UUIDs with uuid.New. It is the
+// default everywhere an IDGenerator can be set.
+type RandomIDs struct{}
+
+// New returns a new random UUID.
+func (RandomIDs) New() uuid.UUID {
+	return uuid.New()
+}
+
+// SequentialIDs generates predictable IDs for tests: the first call to New
+// returns 00000000-0000-0000-0000-000000000001, the next ...0002, and so
+// on. The zero value is ready to use, and it is safe for concurrent use.
+// The IDs aren't valid version 4 UUIDs, so don't use it in production.
+type SequentialIDs struct {
+	last atomic.Uint64
+}
+
+// New returns the next ID in the sequence.
+func (g *SequentialIDs) New() uuid.UUID {
+	var id uuid.UUID
+	binary.BigEndian.PutUint64(id[8:], g.last.Add(1))
+	return id
+}
@@ -0,0 +1,49 @@
+// This file contains tests for SequentialIDs, the predictable IDGenerator
+// used by tests elsewhere.
+package services
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestSequentialIDs(t *testing.T) {
+	var ids SequentialIDs
+	for _, want := range []string{
+		"00000000-0000-0000-0000-000000000001",
+		"00000000-0000-0000-0000-000000000002",
+		"00000000-0000-0000-0000-000000000003",
+	} {
+		if got := ids.New().String(); got != want {
+			t.Errorf("got %s, want %s", got, want)
+		}
+	}
+}
+
+func TestSequentialIDs_ConcurrentCallsAreUnique(t *testing.T) {
+	var ids SequentialIDs
+	const goroutines, perGoroutine = 8, 100
+
+	var mu sync.Mutex
+	seen := make(map[uuid.UUID]struct{})
+	var wg sync.WaitGroup
+	for range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range perGoroutine {
+				id := ids.New()
+				mu.Lock()
+				seen[id] = struct{}{}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(seen) != goroutines*perGoroutine {
+		t.Errorf("expected %d unique IDs, got %d", goroutines*perGoroutine, len(seen))
+	}
+}
@@ -0,0 +1,85 @@
+// This file implements the MatchService, which reads matches for display.
+// Matches are created by SwipeService when two users LIKE each other.
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dlfelps/tinder-go-claude/internal/models"
+	"github.com/dlfelps/tinder-go-claude/internal/store"
+	"github.com/google/uuid"
+)
+
+// MatchService handles read-side match queries.
+type MatchService struct {
+	store *store.InMemoryStore
+}
+
+// NewMatchService creates a new MatchService connected to the given store.
+func NewMatchService(s *store.InMemoryStore) *MatchService {
+	return &MatchService{store: s}
+}
+
+// GetMatchesWithProfiles returns the user's matches, oldest first, each
+// joined with the other person's profile. It returns a NotFoundError if the
+// user doesn't exist, and never returns a nil slice on success.
+//
+// All profiles are fetched with one GetUsers call rather than a GetUser per
+// match, so the store lock is taken once no matter how many matches there
+// are. A match whose other user has since disappeared is skipped, since
+// there is no profile to show.
+func (ms *MatchService) GetMatchesWithProfiles(userID uuid.UUID) ([]models.MatchWithProfile, error) {
+	if _, exists := ms.store.GetUser(userID); !exists {
+		return nil, &NotFoundError{Message: fmt.Sprintf("user %s not found", userID)}
+	}
+	return ms.withProfiles(userID, ms.store.GetMatchesForUser(userID)), nil
+}
+
+// GetMatchesWithProfilesBefore is the paged form of GetMatchesWithProfiles:
+// up to limit matches created strictly before the given time, newest first.
+// A zero before starts from the newest match. Paging by timestamp rather
+// than by offset means new matches arriving between requests can't shift
+// later pages.
+func (ms *MatchService) GetMatchesWithProfilesBefore(userID uuid.UUID, before time.Time, limit int) ([]models.MatchWithProfile, error) {
+	if _, exists := ms.store.GetUser(userID); !exists {
+		return nil, &NotFoundError{Message: fmt.Sprintf("user %s not found", userID)}
+	}
+	return ms.withProfiles(userID, ms.store.GetMatchesForUserBefore(userID, before, limit)), nil
+}
+
+// withProfiles joins matches with the profile of userID's partner in each,
+// and flags the ones userID has marked seen.
+func (ms *MatchService) withProfiles(userID uuid.UUID, matches []models.Match) []models.MatchWithProfile {
+	otherIDs := make([]uuid.UUID, len(matches))
+	for i, match := range matches {
+		otherIDs[i] = otherUser(match, userID)
+	}
+	profiles := ms.store.GetUsers(otherIDs)
+	seen := ms.store.GetSeenMatches(userID)
+
+	result := make([]models.MatchWithProfile, 0, len(matches))
+	for i, match := range matches {
+		profile, ok := profiles[otherIDs[i]]
+		if !ok {
+			continue
+		}
+		_, isSeen := seen[match.ID]
+		result = append(result, models.MatchWithProfile{
+			MatchID:   match.ID,
+			User:      profile,
+			MatchedAt: match.Timestamp,
+			Seen:      isSeen,
+		})
+	}
+	return result
+}
+
+// otherUser returns the ID of the person userID matched with, whichever
+// side of the match record they are on.
+func otherUser(match models.Match, userID uuid.UUID) uuid.UUID {
+	if match.User1ID == userID {
+		return match.User2ID
+	}
+	return match.User1ID
+}
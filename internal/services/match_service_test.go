@@ -0,0 +1,80 @@
+// This file contains unit tests for the MatchService.
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dlfelps/tinder-go-claude/internal/models"
+	"github.com/dlfelps/tinder-go-claude/internal/store"
+	"github.com/google/uuid"
+)
+
+// setupMatchTest resets the store and creates a MatchService.
+func setupMatchTest(t *testing.T) (*MatchService, *store.InMemoryStore) {
+	t.Helper()
+	s := store.GetStore()
+	s.Reset()
+	return NewMatchService(s), s
+}
+
+func TestGetMatchesWithProfiles_HydratesOtherUser(t *testing.T) {
+	ms, s := setupMatchTest(t)
+
+	alice := makeTestUser(s, "Alice", "zone-a")
+	bob := makeTestUser(s, "Bob", "zone-a")
+	carol := makeTestUser(s, "Carol", "zone-a")
+
+	first := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	// Alice is user1 in one match and user2 in the other.
+	s.AddMatch(models.Match{ID: uuid.New(), User1ID: alice.ID, User2ID: bob.ID, Timestamp: first})
+	s.AddMatch(models.Match{ID: uuid.New(), User1ID: carol.ID, User2ID: alice.ID, Timestamp: first.Add(time.Hour)})
+
+	matches, err := ms.GetMatchesWithProfiles(alice.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(matches) != 2 {
+		t.Fatalf("expected 2 matches, got %d", len(matches))
+	}
+
+	tests := []struct {
+		want      models.User
+		matchedAt time.Time
+	}{
+		{bob, first},
+		{carol, first.Add(time.Hour)},
+	}
+	for i, tc := range tests {
+		if matches[i].User.ID != tc.want.ID || matches[i].User.Name != tc.want.Name {
+			t.Errorf("match %d: expected %s, got %+v", i, tc.want.Name, matches[i].User)
+		}
+		if !matches[i].MatchedAt.Equal(tc.matchedAt) {
+			t.Errorf("match %d: expected matched_at %s, got %s", i, tc.matchedAt, matches[i].MatchedAt)
+		}
+	}
+}
+
+func TestGetMatchesWithProfiles_EmptyIsNotNil(t *testing.T) {
+	ms, s := setupMatchTest(t)
+	alice := makeTestUser(s, "Alice", "zone-a")
+
+	matches, err := ms.GetMatchesWithProfiles(alice.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if matches == nil || len(matches) != 0 {
+		t.Errorf("expected an empty, non-nil slice, got %#v", matches)
+	}
+}
+
+func TestGetMatchesWithProfiles_UnknownUser(t *testing.T) {
+	ms, _ := setupMatchTest(t)
+
+	_, err := ms.GetMatchesWithProfiles(uuid.New())
+	var notFoundErr *NotFoundError
+	if !errors.As(err, &notFoundErr) {
+		t.Errorf("expected NotFoundError, got %v", err)
+	}
+}
@@ -0,0 +1,199 @@
+// This file implements the NotificationService, which lets clients poll for
+// new matches in batches instead of receiving one event per match. Each user
+// has a cursor (the timestamp of the newest match already delivered), so a
+// poll only returns what the client hasn't seen yet.
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dlfelps/tinder-go-claude/internal/models"
+	"github.com/dlfelps/tinder-go-claude/internal/store"
+	"github.com/google/uuid"
+)
+
+// NotificationService tracks per-user delivery cursors for match events.
+type NotificationService struct {
+	store *store.InMemoryStore
+
+	// mu protects cursors. The service has its own lock because cursors
+	// live here rather than in the store.
+	mu      sync.Mutex
+	cursors map[uuid.UUID]time.Time
+
+	// now is the clock used to check users' quiet hours. Tests replace it
+	// with SetClock.
+	now func() time.Time
+}
+
+// NewNotificationService creates a new NotificationService connected to the
+// given store.
+func NewNotificationService(s *store.InMemoryStore) *NotificationService {
+	return &NotificationService{
+		store:   s,
+		cursors: make(map[uuid.UUID]time.Time),
+		now:     time.Now,
+	}
+}
+
+// SetClock replaces the service's time source, so tests can pin "now".
+func (ns *NotificationService) SetClock(now func() time.Time) {
+	ns.now = now
+}
+
+// MatchEventBatch is the result of draining a user's match events.
+type MatchEventBatch struct {
+	// Matches are the new matches, oldest first.
+	Matches []models.Match
+
+	// Cursor is the timestamp of the newest delivered match. Passing it back
+	// as `since` resumes exactly where this batch ended.
+	Cursor time.Time
+
+	// DeferredUntil is set when the user is in their quiet hours: the batch
+	// is empty, and new matches are held until this time.
+	DeferredUntil *time.Time
+}
+
+// DrainMatchEvents returns the user's matches created after the cursor and
+// advances the stored cursor past them.
+//
+// If the user has turned off match notifications, the batch is empty but the
+// cursor still advances: the matches exist, the user just isn't notified,
+// and re-enabling notifications won't replay old matches.
+//
+// During the user's quiet hours the batch is empty and the cursor does not
+// move, so the held-back matches are delivered by the first poll after the
+// window ends. DeferredUntil says when that is.
+//
+// If since is nil, the user's stored cursor is used (the zero time on the
+// first poll, which returns every match). A non-nil since overrides the
+// stored cursor, letting a client replay from an earlier point.
+func (ns *NotificationService) DrainMatchEvents(userID uuid.UUID, since *time.Time) (*MatchEventBatch, error) {
+	user, exists := ns.store.GetUser(userID)
+	if !exists {
+		return nil, &NotFoundError{Message: fmt.Sprintf("user %s not found", userID)}
+	}
+
+	// Hold the lock for the whole drain so two concurrent polls for the same
+	// user can't both deliver the same batch.
+	ns.mu.Lock()
+	defer ns.mu.Unlock()
+
+	cursor := ns.cursors[userID]
+	if since != nil {
+		cursor = *since
+	}
+
+	batch := &MatchEventBatch{Matches: []models.Match{}, Cursor: cursor}
+	if user.NotifyOnMatch && user.QuietHours != nil {
+		if until, quiet := user.QuietHours.Until(ns.now()); quiet {
+			batch.DeferredUntil = &until
+			ns.cursors[userID] = cursor
+			return batch, nil
+		}
+	}
+
+	for _, match := range ns.store.GetMatchesForUser(userID) {
+		if match.Timestamp.After(cursor) {
+			batch.Matches = append(batch.Matches, match)
+		}
+	}
+
+	sort.SliceStable(batch.Matches, func(i, j int) bool {
+		return batch.Matches[i].Timestamp.Before(batch.Matches[j].Timestamp)
+	})
+	if n := len(batch.Matches); n > 0 {
+		batch.Cursor = batch.Matches[n-1].Timestamp
+	}
+
+	if !user.NotifyOnMatch {
+		batch.Matches = []models.Match{}
+	}
+
+	ns.cursors[userID] = batch.Cursor
+	return batch, nil
+}
+
+// UserDigest summarizes what happened to one user during a digest window.
+type UserDigest struct {
+	UserID        uuid.UUID      `json:"user_id"`
+	Matches       []models.Match `json:"matches"`
+	LikesReceived []models.Swipe `json:"likes_received"`
+}
+
+// Digest groups the matches and likes created at or after since by the
+// user they should be reported to, for a periodic summary email. Each match
+// is reported to both participants; each like to the user who was liked.
+//
+// Notification preferences are honored: a user with NotifyOnMatch off gets
+// no matches in their digest, and likewise for NotifyOnLike. Users with
+// nothing to report are omitted, and the result is ordered by user ID so
+// repeated runs produce identical output.
+//
+// Unlike DrainMatchEvents, Digest keeps no state: the caller (a scheduled
+// job) decides the window each time.
+func (ns *NotificationService) Digest(since time.Time) []UserDigest {
+	byUser := make(map[uuid.UUID]*UserDigest)
+	// entry returns the digest for a user, creating it on first use.
+	entry := func(id uuid.UUID) *UserDigest {
+		d, ok := byUser[id]
+		if !ok {
+			d = &UserDigest{UserID: id, Matches: []models.Match{}, LikesReceived: []models.Swipe{}}
+			byUser[id] = d
+		}
+		return d
+	}
+
+	for _, match := range ns.store.GetAllMatches() {
+		if match.Timestamp.Before(since) {
+			continue
+		}
+		for _, id := range []uuid.UUID{match.User1ID, match.User2ID} {
+			d := entry(id)
+			d.Matches = append(d.Matches, match)
+		}
+	}
+	for _, swipe := range ns.store.GetAllSwipes() {
+		if swipe.Action != models.SwipeActionLike || swipe.Timestamp.Before(since) {
+			continue
+		}
+		d := entry(swipe.SwipedID)
+		d.LikesReceived = append(d.LikesReceived, swipe)
+	}
+
+	// Apply preferences with one batched lookup. Recipients that no longer
+	// exist are dropped, since there is nobody to mail.
+	ids := make([]uuid.UUID, 0, len(byUser))
+	for id := range byUser {
+		ids = append(ids, id)
+	}
+	users := ns.store.GetUsers(ids)
+
+	digests := make([]UserDigest, 0, len(byUser))
+	for id, d := range byUser {
+		user, ok := users[id]
+		if !ok {
+			continue
+		}
+		if !user.NotifyOnMatch {
+			d.Matches = []models.Match{}
+		}
+		if !user.NotifyOnLike {
+			d.LikesReceived = []models.Swipe{}
+		}
+		if len(d.Matches) == 0 && len(d.LikesReceived) == 0 {
+			continue
+		}
+		digests = append(digests, *d)
+	}
+
+	sort.Slice(digests, func(i, j int) bool {
+		return bytes.Compare(digests[i].UserID[:], digests[j].UserID[:]) < 0
+	})
+	return digests
+}
@@ -0,0 +1,313 @@
+// This file contains unit tests for the NotificationService's match event
+// draining and cursor handling.
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dlfelps/tinder-go-claude/internal/models"
+	"github.com/dlfelps/tinder-go-claude/internal/store"
+	"github.com/google/uuid"
+)
+
+// setupNotificationTest resets the store and creates a NotificationService.
+func setupNotificationTest(t *testing.T) (*NotificationService, *store.InMemoryStore) {
+	t.Helper()
+	s := store.GetStore()
+	s.Reset()
+	return NewNotificationService(s), s
+}
+
+func TestDrainMatchEvents_ReturnsOnlyNewMatches(t *testing.T) {
+	ns, s := setupNotificationTest(t)
+
+	alice := makeTestUser(s, "Alice", "zone-a")
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	s.AddMatch(models.Match{User1ID: alice.ID, User2ID: uuid.New(), Timestamp: base})
+	s.AddMatch(models.Match{User1ID: uuid.New(), User2ID: alice.ID, Timestamp: base.Add(time.Minute)})
+
+	// First drain delivers both matches, oldest first.
+	first, err := ns.DrainMatchEvents(alice.ID, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(first.Matches) != 2 {
+		t.Fatalf("expected 2 matches, got %d", len(first.Matches))
+	}
+	if !first.Matches[0].Timestamp.Before(first.Matches[1].Timestamp) {
+		t.Error("expected matches in chronological order")
+	}
+	if !first.Cursor.Equal(base.Add(time.Minute)) {
+		t.Errorf("cursor: got %v, want %v", first.Cursor, base.Add(time.Minute))
+	}
+
+	// Nothing new yet: the cursor has advanced past both matches.
+	second, err := ns.DrainMatchEvents(alice.ID, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(second.Matches) != 0 {
+		t.Errorf("expected 0 new matches, got %d", len(second.Matches))
+	}
+
+	// A new match arrives and is the only one delivered.
+	s.AddMatch(models.Match{User1ID: alice.ID, User2ID: uuid.New(), Timestamp: base.Add(2 * time.Minute)})
+	third, err := ns.DrainMatchEvents(alice.ID, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(third.Matches) != 1 {
+		t.Errorf("expected 1 new match, got %d", len(third.Matches))
+	}
+}
+
+func TestDrainMatchEvents_SinceOverridesCursor(t *testing.T) {
+	ns, s := setupNotificationTest(t)
+
+	alice := makeTestUser(s, "Alice", "zone-a")
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	s.AddMatch(models.Match{User1ID: alice.ID, User2ID: uuid.New(), Timestamp: base})
+	s.AddMatch(models.Match{User1ID: alice.ID, User2ID: uuid.New(), Timestamp: base.Add(time.Hour)})
+
+	if _, err := ns.DrainMatchEvents(alice.ID, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Replaying from the first match's time returns only the later one.
+	since := base
+	batch, err := ns.DrainMatchEvents(alice.ID, &since)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(batch.Matches) != 1 {
+		t.Errorf("expected 1 match after since, got %d", len(batch.Matches))
+	}
+}
+
+func TestDrainMatchEvents_CursorsArePerUser(t *testing.T) {
+	ns, s := setupNotificationTest(t)
+
+	alice := makeTestUser(s, "Alice", "zone-a")
+	bob := makeTestUser(s, "Bob", "zone-a")
+	s.AddMatch(models.Match{User1ID: alice.ID, User2ID: bob.ID, Timestamp: time.Now().UTC()})
+
+	if _, err := ns.DrainMatchEvents(alice.ID, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Alice draining doesn't consume Bob's notification.
+	batch, err := ns.DrainMatchEvents(bob.ID, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(batch.Matches) != 1 {
+		t.Errorf("expected Bob to still receive 1 match, got %d", len(batch.Matches))
+	}
+}
+
+func TestDrainMatchEvents_RespectsMatchPreference(t *testing.T) {
+	ns, s := setupNotificationTest(t)
+	ss := NewSwipeService(s)
+
+	alice := makeTestUser(s, "Alice", "zone-a")
+	bob := makeTestUser(s, "Bob", "zone-a")
+
+	// Alice turns off match notifications.
+	alice.NotifyOnMatch = false
+	s.UpdateUser(alice)
+
+	if _, err := ss.ProcessSwipe(alice.ID, bob.ID, models.SwipeActionLike); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result, err := ss.ProcessSwipe(bob.ID, alice.ID, models.SwipeActionLike)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The match still forms...
+	if !result.Matched {
+		t.Fatal("expected a match regardless of notification preferences")
+	}
+
+	// ...but Alice gets no notification, while Bob still does.
+	aliceBatch, err := ns.DrainMatchEvents(alice.ID, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(aliceBatch.Matches) != 0 {
+		t.Errorf("expected no events for Alice, got %d", len(aliceBatch.Matches))
+	}
+
+	bobBatch, err := ns.DrainMatchEvents(bob.ID, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(bobBatch.Matches) != 1 {
+		t.Errorf("expected 1 event for Bob, got %d", len(bobBatch.Matches))
+	}
+}
+
+func TestDrainMatchEvents_UserNotFound(t *testing.T) {
+	ns, _ := setupNotificationTest(t)
+
+	_, err := ns.DrainMatchEvents(uuid.New(), nil)
+	if _, ok := err.(*NotFoundError); !ok {
+		t.Errorf("expected NotFoundError, got %T", err)
+	}
+}
+
+// findDigest returns the digest for userID, or nil if there isn't one.
+func findDigest(digests []UserDigest, userID uuid.UUID) *UserDigest {
+	for i := range digests {
+		if digests[i].UserID == userID {
+			return &digests[i]
+		}
+	}
+	return nil
+}
+
+func TestDigest_GroupsEventsByRecipientWithinWindow(t *testing.T) {
+	ns, s := setupNotificationTest(t)
+
+	alice := makeTestUser(s, "Alice", "zone-a")
+	bob := makeTestUser(s, "Bob", "zone-a")
+	carol := makeTestUser(s, "Carol", "zone-a")
+
+	since := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	before := since.Add(-time.Hour)
+	after := since.Add(time.Hour)
+
+	// Inside the window: Bob likes Alice, Carol likes Alice, and Alice and
+	// Bob match.
+	s.AddSwipe(models.Swipe{SwiperID: bob.ID, SwipedID: alice.ID, Action: models.SwipeActionLike, Timestamp: after})
+	s.AddSwipe(models.Swipe{SwiperID: carol.ID, SwipedID: alice.ID, Action: models.SwipeActionLike, Timestamp: since})
+	s.AddMatch(models.Match{User1ID: alice.ID, User2ID: bob.ID, Timestamp: after})
+
+	// Outside the window or not a like: none of these are reported.
+	s.AddSwipe(models.Swipe{SwiperID: alice.ID, SwipedID: carol.ID, Action: models.SwipeActionLike, Timestamp: before})
+	s.AddSwipe(models.Swipe{SwiperID: alice.ID, SwipedID: bob.ID, Action: models.SwipeActionPass, Timestamp: after})
+	s.AddMatch(models.Match{User1ID: alice.ID, User2ID: carol.ID, Timestamp: before})
+
+	digests := ns.Digest(since)
+	if len(digests) != 2 {
+		t.Fatalf("expected digests for 2 users, got %d", len(digests))
+	}
+
+	aliceDigest := findDigest(digests, alice.ID)
+	if aliceDigest == nil {
+		t.Fatal("expected a digest for Alice")
+	}
+	if len(aliceDigest.LikesReceived) != 2 {
+		t.Errorf("Alice: expected 2 likes, got %d", len(aliceDigest.LikesReceived))
+	}
+	if len(aliceDigest.Matches) != 1 {
+		t.Errorf("Alice: expected 1 match, got %d", len(aliceDigest.Matches))
+	}
+
+	bobDigest := findDigest(digests, bob.ID)
+	if bobDigest == nil {
+		t.Fatal("expected a digest for Bob")
+	}
+	if len(bobDigest.Matches) != 1 || len(bobDigest.LikesReceived) != 0 {
+		t.Errorf("Bob: got %d matches and %d likes, want 1 and 0", len(bobDigest.Matches), len(bobDigest.LikesReceived))
+	}
+
+	if findDigest(digests, carol.ID) != nil {
+		t.Error("Carol had no events in the window and should be omitted")
+	}
+}
+
+func TestDigest_HonorsNotificationPreferences(t *testing.T) {
+	ns, s := setupNotificationTest(t)
+
+	alice := makeTestUser(s, "Alice", "zone-a")
+	bob := makeTestUser(s, "Bob", "zone-a")
+	alice.NotifyOnLike = false
+	s.UpdateUser(alice)
+
+	now := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	s.AddSwipe(models.Swipe{SwiperID: bob.ID, SwipedID: alice.ID, Action: models.SwipeActionLike, Timestamp: now})
+
+	if digests := ns.Digest(now.Add(-time.Hour)); len(digests) != 0 {
+		t.Errorf("expected no digests when likes are muted, got %d", len(digests))
+	}
+}
+
+func TestDrainMatchEvents_QuietHoursDeferDelivery(t *testing.T) {
+	ns, s := setupNotificationTest(t)
+
+	// Alice's quiet hours are 22:00–07:00 New York time, which is
+	// 02:00–11:00 UTC in June (daylight saving time, UTC-4).
+	alice := makeTestUser(s, "Alice", "zone-a")
+	alice.QuietHours = &models.QuietHours{Start: "22:00", End: "07:00", Timezone: "America/New_York"}
+	s.UpdateUser(alice)
+
+	// 23:30 in New York: inside the window.
+	now := time.Date(2024, 6, 2, 3, 30, 0, 0, time.UTC)
+	ns.SetClock(func() time.Time { return now })
+	s.AddMatch(models.Match{User1ID: alice.ID, User2ID: uuid.New(), Timestamp: now})
+
+	batch, err := ns.DrainMatchEvents(alice.ID, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(batch.Matches) != 0 {
+		t.Errorf("expected the match to be held back, got %d", len(batch.Matches))
+	}
+	wantUntil := time.Date(2024, 6, 2, 11, 0, 0, 0, time.UTC) // 07:00 New York.
+	if batch.DeferredUntil == nil || !batch.DeferredUntil.Equal(wantUntil) {
+		t.Fatalf("deferred until: got %v, want %v", batch.DeferredUntil, wantUntil)
+	}
+
+	// Just before the window ends, still nothing.
+	now = wantUntil.Add(-time.Minute)
+	if batch, _ := ns.DrainMatchEvents(alice.ID, nil); len(batch.Matches) != 0 {
+		t.Errorf("expected nothing one minute before the window ends, got %d", len(batch.Matches))
+	}
+
+	// Once the window ends, the held-back match is delivered.
+	now = wantUntil
+	batch, err = ns.DrainMatchEvents(alice.ID, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(batch.Matches) != 1 {
+		t.Fatalf("expected the deferred match after the window, got %d", len(batch.Matches))
+	}
+	if batch.DeferredUntil != nil {
+		t.Errorf("expected no deferral outside quiet hours, got %v", batch.DeferredUntil)
+	}
+}
+
+func TestQuietHours_Until(t *testing.T) {
+	day := func(h, m int) time.Time { return time.Date(2024, 1, 15, h, m, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name      string
+		quiet     models.QuietHours
+		at        time.Time
+		wantQuiet bool
+		wantUntil time.Time
+	}{
+		{"same-day window, inside", models.QuietHours{Start: "13:00", End: "15:00"}, day(14, 0), true, day(15, 0)},
+		{"same-day window, start is inclusive", models.QuietHours{Start: "13:00", End: "15:00"}, day(13, 0), true, day(15, 0)},
+		{"same-day window, end is exclusive", models.QuietHours{Start: "13:00", End: "15:00"}, day(15, 0), false, time.Time{}},
+		{"wrapping window, evening", models.QuietHours{Start: "22:00", End: "07:00"}, day(23, 0), true, day(31, 0)},
+		{"wrapping window, morning", models.QuietHours{Start: "22:00", End: "07:00"}, day(6, 59), true, day(7, 0)},
+		{"wrapping window, daytime", models.QuietHours{Start: "22:00", End: "07:00"}, day(12, 0), false, time.Time{}},
+		// 09:00 UTC is 18:00 in Tokyo (UTC+9), inside 17:00–19:00 local.
+		{"user's time zone", models.QuietHours{Start: "17:00", End: "19:00", Timezone: "Asia/Tokyo"}, day(9, 0), true, day(10, 0)},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			until, quiet := tc.quiet.Until(tc.at)
+			if quiet != tc.wantQuiet {
+				t.Fatalf("quiet: got %v, want %v", quiet, tc.wantQuiet)
+			}
+			if quiet && !until.Equal(tc.wantUntil) {
+				t.Errorf("until: got %v, want %v", until, tc.wantUntil)
+			}
+		})
+	}
+}
@@ -0,0 +1,209 @@
+// This file implements the SimulationService, which generates synthetic
+// users and swipes for load testing and demo data for development. It
+// deliberately goes through the SwipeService so generated swipes exercise
+// the same code paths as real traffic (validation, recording, and match
+// detection), minus the daily swipe limit.
+package services
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/dlfelps/tinder-go-claude/internal/models"
+	"github.com/dlfelps/tinder-go-claude/internal/store"
+	"github.com/google/uuid"
+)
+
+// Limits on the size of a single simulation run. They keep an accidental
+// request from exhausting the server's memory.
+const (
+	MaxSimulatedUsers  = 10000
+	MaxSimulatedSwipes = 100000
+)
+
+// simulationZone is the zone assigned to every generated user.
+const simulationZone = "simulation"
+
+// SimulationService generates synthetic load against the store.
+type SimulationService struct {
+	store        *store.InMemoryStore
+	swipeService *SwipeService
+}
+
+// NewSimulationService creates a new SimulationService. It takes the
+// SwipeService as a dependency so swipes follow the real processing path.
+func NewSimulationService(s *store.InMemoryStore, ss *SwipeService) *SimulationService {
+	return &SimulationService{store: s, swipeService: ss}
+}
+
+// SimulationResult summarizes a simulation run. The struct tags let the
+// handler return it directly as JSON.
+type SimulationResult struct {
+	UsersCreated int   `json:"users_created"`
+	Swipes       int   `json:"swipes"`
+	Likes        int   `json:"likes"`
+	Passes       int   `json:"passes"`
+	Matches      int   `json:"matches"`
+	DurationMs   int64 `json:"duration_ms"`
+}
+
+// Simulate creates numUsers users and then records numSwipes random swipes
+// between them, reporting how many matches resulted and how long it took.
+//
+// At least two users are required so every swipe has a distinct target.
+func (sim *SimulationService) Simulate(numUsers, numSwipes int) (*SimulationResult, error) {
+	if numUsers < 2 || numUsers > MaxSimulatedUsers {
+		return nil, &ValidationError{Message: fmt.Sprintf("users must be between 2 and %d", MaxSimulatedUsers)}
+	}
+	if numSwipes < 0 || numSwipes > MaxSimulatedSwipes {
+		return nil, &ValidationError{Message: fmt.Sprintf("swipes must be between 0 and %d", MaxSimulatedSwipes)}
+	}
+
+	start := time.Now()
+	result := &SimulationResult{}
+
+	// Step 1: Generate the users.
+	ids := make([]uuid.UUID, numUsers)
+	for i := range ids {
+		now := time.Now().UTC()
+		user := models.User{
+			ID:            uuid.New(),
+			Name:          fmt.Sprintf("sim-user-%d", i+1),
+			Age:           18 + rand.IntN(40),
+			Gender:        string(models.GenderOther),
+			ZoneID:        simulationZone,
+			LastActiveAt:  now,
+			JoinedAt:      now,
+			NotifyOnLike:  true,
+			NotifyOnMatch: true,
+		}
+		sim.store.AddUser(user)
+		ids[i] = user.ID
+	}
+	result.UsersCreated = numUsers
+
+	// Step 2: Record random swipes between distinct users.
+	if err := sim.randomSwipes(ids, numSwipes, result); err != nil {
+		return nil, err
+	}
+
+	result.DurationMs = time.Since(start).Milliseconds()
+	return result, nil
+}
+
+// randomSwipes records numSwipes random swipes between distinct users in
+// ids, half of them LIKEs on average, and tallies them in result. ids must
+// hold at least two users.
+func (sim *SimulationService) randomSwipes(ids []uuid.UUID, numSwipes int, result *SimulationResult) error {
+	for i := 0; i < numSwipes; i++ {
+		swiper := ids[rand.IntN(len(ids))]
+		swiped := ids[rand.IntN(len(ids))]
+		for swiped == swiper {
+			swiped = ids[rand.IntN(len(ids))]
+		}
+
+		action := models.SwipeActionPass
+		if rand.IntN(2) == 0 {
+			action = models.SwipeActionLike
+		}
+
+		// The daily swipe limit is for people, not synthetic load, so
+		// generated swipes skip it.
+		swipeResult, err := sim.swipeService.processSwipe(swiper, swiped, action, false)
+		if err != nil {
+			return err
+		}
+
+		result.Swipes++
+		if action == models.SwipeActionLike {
+			result.Likes++
+		} else {
+			result.Passes++
+		}
+		if swipeResult.Matched {
+			result.Matches++
+		}
+	}
+	return nil
+}
+
+// Limits on a single seed run; see Seed.
+const (
+	MaxSeedUsers  = 1000
+	MaxSeedZones  = 50
+	MaxSeedSwipes = 10000
+)
+
+// seedGenders are the genders given to seeded users, in rotation.
+var seedGenders = []models.Gender{
+	models.GenderFemale, models.GenderMale, models.GenderNonbinary, models.GenderOther,
+}
+
+// SeedResult describes the demo data created by Seed. UserIDs lists the
+// new users in creation order; the swipe counts are as in SimulationResult.
+type SeedResult struct {
+	UserIDs []uuid.UUID `json:"user_ids"`
+	Zones   []string    `json:"zones"`
+	Swipes  int         `json:"swipes"`
+	Likes   int         `json:"likes"`
+	Passes  int         `json:"passes"`
+	Matches int         `json:"matches"`
+}
+
+// Seed creates demo data for local development: numUsers users spread
+// evenly over numZones zones named "demo-zone-1", "demo-zone-2", and so on,
+// followed by numSwipes random swipes between them. Unlike Simulate's
+// identical users, seeded users have varied genders and ages (18 to 60), so
+// that feeds and filters have something to show; they are interested in
+// everyone, so feeds aren't empty.
+//
+// Swipes need two distinct users, so numSwipes must be zero when numUsers
+// is 1.
+func (sim *SimulationService) Seed(numUsers, numZones, numSwipes int) (*SeedResult, error) {
+	if numUsers < 1 || numUsers > MaxSeedUsers {
+		return nil, &ValidationError{Message: fmt.Sprintf("users must be between 1 and %d", MaxSeedUsers)}
+	}
+	if numZones < 1 || numZones > MaxSeedZones {
+		return nil, &ValidationError{Message: fmt.Sprintf("zones must be between 1 and %d", MaxSeedZones)}
+	}
+	if numSwipes < 0 || numSwipes > MaxSeedSwipes {
+		return nil, &ValidationError{Message: fmt.Sprintf("swipes must be between 0 and %d", MaxSeedSwipes)}
+	}
+	if numSwipes > 0 && numUsers < 2 {
+		return nil, &ValidationError{Message: "at least 2 users are needed for swipes"}
+	}
+
+	result := &SeedResult{
+		UserIDs: make([]uuid.UUID, numUsers),
+		Zones:   make([]string, numZones),
+	}
+	for i := range result.Zones {
+		result.Zones[i] = fmt.Sprintf("demo-zone-%d", i+1)
+	}
+
+	for i := range result.UserIDs {
+		now := time.Now().UTC()
+		user := models.User{
+			ID:            uuid.New(),
+			Name:          fmt.Sprintf("demo-user-%d", i+1),
+			Age:           18 + rand.IntN(43),
+			Gender:        string(seedGenders[i%len(seedGenders)]),
+			InterestedIn:  models.InterestedInEveryone,
+			ZoneID:        result.Zones[i%numZones],
+			LastActiveAt:  now,
+			JoinedAt:      now,
+			NotifyOnLike:  true,
+			NotifyOnMatch: true,
+		}
+		sim.store.AddUser(user)
+		result.UserIDs[i] = user.ID
+	}
+
+	var swipes SimulationResult
+	if err := sim.randomSwipes(result.UserIDs, numSwipes, &swipes); err != nil {
+		return nil, err
+	}
+	result.Swipes, result.Likes, result.Passes, result.Matches = swipes.Swipes, swipes.Likes, swipes.Passes, swipes.Matches
+	return result, nil
+}
@@ -0,0 +1,153 @@
+// This file contains unit tests for the SimulationService.
+package services
+
+import (
+	"testing"
+
+	"github.com/dlfelps/tinder-go-claude/internal/store"
+)
+
+// setupSimulationTest resets the store and creates a SimulationService.
+func setupSimulationTest(t *testing.T) (*SimulationService, *store.InMemoryStore) {
+	t.Helper()
+	s := store.GetStore()
+	s.Reset()
+	return NewSimulationService(s, NewSwipeService(s)), s
+}
+
+func TestSimulate_CountsAreConsistent(t *testing.T) {
+	sim, s := setupSimulationTest(t)
+
+	result, err := sim.Simulate(10, 200)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.UsersCreated != 10 {
+		t.Errorf("users_created: got %d, want 10", result.UsersCreated)
+	}
+	if got := len(s.GetAllUsers()); got != 10 {
+		t.Errorf("expected 10 users in store, got %d", got)
+	}
+	if result.Swipes != 200 {
+		t.Errorf("swipes: got %d, want 200", result.Swipes)
+	}
+	if result.Likes+result.Passes != result.Swipes {
+		t.Errorf("likes (%d) + passes (%d) != swipes (%d)", result.Likes, result.Passes, result.Swipes)
+	}
+	// Every match is triggered by exactly one LIKE.
+	if result.Matches > result.Likes {
+		t.Errorf("matches (%d) exceed likes (%d)", result.Matches, result.Likes)
+	}
+}
+
+func TestSimulate_IgnoresActiveLikeCap(t *testing.T) {
+	s := store.GetStore()
+	s.Reset()
+	ss := NewSwipeService(s)
+	ss.SetMaxActiveLikes(1)
+	sim := NewSimulationService(s, ss)
+
+	// 10 users and 200 swipes make far more than one outstanding like per
+	// user, which the cap would refuse partway through.
+	result, err := sim.Simulate(10, 200)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Swipes != 200 {
+		t.Errorf("swipes: got %d, want 200", result.Swipes)
+	}
+}
+
+func TestSimulate_RejectsOutOfRangeInput(t *testing.T) {
+	sim, _ := setupSimulationTest(t)
+
+	tests := []struct {
+		name          string
+		users, swipes int
+	}{
+		{"too few users", 1, 10},
+		{"too many users", MaxSimulatedUsers + 1, 10},
+		{"negative swipes", 5, -1},
+		{"too many swipes", 5, MaxSimulatedSwipes + 1},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := sim.Simulate(tc.users, tc.swipes)
+			if _, ok := err.(*ValidationError); !ok {
+				t.Errorf("expected ValidationError, got %T", err)
+			}
+		})
+	}
+}
+
+func TestSeed_SpreadsUsersOverZones(t *testing.T) {
+	sim, s := setupSimulationTest(t)
+
+	result, err := sim.Seed(10, 3, 20)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.UserIDs) != 10 {
+		t.Errorf("user_ids: got %d, want 10", len(result.UserIDs))
+	}
+	if got := len(s.GetAllUsers()); got != 10 {
+		t.Errorf("expected 10 users in store, got %d", got)
+	}
+	if len(result.Zones) != 3 {
+		t.Fatalf("zones: got %v, want 3 zones", result.Zones)
+	}
+
+	perZone := map[string]int{}
+	for _, id := range result.UserIDs {
+		user, ok := s.GetUser(id)
+		if !ok {
+			t.Fatalf("seeded user %s not in store", id)
+		}
+		perZone[user.ZoneID]++
+	}
+	// 10 users over 3 zones: no zone may hold more than one extra user.
+	for _, zone := range result.Zones {
+		if n := perZone[zone]; n < 3 || n > 4 {
+			t.Errorf("zone %s holds %d users, want 3 or 4", zone, n)
+		}
+	}
+
+	if result.Swipes != 20 {
+		t.Errorf("swipes: got %d, want 20", result.Swipes)
+	}
+	if result.Likes+result.Passes != result.Swipes {
+		t.Errorf("likes (%d) + passes (%d) != swipes (%d)", result.Likes, result.Passes, result.Swipes)
+	}
+}
+
+func TestSeed_RejectsOutOfRangeInput(t *testing.T) {
+	sim, s := setupSimulationTest(t)
+
+	tests := []struct {
+		name                 string
+		users, zones, swipes int
+	}{
+		{"no users", 0, 1, 0},
+		{"too many users", MaxSeedUsers + 1, 1, 0},
+		{"no zones", 5, 0, 0},
+		{"too many zones", 5, MaxSeedZones + 1, 0},
+		{"negative swipes", 5, 1, -1},
+		{"too many swipes", 5, 1, MaxSeedSwipes + 1},
+		{"swipes with one user", 1, 1, 1},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := sim.Seed(tc.users, tc.zones, tc.swipes)
+			if _, ok := err.(*ValidationError); !ok {
+				t.Errorf("expected ValidationError, got %T", err)
+			}
+		})
+	}
+	if got := len(s.GetAllUsers()); got != 0 {
+		t.Errorf("rejected seeds should create no users, store has %d", got)
+	}
+}
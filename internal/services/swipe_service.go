@@ -5,6 +5,7 @@ package services
 
 import (
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/dlfelps/tinder-go-claude/internal/models"
@@ -15,11 +16,120 @@ import (
 // SwipeService handles swipe recording and mutual match detection.
 type SwipeService struct {
 	store *store.InMemoryStore
+
+	// matchHooks run after each new match; see OnMatch.
+	matchHooks []MatchHook
+
+	// asyncHooks runs each hook in its own goroutine; see SetAsyncHooks.
+	asyncHooks bool
+
+	// dailyLimit caps swipes per user in any 24 hours; see
+	// SetDailySwipeLimit. Zero means unlimited.
+	dailyLimit int
+
+	// swipeTimes holds, per user, when each of their swipes in the current
+	// limit window was made. The daily limit counts these rather than the
+	// stored swipes, which undo, admin removal, and swipe log pruning all
+	// delete; otherwise a user could undo their way past the limit.
+	// swipeTimesMu guards it, since swipes arrive concurrently.
+	swipeTimesMu sync.Mutex
+	swipeTimes   map[uuid.UUID][]time.Time
+
+	// maxActiveLikes caps a user's outstanding likes; see
+	// SetMaxActiveLikes. Zero means unlimited.
+	maxActiveLikes int
+
+	// now is the clock used for swipe timestamps and the rate limit window.
+	// Tests replace it with SetClock.
+	now func() time.Time
+
+	// ids generates match IDs; see SetIDGenerator.
+	ids IDGenerator
 }
 
-// NewSwipeService creates a new SwipeService connected to the given store.
+// DefaultDailySwipeLimit is how many swipes a user may make in any 24
+// hours unless SetDailySwipeLimit says otherwise.
+const DefaultDailySwipeLimit = 100
+
+// swipeLimitWindow is the trailing window the daily limit applies to.
+const swipeLimitWindow = 24 * time.Hour
+
+// NewSwipeService creates a new SwipeService connected to the given store,
+// with the default daily swipe limit.
 func NewSwipeService(s *store.InMemoryStore) *SwipeService {
-	return &SwipeService{store: s}
+	return &SwipeService{
+		store:      s,
+		dailyLimit: DefaultDailySwipeLimit,
+		swipeTimes: make(map[uuid.UUID][]time.Time),
+		now:        time.Now,
+		ids:        RandomIDs{},
+	}
+}
+
+// SetDailySwipeLimit sets how many swipes a user may make in any trailing
+// 24 hours. Zero or less removes the limit.
+func (ss *SwipeService) SetDailySwipeLimit(limit int) {
+	if limit < 0 {
+		limit = 0
+	}
+	ss.dailyLimit = limit
+}
+
+// SetMaxActiveLikes caps how many outstanding likes a user may have: LIKEs
+// on people who haven't matched with them (yet). Once at the cap, a user
+// must wait for responses before liking anyone new, which nudges them
+// toward matching rather than liking everyone in sight. Zero or less, the
+// default, removes the cap.
+func (ss *SwipeService) SetMaxActiveLikes(limit int) {
+	if limit < 0 {
+		limit = 0
+	}
+	ss.maxActiveLikes = limit
+}
+
+// SetClock replaces the service's time source, so tests can pin "now".
+func (ss *SwipeService) SetClock(now func() time.Time) {
+	ss.now = now
+}
+
+// SetIDGenerator replaces the generator of match IDs, so tests can predict
+// them. Nil restores the default, RandomIDs.
+func (ss *SwipeService) SetIDGenerator(ids IDGenerator) {
+	if ids == nil {
+		ids = RandomIDs{}
+	}
+	ss.ids = ids
+}
+
+// MatchHook is a callback run after a match forms. Hooks let analytics,
+// notifications, webhooks, and the like react to matches without the swipe
+// service knowing about any of them.
+type MatchHook func(models.Match)
+
+// OnMatch registers a hook to run after every new match, in registration
+// order. Register hooks during startup, before the service handles swipes;
+// OnMatch is not safe to call concurrently with ProcessSwipe.
+func (ss *SwipeService) OnMatch(hook MatchHook) {
+	ss.matchHooks = append(ss.matchHooks, hook)
+}
+
+// SetAsyncHooks controls how match hooks run. By default they run
+// synchronously, so ProcessSwipe returns only after every hook has finished.
+// When async is true, each hook runs in its own goroutine and ProcessSwipe
+// does not wait; hooks must then be safe for concurrent use.
+func (ss *SwipeService) SetAsyncHooks(async bool) {
+	ss.asyncHooks = async
+}
+
+// runMatchHooks passes a new match to every registered hook.
+func (ss *SwipeService) runMatchHooks(match models.Match) {
+	for _, hook := range ss.matchHooks {
+		if ss.asyncHooks {
+			go hook(match)
+			continue
+		}
+		hook(match)
+	}
 }
 
 // ProcessSwipeResult holds the outcome of processing a swipe action.
@@ -38,15 +148,28 @@ type ProcessSwipeResult struct {
 }
 
 // ProcessSwipe validates and records a swipe action, then checks for a
-// mutual match. It enforces several business rules:
+// mutual match, which only forms between users whose gender and age
+// preferences suit each other. It enforces several business rules:
 //   - Both the swiper and swiped users must exist (404 error)
 //   - A user cannot swipe on themselves (400 error)
+//   - Neither user may be under models.MinimumAge (400 error)
+//   - Neither user may have blocked the other (403 error)
+//   - A user cannot exceed the daily swipe limit (429 error)
+//   - A LIKE cannot exceed the outstanding like cap (409 error)
 //
 // The function returns a structured result and an error. In Go, we often
 // need to distinguish between different types of errors. Here we use a
 // simple approach: the error message contains enough context for the
 // handler to determine the appropriate HTTP status code.
 func (ss *SwipeService) ProcessSwipe(swiperID, swipedID uuid.UUID, action models.SwipeAction) (*ProcessSwipeResult, error) {
+	return ss.processSwipe(swiperID, swipedID, action, true)
+}
+
+// processSwipe is ProcessSwipe with the per-user limits optional, for the
+// load simulator and the demo seeder, whose synthetic users would otherwise
+// hit limits meant for people: both the daily limit and the outstanding
+// like cap are skipped when enforceLimits is false.
+func (ss *SwipeService) processSwipe(swiperID, swipedID uuid.UUID, action models.SwipeAction, enforceLimits bool) (*ProcessSwipeResult, error) {
 	// Validate business rules.
 
 	// Rule 1: Users cannot swipe on themselves.
@@ -56,23 +179,57 @@ func (ss *SwipeService) ProcessSwipe(swiperID, swipedID uuid.UUID, action models
 	}
 
 	// Rule 2: The swiper must exist.
-	if _, exists := ss.store.GetUser(swiperID); !exists {
+	swiper, exists := ss.store.GetUser(swiperID)
+	if !exists {
 		return nil, &NotFoundError{Message: fmt.Sprintf("swiper user %s not found", swiperID)}
 	}
 
 	// Rule 3: The swiped user must exist.
-	if _, exists := ss.store.GetUser(swipedID); !exists {
+	swiped, exists := ss.store.GetUser(swipedID)
+	if !exists {
 		return nil, &NotFoundError{Message: fmt.Sprintf("swiped user %s not found", swipedID)}
 	}
 
+	// Rule 4: Both users must be adults. Validation keeps minors from being
+	// created, but this guards against any that arrive some other way, such
+	// as an imported data file. Refusing the swipe means no match can form.
+	if swiper.Age < models.MinimumAge || swiped.Age < models.MinimumAge {
+		return nil, &ValidationError{Message: fmt.Sprintf("users under %d cannot swipe or be swiped on", models.MinimumAge)}
+	}
+
+	// Rule 5: Neither user may have blocked the other.
+	if ss.store.IsBlocked(swiperID, swipedID) {
+		return nil, &ForbiddenError{Message: "cannot swipe on a blocked user"}
+	}
+
+	// Rule 6: The swiper must be under their daily limit.
+	now := ss.now().UTC()
+	if enforceLimits {
+		if err := ss.checkDailyLimit(swiperID, now); err != nil {
+			return nil, err
+		}
+	}
+
+	// Rule 7: A LIKE must not push the swiper past their outstanding like
+	// cap.
+	if enforceLimits && action == models.SwipeActionLike {
+		if err := ss.checkActiveLikes(swiperID, swipedID); err != nil {
+			return nil, err
+		}
+	}
+
 	// Record the swipe.
 	swipe := models.Swipe{
 		SwiperID:  swiperID,
 		SwipedID:  swipedID,
 		Action:    action,
-		Timestamp: time.Now().UTC(),
+		Timestamp: now,
 	}
 	ss.store.AddSwipe(swipe)
+	ss.store.TouchUser(swiperID, now)
+	if enforceLimits {
+		ss.recordSwipeTime(swiperID, now)
+	}
 
 	result := &ProcessSwipeResult{
 		Swipe:   swipe,
@@ -84,22 +241,124 @@ func (ss *SwipeService) ProcessSwipe(swiperID, swipedID uuid.UUID, action models
 	if action == models.SwipeActionLike {
 		reverseSwipe := ss.store.FindSwipe(swipedID, swiperID)
 
-		// If a reverse swipe exists and it's also a LIKE, we have a match!
-		if reverseSwipe != nil && reverseSwipe.Action == models.SwipeActionLike {
+		// If a reverse swipe exists and it's also a LIKE, we have a match —
+		// provided each user fits the other's gender and age preferences.
+		// The feed already hides incompatible users, but a swipe can name
+		// anyone, and preferences may have changed since the first LIKE.
+		// The store refuses a second match for a pair that is already
+		// matched (say, after a repeated LIKE), so only a newly stored
+		// match counts.
+		if reverseSwipe != nil && reverseSwipe.Action == models.SwipeActionLike &&
+			preferencesCompatible(swiper, swiped) {
 			match := models.Match{
-				User1ID:   swiperID,
-				User2ID:   swipedID,
-				Timestamp: time.Now().UTC(),
+				ID:          ss.ids.New(),
+				User1ID:     swiperID,
+				User2ID:     swipedID,
+				Timestamp:   now,
+				InitiatorID: swiperID,
+			}
+			if !ss.store.AddMatch(match) {
+				return result, nil
 			}
-			ss.store.AddMatch(match)
 			result.Matched = true
 			result.Match = &match
+
+			// Hooks get their own copy, so they can't alter the result.
+			ss.runMatchHooks(match)
 		}
 	}
 
 	return result, nil
 }
 
+// checkDailyLimit returns a RateLimitError if the user has already made
+// dailyLimit swipes in the 24 hours before now. Swipes count from when they
+// are made (see recordSwipeTime), so undoing one doesn't give it back. The
+// counts are kept in memory only and start from zero after a restart.
+//
+// The check and the later recordSwipeTime are separate steps, so a burst of
+// concurrent swipes from one user can overshoot the limit by a few. That is
+// fine for discouraging mass swiping, which is what the limit is for.
+func (ss *SwipeService) checkDailyLimit(userID uuid.UUID, now time.Time) error {
+	if ss.dailyLimit == 0 {
+		return nil
+	}
+
+	ss.swipeTimesMu.Lock()
+	times := ss.pruneSwipeTimesLocked(userID, now)
+	count := len(times)
+	var oldest time.Time
+	if count > 0 {
+		oldest = times[0]
+	}
+	ss.swipeTimesMu.Unlock()
+	if count < ss.dailyLimit {
+		return nil
+	}
+
+	// A slot frees up once the oldest swipe in the window leaves it.
+	return &RateLimitError{
+		Message:    fmt.Sprintf("daily swipe limit of %d reached", ss.dailyLimit),
+		RetryAfter: oldest.Add(swipeLimitWindow).Sub(now),
+	}
+}
+
+// recordSwipeTime counts a swipe userID made at now toward their daily
+// limit. It does nothing while the limit is off, so the times don't pile
+// up unread.
+func (ss *SwipeService) recordSwipeTime(userID uuid.UUID, now time.Time) {
+	if ss.dailyLimit == 0 {
+		return
+	}
+	ss.swipeTimesMu.Lock()
+	defer ss.swipeTimesMu.Unlock()
+	ss.swipeTimes[userID] = append(ss.pruneSwipeTimesLocked(userID, now), now)
+}
+
+// pruneSwipeTimesLocked drops userID's swipe times that have left the limit
+// window ending at now, and returns the rest, oldest first. The caller must
+// hold swipeTimesMu.
+func (ss *SwipeService) pruneSwipeTimesLocked(userID uuid.UUID, now time.Time) []time.Time {
+	windowStart := now.Add(-swipeLimitWindow)
+	times := ss.swipeTimes[userID]
+	i := 0
+	for i < len(times) && !times[i].After(windowStart) {
+		i++
+	}
+	times = times[i:]
+	if len(times) == 0 {
+		delete(ss.swipeTimes, userID)
+		return nil
+	}
+	ss.swipeTimes[userID] = times
+	return times
+}
+
+// checkActiveLikes returns a ConflictError if a LIKE from swiperID on
+// swipedID would take swiperID past maxActiveLikes outstanding likes. A LIKE
+// that adds nothing outstanding is always allowed: one that answers a LIKE
+// (it matches at once) or one on someone the swiper has already liked.
+//
+// As with the daily limit, concurrent LIKEs from one user can overshoot the
+// cap by a few.
+func (ss *SwipeService) checkActiveLikes(swiperID, swipedID uuid.UUID) error {
+	if ss.maxActiveLikes == 0 {
+		return nil
+	}
+	if reverse := ss.store.FindSwipe(swipedID, swiperID); reverse != nil && reverse.Action == models.SwipeActionLike {
+		return nil
+	}
+	if previous := ss.store.FindSwipe(swiperID, swipedID); previous != nil && previous.Action == models.SwipeActionLike {
+		return nil
+	}
+	if ss.store.CountOutstandingLikes(swiperID) < ss.maxActiveLikes {
+		return nil
+	}
+	return &ConflictError{
+		Message: fmt.Sprintf("you have %d likes waiting for a response; wait for some to match before liking anyone new", ss.maxActiveLikes),
+	}
+}
+
 // ---------------------------------------------------------------------------
 // Custom error types
 // ---------------------------------------------------------------------------
@@ -131,3 +390,41 @@ type ValidationError struct {
 func (e *ValidationError) Error() string {
 	return e.Message
 }
+
+// ForbiddenError indicates an action that is never allowed between these
+// users, such as swiping on someone who is blocked.
+// This maps to HTTP 403 Forbidden.
+type ForbiddenError struct {
+	Message string
+}
+
+// Error implements the error interface for ForbiddenError.
+func (e *ForbiddenError) Error() string {
+	return e.Message
+}
+
+// ConflictError indicates a request that clashes with the current state and
+// may succeed later, such as a LIKE while at the outstanding like cap.
+// This maps to HTTP 409 Conflict.
+type ConflictError struct {
+	Message string
+}
+
+// Error implements the error interface for ConflictError.
+func (e *ConflictError) Error() string {
+	return e.Message
+}
+
+// RateLimitError indicates the user has made too many requests of some kind
+// and should wait. This maps to HTTP 429 Too Many Requests.
+type RateLimitError struct {
+	Message string
+
+	// RetryAfter is how long until the user can try again.
+	RetryAfter time.Duration
+}
+
+// Error implements the error interface for RateLimitError.
+func (e *RateLimitError) Error() string {
+	return e.Message
+}
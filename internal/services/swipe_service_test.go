@@ -1,11 +1,16 @@
 // This file contains unit tests for the SwipeService, covering:
 //   - Successful swipe recording
 //   - Mutual match detection (bidirectional LIKE)
-//   - Business rule enforcement (self-swipe prevention, user existence)
+//   - Business rule enforcement (self-swipe prevention, user existence,
+//     the minimum age, blocks)
+//   - Match hooks (OnMatch), synchronous and async
+//   - The daily swipe limit and the outstanding like cap
 package services
 
 import (
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/dlfelps/tinder-go-claude/internal/models"
 	"github.com/dlfelps/tinder-go-claude/internal/store"
@@ -52,6 +57,27 @@ func TestProcessSwipe_RecordsSwipe(t *testing.T) {
 	}
 }
 
+func TestProcessSwipe_UpdatesSwiperActivity(t *testing.T) {
+	ss, s := setupSwipeTest(t)
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	ss.SetClock(func() time.Time { return now })
+
+	alice := makeTestUser(s, "Alice", "zone-a")
+	bob := makeTestUser(s, "Bob", "zone-a")
+
+	if _, err := ss.ProcessSwipe(alice.ID, bob.ID, models.SwipeActionPass); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Only the swiper was active; being swiped on isn't activity.
+	if got, _ := s.GetUser(alice.ID); !got.LastActiveAt.Equal(now) {
+		t.Errorf("Alice's LastActiveAt: got %v, want %v", got.LastActiveAt, now)
+	}
+	if got, _ := s.GetUser(bob.ID); !got.LastActiveAt.IsZero() {
+		t.Errorf("Bob's LastActiveAt should be unchanged, got %v", got.LastActiveAt)
+	}
+}
+
 func TestProcessSwipe_MutualLikeCreatesMatch(t *testing.T) {
 	ss, s := setupSwipeTest(t)
 
@@ -84,6 +110,74 @@ func TestProcessSwipe_MutualLikeCreatesMatch(t *testing.T) {
 	if len(matches) != 1 {
 		t.Errorf("expected 1 match, got %d", len(matches))
 	}
+
+	// The match carries a server-assigned ID that can be looked up.
+	if result2.Match.ID == uuid.Nil {
+		t.Fatal("expected match to have an ID")
+	}
+	if _, exists := s.GetMatchByID(result2.Match.ID); !exists {
+		t.Error("expected match to be retrievable by ID")
+	}
+
+	// Bob's LIKE completed the match, so he is its initiator.
+	if result2.Match.InitiatorID != bob.ID {
+		t.Errorf("initiator: got %s, want Bob (%s)", result2.Match.InitiatorID, bob.ID)
+	}
+	if stored, _ := s.GetMatchByID(result2.Match.ID); stored.InitiatorID != bob.ID {
+		t.Errorf("stored initiator: got %s, want Bob (%s)", stored.InitiatorID, bob.ID)
+	}
+}
+
+func TestProcessSwipe_RepeatedLikeDoesNotMatchTwice(t *testing.T) {
+	ss, s := setupSwipeTest(t)
+
+	alice := makeTestUser(s, "Alice", "zone-a")
+	bob := makeTestUser(s, "Bob", "zone-a")
+
+	ss.ProcessSwipe(alice.ID, bob.ID, models.SwipeActionLike)
+	ss.ProcessSwipe(bob.ID, alice.ID, models.SwipeActionLike)
+
+	// Alice likes Bob again; they are already matched.
+	result, err := ss.ProcessSwipe(alice.ID, bob.ID, models.SwipeActionLike)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Matched || result.Match != nil {
+		t.Error("expected no new match for an already matched pair")
+	}
+	if n := len(s.GetMatchesForUser(alice.ID)); n != 1 {
+		t.Errorf("expected 1 match, got %d", n)
+	}
+}
+
+func TestProcessSwipe_MatchIDsFromGenerator(t *testing.T) {
+	ss, s := setupSwipeTest(t)
+	ss.SetIDGenerator(&SequentialIDs{})
+
+	alice := makeTestUser(s, "Alice", "zone-a")
+	bob := makeTestUser(s, "Bob", "zone-a")
+	carol := makeTestUser(s, "Carol", "zone-a")
+
+	want := []string{
+		"00000000-0000-0000-0000-000000000001",
+		"00000000-0000-0000-0000-000000000002",
+	}
+	for i, other := range []models.User{bob, carol} {
+		ss.ProcessSwipe(other.ID, alice.ID, models.SwipeActionLike)
+		result, err := ss.ProcessSwipe(alice.ID, other.ID, models.SwipeActionLike)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Matched {
+			t.Fatalf("expected Alice to match %s", other.Name)
+		}
+		if got := result.Match.ID.String(); got != want[i] {
+			t.Errorf("match with %s: got ID %s, want %s", other.Name, got, want[i])
+		}
+		if _, ok := s.GetMatchByID(result.Match.ID); !ok {
+			t.Errorf("match with %s not stored under its generated ID", other.Name)
+		}
+	}
 }
 
 func TestProcessSwipe_LikeAndPassNoMatch(t *testing.T) {
@@ -130,6 +224,88 @@ func TestProcessSwipe_PassAndLikeNoMatch(t *testing.T) {
 	}
 }
 
+func TestProcessSwipe_LikeAfterExpiredPassMatches(t *testing.T) {
+	ss, s := setupSwipeTest(t)
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	ss.SetClock(clock)
+	fs := NewFeedService(s)
+	fs.SetClock(clock)
+	fs.SetPassExpiry(24 * time.Hour)
+
+	alice := makeTestUser(s, "Alice", "zone-a")
+	bob := makeTestUser(s, "Bob", "zone-a")
+
+	if _, err := ss.ProcessSwipe(alice.ID, bob.ID, models.SwipeActionPass); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Once the PASS expires Bob is back in Alice's feed, and this time she
+	// likes him.
+	now = now.Add(48 * time.Hour)
+	feed, _, err := fs.GetFeed(alice.ID, FeedOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := feedNames(feed); len(got) != 1 || got[0] != "Bob" {
+		t.Fatalf("expected Bob back in Alice's feed, got %v", got)
+	}
+	if _, err := ss.ProcessSwipe(alice.ID, bob.ID, models.SwipeActionLike); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Bob's LIKE must be checked against Alice's latest swipe, not her PASS.
+	result, err := ss.ProcessSwipe(bob.ID, alice.ID, models.SwipeActionLike)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Matched {
+		t.Error("expected a match: Alice's LIKE replaced her expired PASS")
+	}
+}
+
+func TestProcessSwipe_IncompatiblePreferencesNoMatch(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(alice, bob *models.User)
+	}{
+		{"Alice not interested in Bob's gender", func(alice, bob *models.User) {
+			alice.Gender, alice.InterestedIn = "female", "female"
+			bob.Gender, bob.InterestedIn = "male", "female"
+		}},
+		{"Bob outside Alice's age range", func(alice, bob *models.User) {
+			alice.MaxAge = 24
+		}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ss, s := setupSwipeTest(t)
+
+			alice := makeTestUser(s, "Alice", "zone-a")
+			bob := makeTestUser(s, "Bob", "zone-a")
+			tc.setup(&alice, &bob)
+			s.UpdateUser(alice)
+			s.UpdateUser(bob)
+
+			// Both LIKE each other, but only Bob's preferences are met.
+			if _, err := ss.ProcessSwipe(alice.ID, bob.ID, models.SwipeActionLike); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			result, err := ss.ProcessSwipe(bob.ID, alice.ID, models.SwipeActionLike)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Matched || s.AreMatched(alice.ID, bob.ID) {
+				t.Error("expected no match when preferences are one-sided")
+			}
+			if len(s.GetAllSwipes()) != 2 {
+				t.Error("expected both swipes to be recorded")
+			}
+		})
+	}
+}
+
 // ---------------------------------------------------------------------------
 // Business rule enforcement tests
 // ---------------------------------------------------------------------------
@@ -182,3 +358,310 @@ func TestProcessSwipe_SwipedNotFound(t *testing.T) {
 		t.Errorf("expected NotFoundError, got %T", err)
 	}
 }
+
+func TestProcessSwipe_MinorsNeverMatch(t *testing.T) {
+	ss, s := setupSwipeTest(t)
+
+	alice := makeTestUser(s, "Alice", "zone-a")
+	// Validation stops minors at the API, so plant one (and a LIKE from
+	// them) directly, as an imported data file could.
+	minor := makeTestUser(s, "Mina", "zone-a")
+	minor.Age = models.MinimumAge - 1
+	s.UpdateUser(minor)
+	s.AddSwipe(models.Swipe{SwiperID: minor.ID, SwipedID: alice.ID, Action: models.SwipeActionLike, Timestamp: time.Now().UTC()})
+
+	tests := []struct {
+		name               string
+		swiperID, swipedID uuid.UUID
+	}{
+		{"adult likes minor", alice.ID, minor.ID},
+		{"minor likes adult", minor.ID, alice.ID},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ss.ProcessSwipe(tt.swiperID, tt.swipedID, models.SwipeActionLike)
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Errorf("expected ValidationError, got %v", err)
+			}
+		})
+	}
+
+	if n := len(s.GetMatchesForUser(alice.ID)); n != 0 {
+		t.Errorf("expected no matches, got %d", n)
+	}
+}
+
+func TestProcessSwipe_BlockedIsForbidden(t *testing.T) {
+	ss, s := setupSwipeTest(t)
+
+	alice := makeTestUser(s, "Alice", "zone-a")
+	bob := makeTestUser(s, "Bob", "zone-a")
+	s.AddBlock(models.Block{BlockerID: bob.ID, BlockedID: alice.ID, Timestamp: time.Now().UTC()})
+
+	// The block works both ways, and nothing is recorded.
+	for _, pair := range [][2]uuid.UUID{{alice.ID, bob.ID}, {bob.ID, alice.ID}} {
+		_, err := ss.ProcessSwipe(pair[0], pair[1], models.SwipeActionLike)
+		var forbiddenErr *ForbiddenError
+		if !errors.As(err, &forbiddenErr) {
+			t.Errorf("expected ForbiddenError, got %v", err)
+		}
+	}
+	if n := len(s.GetAllSwipes()); n != 0 {
+		t.Errorf("expected no swipes recorded, got %d", n)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Match hook tests
+// ---------------------------------------------------------------------------
+
+func TestOnMatch_HookReceivesMatch(t *testing.T) {
+	ss, s := setupSwipeTest(t)
+
+	alice := makeTestUser(s, "Alice", "zone-a")
+	bob := makeTestUser(s, "Bob", "zone-a")
+
+	var got []models.Match
+	ss.OnMatch(func(m models.Match) { got = append(got, m) })
+
+	// A one-sided LIKE and a PASS must not fire the hook.
+	if _, err := ss.ProcessSwipe(alice.ID, bob.ID, models.SwipeActionLike); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 0 {
+		t.Fatalf("expected no hook calls before the match, got %d", len(got))
+	}
+
+	result, err := ss.ProcessSwipe(bob.ID, alice.ID, models.SwipeActionLike)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("expected 1 hook call, got %d", len(got))
+	}
+	if got[0] != *result.Match {
+		t.Errorf("hook got %+v, want %+v", got[0], *result.Match)
+	}
+}
+
+func TestOnMatch_AllHooksRunInOrder(t *testing.T) {
+	ss, s := setupSwipeTest(t)
+
+	alice := makeTestUser(s, "Alice", "zone-a")
+	bob := makeTestUser(s, "Bob", "zone-a")
+
+	var order []string
+	ss.OnMatch(func(models.Match) { order = append(order, "analytics") })
+	ss.OnMatch(func(models.Match) { order = append(order, "notify") })
+	ss.OnMatch(func(models.Match) { order = append(order, "webhook") })
+
+	ss.ProcessSwipe(alice.ID, bob.ID, models.SwipeActionLike)
+	ss.ProcessSwipe(bob.ID, alice.ID, models.SwipeActionLike)
+
+	want := []string{"analytics", "notify", "webhook"}
+	if len(order) != len(want) {
+		t.Fatalf("expected hooks %v, got %v", want, order)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Errorf("hook %d: expected %s, got %s", i, want[i], order[i])
+		}
+	}
+}
+
+func TestOnMatch_AsyncHooks(t *testing.T) {
+	ss, s := setupSwipeTest(t)
+	ss.SetAsyncHooks(true)
+
+	alice := makeTestUser(s, "Alice", "zone-a")
+	bob := makeTestUser(s, "Bob", "zone-a")
+
+	// Async hooks run on other goroutines, so they report back on channels.
+	first := make(chan models.Match, 1)
+	second := make(chan models.Match, 1)
+	ss.OnMatch(func(m models.Match) { first <- m })
+	ss.OnMatch(func(m models.Match) { second <- m })
+
+	ss.ProcessSwipe(alice.ID, bob.ID, models.SwipeActionLike)
+	result, err := ss.ProcessSwipe(bob.ID, alice.ID, models.SwipeActionLike)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for i, ch := range []chan models.Match{first, second} {
+		select {
+		case m := <-ch:
+			if m.ID != result.Match.ID {
+				t.Errorf("hook %d: expected match %s, got %s", i, result.Match.ID, m.ID)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("hook %d did not run", i)
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Daily swipe limit tests
+// ---------------------------------------------------------------------------
+
+func TestProcessSwipe_DailyLimit(t *testing.T) {
+	ss, s := setupSwipeTest(t)
+	ss.SetDailySwipeLimit(3)
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	ss.SetClock(func() time.Time { return now })
+
+	alice := makeTestUser(s, "Alice", "zone-a")
+	others := make([]models.User, 5)
+	for i := range others {
+		others[i] = makeTestUser(s, "Other", "zone-a")
+	}
+
+	// Three swipes, an hour apart, use up the limit.
+	for i := 0; i < 3; i++ {
+		if _, err := ss.ProcessSwipe(alice.ID, others[i].ID, models.SwipeActionPass); err != nil {
+			t.Fatalf("swipe %d: unexpected error: %v", i, err)
+		}
+		now = now.Add(time.Hour)
+	}
+
+	// The fourth is refused and not recorded.
+	_, err := ss.ProcessSwipe(alice.ID, others[3].ID, models.SwipeActionLike)
+	var rateLimitErr *RateLimitError
+	if !errors.As(err, &rateLimitErr) {
+		t.Fatalf("expected RateLimitError, got %v", err)
+	}
+	// The first swipe was at 12:00 and it is now 15:00, so a slot frees up
+	// at 12:00 tomorrow, 21 hours away.
+	if rateLimitErr.RetryAfter != 21*time.Hour {
+		t.Errorf("retry after: got %v, want 21h", rateLimitErr.RetryAfter)
+	}
+	if n := len(s.GetSwipesByUser(alice.ID)); n != 3 {
+		t.Errorf("expected the refused swipe not to be recorded, got %d swipes", n)
+	}
+
+	// Other users have their own allowance.
+	if _, err := ss.ProcessSwipe(others[0].ID, alice.ID, models.SwipeActionLike); err != nil {
+		t.Errorf("expected another user's swipe to succeed, got %v", err)
+	}
+
+	// Once the first swipe is more than 24 hours old, Alice can swipe again.
+	now = time.Date(2024, 6, 2, 12, 0, 1, 0, time.UTC)
+	if _, err := ss.ProcessSwipe(alice.ID, others[3].ID, models.SwipeActionLike); err != nil {
+		t.Errorf("expected a swipe after the window to succeed, got %v", err)
+	}
+}
+
+func TestProcessSwipe_DailyLimitSurvivesUndo(t *testing.T) {
+	ss, s := setupSwipeTest(t)
+	ss.SetDailySwipeLimit(3)
+
+	alice := makeTestUser(s, "Alice", "zone-a")
+	bob := makeTestUser(s, "Bob", "zone-a")
+
+	// Swiping and undoing over and over still uses up the limit.
+	for i := 0; i < 3; i++ {
+		if _, err := ss.ProcessSwipe(alice.ID, bob.ID, models.SwipeActionPass); err != nil {
+			t.Fatalf("swipe %d: unexpected error: %v", i, err)
+		}
+		if _, ok := s.UndoLastSwipe(alice.ID); !ok {
+			t.Fatalf("undo %d: expected a swipe to undo", i)
+		}
+	}
+
+	_, err := ss.ProcessSwipe(alice.ID, bob.ID, models.SwipeActionPass)
+	var rateLimitErr *RateLimitError
+	if !errors.As(err, &rateLimitErr) {
+		t.Fatalf("expected RateLimitError after undoing, got %v", err)
+	}
+}
+
+func TestProcessSwipe_DailyLimitDisabled(t *testing.T) {
+	ss, s := setupSwipeTest(t)
+	ss.SetDailySwipeLimit(0)
+
+	alice := makeTestUser(s, "Alice", "zone-a")
+	for i := 0; i < DefaultDailySwipeLimit+1; i++ {
+		other := makeTestUser(s, "Other", "zone-a")
+		if _, err := ss.ProcessSwipe(alice.ID, other.ID, models.SwipeActionPass); err != nil {
+			t.Fatalf("swipe %d: unexpected error: %v", i, err)
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Outstanding like cap tests
+// ---------------------------------------------------------------------------
+
+func TestProcessSwipe_MaxActiveLikes(t *testing.T) {
+	ss, s := setupSwipeTest(t)
+	ss.SetMaxActiveLikes(2)
+
+	alice := makeTestUser(s, "Alice", "zone-a")
+	bob := makeTestUser(s, "Bob", "zone-a")
+	carol := makeTestUser(s, "Carol", "zone-a")
+	dave := makeTestUser(s, "Dave", "zone-a")
+
+	// Two outstanding likes reach the cap.
+	for _, other := range []models.User{bob, carol} {
+		if _, err := ss.ProcessSwipe(alice.ID, other.ID, models.SwipeActionLike); err != nil {
+			t.Fatalf("like on %s: unexpected error: %v", other.Name, err)
+		}
+	}
+
+	// The third is refused and not recorded.
+	_, err := ss.ProcessSwipe(alice.ID, dave.ID, models.SwipeActionLike)
+	var conflictErr *ConflictError
+	if !errors.As(err, &conflictErr) {
+		t.Fatalf("expected ConflictError, got %v", err)
+	}
+	if n := len(s.GetSwipesByUser(alice.ID)); n != 2 {
+		t.Errorf("expected the refused like not to be recorded, got %d swipes", n)
+	}
+
+	// PASSes and repeated likes add nothing outstanding, so they still go
+	// through.
+	if _, err := ss.ProcessSwipe(alice.ID, dave.ID, models.SwipeActionPass); err != nil {
+		t.Errorf("expected a PASS at the cap to succeed, got %v", err)
+	}
+	if _, err := ss.ProcessSwipe(alice.ID, bob.ID, models.SwipeActionLike); err != nil {
+		t.Errorf("expected a repeated like at the cap to succeed, got %v", err)
+	}
+
+	// Bob likes Alice back. The match frees up one slot.
+	if _, err := ss.ProcessSwipe(bob.ID, alice.ID, models.SwipeActionLike); err != nil {
+		t.Fatalf("Bob's like: unexpected error: %v", err)
+	}
+	if n := s.CountOutstandingLikes(alice.ID); n != 1 {
+		t.Errorf("outstanding likes after the match: got %d, want 1", n)
+	}
+	erin := makeTestUser(s, "Erin", "zone-a")
+	if _, err := ss.ProcessSwipe(alice.ID, erin.ID, models.SwipeActionLike); err != nil {
+		t.Errorf("expected a like after matching to succeed, got %v", err)
+	}
+}
+
+func TestProcessSwipe_MaxActiveLikesAllowsLikeBack(t *testing.T) {
+	ss, s := setupSwipeTest(t)
+	ss.SetMaxActiveLikes(1)
+
+	alice := makeTestUser(s, "Alice", "zone-a")
+	bob := makeTestUser(s, "Bob", "zone-a")
+	carol := makeTestUser(s, "Carol", "zone-a")
+
+	if _, err := ss.ProcessSwipe(alice.ID, bob.ID, models.SwipeActionLike); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := ss.ProcessSwipe(carol.ID, alice.ID, models.SwipeActionLike); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Alice is at the cap, but liking Carol back matches at once.
+	result, err := ss.ProcessSwipe(alice.ID, carol.ID, models.SwipeActionLike)
+	if err != nil {
+		t.Fatalf("expected liking back at the cap to succeed, got %v", err)
+	}
+	if !result.Matched {
+		t.Error("expected the like back to match")
+	}
+}
@@ -0,0 +1,196 @@
+// This file implements MatchWebhook, which tells an external service about
+// new matches by POSTing each one, as JSON, to a configured URL. It plugs
+// into the swipe service as a match hook (see SwipeService.OnMatch), so the
+// swipe service itself knows nothing about HTTP. Like the polling endpoint
+// (NotificationService.DrainMatchEvents), it honors both users'
+// NotifyOnMatch settings and quiet hours.
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/dlfelps/tinder-go-claude/internal/models"
+	"github.com/dlfelps/tinder-go-claude/internal/store"
+	"github.com/google/uuid"
+)
+
+// Limits that keep a slow or unreachable webhook from affecting requests.
+const (
+	// webhookQueueSize is how many matches may wait to be sent. When the
+	// queue is full, further matches are dropped (and logged) rather than
+	// making swipes wait.
+	webhookQueueSize = 100
+
+	// webhookTimeout bounds each POST, including reading the response.
+	webhookTimeout = 5 * time.Second
+)
+
+// MatchWebhook POSTs new matches to a URL from a single background worker.
+// Notify only queues the match, so a swipe never waits on the network.
+// Delivery is best effort: failed POSTs are logged, not retried.
+type MatchWebhook struct {
+	url    string
+	client *http.Client
+
+	// store is where Notify looks up the two users' notification settings.
+	store *store.InMemoryStore
+
+	// now is the clock used to check quiet hours. Tests replace it with
+	// SetClock.
+	now func() time.Time
+
+	// queue carries matches from Notify to the worker. It is buffered, so
+	// Notify returns at once unless webhookQueueSize matches are waiting.
+	queue chan models.Match
+
+	// done is closed when the worker has sent everything and exited.
+	done chan struct{}
+
+	// mu guards closed and deferred. Deferred matches come back to Notify
+	// from timer goroutines, which may fire while Close is running.
+	mu     sync.Mutex
+	closed bool
+
+	// deferred holds a timer per match held back for quiet hours.
+	deferred map[uuid.UUID]*time.Timer
+}
+
+// NewMatchWebhook creates a MatchWebhook for the given http or https URL and
+// starts its worker. The store supplies the users' notification settings.
+// Call Close to stop it.
+func NewMatchWebhook(rawURL string, s *store.InMemoryStore) (*MatchWebhook, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("webhook URL %q must be an absolute http or https URL", rawURL)
+	}
+
+	wh := &MatchWebhook{
+		url:      rawURL,
+		client:   &http.Client{Timeout: webhookTimeout},
+		store:    s,
+		now:      time.Now,
+		queue:    make(chan models.Match, webhookQueueSize),
+		done:     make(chan struct{}),
+		deferred: make(map[uuid.UUID]*time.Timer),
+	}
+	go wh.run()
+	return wh, nil
+}
+
+// SetClock replaces the webhook's time source, so tests can pin "now".
+func (wh *MatchWebhook) SetClock(now func() time.Time) {
+	wh.now = now
+}
+
+// Notify queues a match to be sent. Its signature matches MatchHook, so it
+// can be registered directly with SwipeService.OnMatch. It never blocks: if
+// the queue is full, the match is dropped and a warning is logged.
+//
+// The match is only sent if both users have NotifyOnMatch on; a match
+// involving a user who opted out (or no longer exists) is skipped. If
+// either user is in their quiet hours, the match is held until the later of
+// the two windows ends, then checked again, since settings may have changed
+// in the meantime.
+func (wh *MatchWebhook) Notify(match models.Match) {
+	users := wh.store.GetUsers([]uuid.UUID{match.User1ID, match.User2ID})
+	var holdUntil time.Time
+	for _, id := range []uuid.UUID{match.User1ID, match.User2ID} {
+		user, ok := users[id]
+		if !ok || !user.NotifyOnMatch {
+			return
+		}
+		if user.QuietHours == nil {
+			continue
+		}
+		if until, quiet := user.QuietHours.Until(wh.now()); quiet && until.After(holdUntil) {
+			holdUntil = until
+		}
+	}
+
+	wh.mu.Lock()
+	defer wh.mu.Unlock()
+	if wh.closed {
+		return
+	}
+	if !holdUntil.IsZero() {
+		wh.deferred[match.ID] = time.AfterFunc(holdUntil.Sub(wh.now()), func() {
+			wh.mu.Lock()
+			delete(wh.deferred, match.ID)
+			wh.mu.Unlock()
+			wh.Notify(match)
+		})
+		return
+	}
+	select {
+	case wh.queue <- match:
+	default:
+		slog.Warn("match webhook queue full; dropping match", "match_id", match.ID.String())
+	}
+}
+
+// Close stops accepting matches and waits for the queued ones to be sent.
+// Matches still held for quiet hours can't be sent without breaking those
+// hours, so they are dropped, with a warning saying how many. Close the
+// webhook only once no more swipes can arrive, such as after the HTTP
+// server has shut down; later calls to Notify do nothing.
+func (wh *MatchWebhook) Close() {
+	wh.mu.Lock()
+	wh.closed = true
+	for _, timer := range wh.deferred {
+		timer.Stop()
+	}
+	if n := len(wh.deferred); n > 0 {
+		slog.Warn("match webhook closed with matches held for quiet hours; dropping them", "count", n)
+	}
+	wh.deferred = nil
+	close(wh.queue)
+	wh.mu.Unlock()
+	<-wh.done
+}
+
+// pendingDeferred returns how many matches are held for quiet hours.
+func (wh *MatchWebhook) pendingDeferred() int {
+	wh.mu.Lock()
+	defer wh.mu.Unlock()
+	return len(wh.deferred)
+}
+
+// run is the worker: it sends queued matches one at a time until Close.
+func (wh *MatchWebhook) run() {
+	defer close(wh.done)
+	for match := range wh.queue {
+		if err := wh.send(match); err != nil {
+			slog.Warn("match webhook failed", "match_id", match.ID.String(), "error", err)
+		}
+	}
+}
+
+// send POSTs one match. The body is the match as stored: its ID, both user
+// IDs, and the timestamp. Any 2xx status counts as delivered.
+func (wh *MatchWebhook) send(match models.Match) error {
+	body, err := json.Marshal(match)
+	if err != nil {
+		return fmt.Errorf("encoding match: %w", err)
+	}
+
+	resp, err := wh.client.Post(wh.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// Read the body to the end so the connection can be reused.
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
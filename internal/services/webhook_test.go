@@ -0,0 +1,161 @@
+// This file contains tests for MatchWebhook, using httptest.Server as the
+// receiving end.
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dlfelps/tinder-go-claude/internal/models"
+	"github.com/dlfelps/tinder-go-claude/internal/store"
+)
+
+func TestMatchWebhook_PostsMatchOnMutualLike(t *testing.T) {
+	received := make(chan models.Match, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("got %s with Content-Type %q, want a JSON POST", r.Method, r.Header.Get("Content-Type"))
+		}
+		var match models.Match
+		if err := json.NewDecoder(r.Body).Decode(&match); err != nil {
+			t.Errorf("decoding payload: %v", err)
+		}
+		received <- match
+	}))
+	defer server.Close()
+
+	webhook, err := NewMatchWebhook(server.URL, store.GetStore())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer webhook.Close()
+
+	ss, s := setupSwipeTest(t)
+	ss.OnMatch(webhook.Notify)
+	alice := makeTestUser(s, "Alice", "zone-a")
+	bob := makeTestUser(s, "Bob", "zone-a")
+
+	ss.ProcessSwipe(alice.ID, bob.ID, models.SwipeActionLike)
+	result, err := ss.ProcessSwipe(bob.ID, alice.ID, models.SwipeActionLike)
+	if err != nil || !result.Matched {
+		t.Fatalf("expected a match, got %+v, %v", result, err)
+	}
+
+	select {
+	case got := <-received:
+		want := *result.Match
+		if got.ID != want.ID || got.User1ID != want.User1ID || got.User2ID != want.User2ID || !got.Timestamp.Equal(want.Timestamp) {
+			t.Errorf("payload: got %+v, want %+v", got, want)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("webhook was not called")
+	}
+}
+
+func TestMatchWebhook_SlowEndpointDoesNotBlockSwipes(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+
+	webhook, err := NewMatchWebhook(server.URL, store.GetStore())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Unblock the endpoint before waiting for the worker to finish.
+	defer webhook.Close()
+	defer close(release)
+
+	ss, s := setupSwipeTest(t)
+	ss.OnMatch(webhook.Notify)
+	alice := makeTestUser(s, "Alice", "zone-a")
+	bob := makeTestUser(s, "Bob", "zone-a")
+
+	ss.ProcessSwipe(alice.ID, bob.ID, models.SwipeActionLike)
+	start := time.Now()
+	if _, err := ss.ProcessSwipe(bob.ID, alice.ID, models.SwipeActionLike); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("swipe took %v waiting on the webhook", elapsed)
+	}
+}
+
+func TestNewMatchWebhook_RejectsBadURL(t *testing.T) {
+	for _, raw := range []string{"", "example.com/hook", "ftp://example.com/hook", "http://", "://bad"} {
+		if _, err := NewMatchWebhook(raw, store.GetStore()); err == nil {
+			t.Errorf("%q: expected an error", raw)
+		}
+	}
+}
+
+func TestMatchWebhook_SkipsOptedOutUser(t *testing.T) {
+	var posts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posts.Add(1)
+	}))
+	defer server.Close()
+
+	ss, s := setupSwipeTest(t)
+	webhook, err := NewMatchWebhook(server.URL, s)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ss.OnMatch(webhook.Notify)
+	alice := makeTestUser(s, "Alice", "zone-a")
+	bob := makeTestUser(s, "Bob", "zone-a")
+	bob.NotifyOnMatch = false
+	s.UpdateUser(bob)
+
+	ss.ProcessSwipe(alice.ID, bob.ID, models.SwipeActionLike)
+	if result, err := ss.ProcessSwipe(bob.ID, alice.ID, models.SwipeActionLike); err != nil || !result.Matched {
+		t.Fatalf("expected a match, got %+v, %v", result, err)
+	}
+
+	// Close waits for everything queued to be sent, so any POST has
+	// happened by the time it returns.
+	webhook.Close()
+	if n := posts.Load(); n != 0 {
+		t.Errorf("expected no POST for a user who opted out, got %d", n)
+	}
+}
+
+func TestMatchWebhook_DefersDuringQuietHours(t *testing.T) {
+	var posts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posts.Add(1)
+	}))
+	defer server.Close()
+
+	ss, s := setupSwipeTest(t)
+	webhook, err := NewMatchWebhook(server.URL, s)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// 23:00 UTC is inside Bob's 22:00-07:00 quiet hours.
+	webhook.SetClock(func() time.Time { return time.Date(2024, 6, 1, 23, 0, 0, 0, time.UTC) })
+	ss.OnMatch(webhook.Notify)
+	alice := makeTestUser(s, "Alice", "zone-a")
+	bob := makeTestUser(s, "Bob", "zone-a")
+	bob.QuietHours = &models.QuietHours{Start: "22:00", End: "07:00"}
+	s.UpdateUser(bob)
+
+	ss.ProcessSwipe(alice.ID, bob.ID, models.SwipeActionLike)
+	if result, err := ss.ProcessSwipe(bob.ID, alice.ID, models.SwipeActionLike); err != nil || !result.Matched {
+		t.Fatalf("expected a match, got %+v, %v", result, err)
+	}
+
+	// The match is held, not sent and not dropped.
+	if n := webhook.pendingDeferred(); n != 1 {
+		t.Errorf("expected 1 match held for quiet hours, got %d", n)
+	}
+	webhook.Close()
+	if n := posts.Load(); n != 0 {
+		t.Errorf("expected no POST during quiet hours, got %d", n)
+	}
+}
@@ -0,0 +1,192 @@
+// This file adds file persistence to the InMemoryStore, so a server can
+// keep its users, swipes, matches, match seen receipts, blocks, and reports
+// across restarts. The whole store is written as one JSON document; that is
+// fine for demos and small data sets, where it beats setting up a real
+// database.
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/dlfelps/tinder-go-claude/internal/models"
+	"github.com/google/uuid"
+)
+
+// snapshot is the on-disk form of the store. Exposure counts are not saved:
+// they only tune feed ordering, and may as well start fresh. Seen receipts
+// are, since losing them would show every match as new after a restart.
+type snapshot struct {
+	Users   []models.User   `json:"users"`
+	Swipes  []models.Swipe  `json:"swipes"`
+	Matches []models.Match  `json:"matches"`
+	Blocks  []models.Block  `json:"blocks"`
+	Reports []models.Report `json:"reports"`
+
+	// SeenMatches maps each user ID to the IDs of the matches that user has
+	// marked seen; see MarkMatchSeen.
+	SeenMatches map[uuid.UUID][]uuid.UUID `json:"seen_matches"`
+}
+
+// SaveToFile writes the store's users, swipes, matches, seen receipts,
+// blocks, and reports to path as JSON.
+//
+// The data goes to a temporary file in the same directory first, which is
+// then renamed over path. A rename within one directory is atomic, so a
+// crash mid-save leaves the previous file intact rather than half-written.
+// The read lock is held throughout, so the file is a consistent snapshot.
+func (s *InMemoryStore) SaveToFile(path string) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	snap := snapshot{
+		Users:   make([]models.User, 0, len(s.users)),
+		Swipes:  s.swipes,
+		Matches: s.matches,
+		Blocks:  make([]models.Block, 0, len(s.blocks)),
+		Reports: s.reports,
+
+		SeenMatches: make(map[uuid.UUID][]uuid.UUID, len(s.seenMatches)),
+	}
+	for _, user := range s.users {
+		snap.Users = append(snap.Users, user)
+	}
+	for userID, seen := range s.seenMatches {
+		for matchID := range seen {
+			snap.SeenMatches[userID] = append(snap.SeenMatches[userID], matchID)
+		}
+	}
+	for _, block := range s.blocks {
+		snap.Blocks = append(snap.Blocks, block)
+	}
+
+	data, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding store: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("saving store: %w", err)
+	}
+	// Removing the temp file fails harmlessly once it has been renamed.
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("saving store: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("saving store: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("saving store: %w", err)
+	}
+	return nil
+}
+
+// LoadFromFile replaces the store's users, swipes, matches, seen receipts,
+// blocks, and reports with those saved in path by SaveToFile. Files saved
+// before seen receipts, blocks, or reports existed load with none. If path
+// doesn't exist yet, as on a first run, the store is left unchanged and no
+// error is returned. If the file can't be read or parsed, the store is also
+// left unchanged and the error is returned.
+//
+// Exposure counts are cleared, the user cache (if enabled) is emptied, and
+// the swipe log cap (if set) is applied to the loaded swipes. A repeated
+// match for the same pair is dropped with a warning, keeping the first, and
+// a seen receipt for a match that wasn't loaded, or that the user isn't
+// part of, is dropped too.
+func (s *InMemoryStore) LoadFromFile(path string) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("loading store: %w", err)
+	}
+
+	var snap snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return fmt.Errorf("loading store from %s: %w", path, err)
+	}
+
+	users := make(map[uuid.UUID]models.User, len(snap.Users))
+	for _, user := range snap.Users {
+		users[user.ID] = user
+	}
+	// A file with "swipes": null (or none at all) loads as empty.
+	if snap.Swipes == nil {
+		snap.Swipes = make([]models.Swipe, 0)
+	}
+	// A pair can only be matched once (see AddMatch). If a hand-edited
+	// file matches a pair twice, the first match is kept and the drop is
+	// logged, so the data loss isn't silent.
+	matches := make([]models.Match, 0, len(snap.Matches))
+	matchPairs := make(map[[2]uuid.UUID]struct{}, len(snap.Matches))
+	dropped := 0
+	for _, match := range snap.Matches {
+		key := pairKey(match.User1ID, match.User2ID)
+		if _, dup := matchPairs[key]; dup {
+			dropped++
+			continue
+		}
+		matchPairs[key] = struct{}{}
+		matches = append(matches, match)
+	}
+	if dropped > 0 {
+		slog.Warn("dropped duplicate matches", "count", dropped)
+	}
+
+	if snap.Reports == nil {
+		snap.Reports = make([]models.Report, 0)
+	}
+	blocks := make(map[[2]uuid.UUID]models.Block, len(snap.Blocks))
+	for _, block := range snap.Blocks {
+		blocks[[2]uuid.UUID{block.BlockerID, block.BlockedID}] = block
+	}
+
+	// Rebuild the seen sets, keeping only receipts MarkMatchSeen could
+	// have written: the match exists and the user is in it.
+	matchesByID := make(map[uuid.UUID]models.Match, len(matches))
+	for _, match := range matches {
+		matchesByID[match.ID] = match
+	}
+	seenMatches := make(map[uuid.UUID]map[uuid.UUID]struct{}, len(snap.SeenMatches))
+	for userID, matchIDs := range snap.SeenMatches {
+		for _, matchID := range matchIDs {
+			match, ok := matchesByID[matchID]
+			if !ok || (match.User1ID != userID && match.User2ID != userID) {
+				continue
+			}
+			if seenMatches[userID] == nil {
+				seenMatches[userID] = make(map[uuid.UUID]struct{})
+			}
+			seenMatches[userID][matchID] = struct{}{}
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.users = users
+	s.swipes = snap.Swipes
+	s.matches = matches
+	s.matchPairs = matchPairs
+	s.blocks = blocks
+	s.reports = snap.Reports
+	s.seenMatches = seenMatches
+	s.exposures = make(map[uuid.UUID]int)
+	if s.userCache != nil {
+		s.userCache = newUserCache(s.userCache.capacity)
+	}
+	if s.maxSwipes > 0 && len(s.swipes) > s.maxSwipes {
+		s.pruneSwipesLocked()
+	}
+	return nil
+}
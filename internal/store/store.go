@@ -3,7 +3,9 @@
 // matches in memory using Go's built-in data structures.
 //
 // Key Go concepts demonstrated here:
-//   - sync.Mutex for thread-safe access to shared data
+//   - sync.RWMutex for thread-safe access to shared data: any number of
+//     readers can hold the read lock at once, while a writer takes the
+//     write lock and waits for every reader to finish
 //   - Maps (hash tables) for O(1) lookups by ID
 //   - Slices (dynamic arrays) for ordered collections
 //   - The sync package for concurrency primitives
@@ -13,23 +15,31 @@
 package store
 
 import (
+	"bytes"
+	"fmt"
+	"slices"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/dlfelps/tinder-go-claude/internal/models"
 	"github.com/google/uuid"
 )
 
 // InMemoryStore holds all application data in memory. It is safe for
-// concurrent use because all methods acquire a mutex lock before reading
-// or writing data.
+// concurrent use because all methods acquire a lock before reading or
+// writing data.
 //
-// In Go, we achieve thread safety with sync.Mutex rather than Python's GIL
-// or asyncio locks. The mutex ensures that only one goroutine can access
-// the store's data at a time.
+// In Go, we achieve thread safety with sync.RWMutex rather than Python's
+// GIL or asyncio locks. It is a readers-writer lock: any number of
+// goroutines can hold the read lock (RLock) at once, but the write lock
+// (Lock) is exclusive. Most traffic is reads (feeds, lookups), so readers
+// no longer queue behind each other, while a write still waits for
+// in-flight reads and blocks new ones until it is done.
 type InMemoryStore struct {
 	// mu protects all fields below from concurrent access.
-	// Convention: always lock mu before reading or writing any field.
-	mu sync.Mutex
+	// Convention: RLock mu to only read fields, Lock it to change any.
+	mu sync.RWMutex
 
 	// users maps user IDs to User structs for O(1) lookups.
 	users map[uuid.UUID]models.User
@@ -39,6 +49,35 @@ type InMemoryStore struct {
 
 	// matches stores all match records in chronological order.
 	matches []models.Match
+
+	// matchPairs holds the pairKey of every match in matches, so AddMatch
+	// can refuse a second match for a pair and AreMatched can answer in
+	// O(1) instead of scanning. Every change to matches must keep it in
+	// step.
+	matchPairs map[[2]uuid.UUID]struct{}
+
+	// seenMatches holds, per user, the IDs of the matches they have marked
+	// seen. Each participant has their own receipt.
+	seenMatches map[uuid.UUID]map[uuid.UUID]struct{}
+
+	// blocks holds every block, keyed by [blocker, blocked]. Unlike
+	// pairKey the order matters, because it records who blocked whom.
+	blocks map[[2]uuid.UUID]models.Block
+
+	// reports stores all abuse reports in the order they were filed.
+	reports []models.Report
+
+	// exposures counts how many times each user has been served at the top
+	// of someone's feed. The feed service uses it to spread exposure.
+	exposures map[uuid.UUID]int
+
+	// maxSwipes caps the length of the swipe log; see SetMaxSwipes.
+	// Zero means unlimited.
+	maxSwipes int
+
+	// userCache is an optional LRU in front of users for GetUser; see
+	// SetUserCacheSize. Nil means disabled.
+	userCache *userCache
 }
 
 // ---------------------------------------------------------------------------
@@ -50,9 +89,14 @@ type InMemoryStore struct {
 // by sync.Once for lazy initialization. Here we use a simple variable since
 // we want it available immediately.
 var defaultStore = &InMemoryStore{
-	users:   make(map[uuid.UUID]models.User),
-	swipes:  make([]models.Swipe, 0),
-	matches: make([]models.Match, 0),
+	users:       make(map[uuid.UUID]models.User),
+	swipes:      make([]models.Swipe, 0),
+	matches:     make([]models.Match, 0),
+	matchPairs:  make(map[[2]uuid.UUID]struct{}),
+	seenMatches: make(map[uuid.UUID]map[uuid.UUID]struct{}),
+	blocks:      make(map[[2]uuid.UUID]models.Block),
+	reports:     make([]models.Report, 0),
+	exposures:   make(map[uuid.UUID]int),
 }
 
 // GetStore returns the singleton InMemoryStore instance. Every part of the
@@ -75,6 +119,138 @@ func (s *InMemoryStore) AddUser(user models.User) {
 	defer s.mu.Unlock()
 
 	s.users[user.ID] = user
+	s.invalidateUserLocked(user.ID)
+}
+
+// UpdateUser replaces an existing user's stored record. It returns false
+// (and stores nothing) if no user with user.ID exists, so updates can never
+// create users by accident.
+func (s *InMemoryStore) UpdateUser(user models.User) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.users[user.ID]; !exists {
+		return false
+	}
+	s.users[user.ID] = user
+	s.invalidateUserLocked(user.ID)
+	return true
+}
+
+// ModifyUser applies modify to the stored user with the given ID while
+// holding the write lock, and returns the updated user. It returns false
+// (and calls nothing) if the user doesn't exist.
+//
+// Use it instead of GetUser followed by UpdateUser whenever a change only
+// touches some fields. With a separate read and write, two requests that
+// change different fields can interleave, and whichever writes last puts
+// back its stale copy of the other's field. Here the read, the change, and
+// the write happen under one lock, so every change sees the ones before it.
+// modify must not call back into the store, which would deadlock.
+func (s *InMemoryStore) ModifyUser(id uuid.UUID, modify func(user *models.User)) (models.User, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, exists := s.users[id]
+	if !exists {
+		return models.User{}, false
+	}
+	modify(&user)
+	s.users[id] = user
+	s.invalidateUserLocked(id)
+	return user, true
+}
+
+// touchInterval is how stale LastActiveAt may get before TouchUser updates
+// it. Activity only needs to be known to within a few minutes (see the feed
+// service's online window), and skipping the write saves taking the write
+// lock on every feed request.
+const touchInterval = time.Minute
+
+// TouchUser records that the user was active at the given time by updating
+// LastActiveAt. The timestamp only moves forward, so an older time (say,
+// from a request that started before another finished) never makes a user
+// look less active. It is also left alone when it is less than
+// touchInterval older than at, so LastActiveAt can lag by up to a minute.
+// It returns false if the user doesn't exist.
+func (s *InMemoryStore) TouchUser(id uuid.UUID, at time.Time) bool {
+	// Most calls find a recent timestamp and need no write, so check under
+	// the read lock first. That lets concurrent feed requests proceed in
+	// parallel.
+	s.mu.RLock()
+	user, exists := s.users[id]
+	s.mu.RUnlock()
+	if !exists {
+		return false
+	}
+	if at.Sub(user.LastActiveAt) < touchInterval {
+		return true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Look again: the user may have changed since the read lock was
+	// released.
+	user, exists = s.users[id]
+	if !exists {
+		return false
+	}
+	if at.After(user.LastActiveAt) {
+		user.LastActiveAt = at
+		s.users[id] = user
+		s.invalidateUserLocked(id)
+	}
+	return true
+}
+
+// DeleteUser removes a user and cascades the deletion: every swipe the user
+// made or received and every match they are part of is removed too, along
+// with their exposure count, so they vanish from other users' feeds and
+// match lists. It returns false if the user does not exist.
+//
+// Everything happens under one lock, so no reader can observe the user
+// gone while their swipes or matches are still present.
+func (s *InMemoryStore) DeleteUser(id uuid.UUID) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.users[id]; !exists {
+		return false
+	}
+	delete(s.users, id)
+	delete(s.exposures, id)
+	delete(s.seenMatches, id)
+	s.invalidateUserLocked(id)
+
+	// Filter both slices in place, preserving chronological order.
+	keptSwipes := s.swipes[:0]
+	for _, swipe := range s.swipes {
+		if swipe.SwiperID != id && swipe.SwipedID != id {
+			keptSwipes = append(keptSwipes, swipe)
+		}
+	}
+	clear(s.swipes[len(keptSwipes):])
+	s.swipes = keptSwipes
+
+	keptMatches := s.matches[:0]
+	for _, match := range s.matches {
+		if match.User1ID != id && match.User2ID != id {
+			keptMatches = append(keptMatches, match)
+			continue
+		}
+		s.forgetMatchLocked(match)
+	}
+	clear(s.matches[len(keptMatches):])
+	s.matches = keptMatches
+
+	for key := range s.blocks {
+		if key[0] == id || key[1] == id {
+			delete(s.blocks, key)
+		}
+	}
+
+	return true
 }
 
 // GetUser retrieves a user by their UUID. It returns the user and a boolean
@@ -83,18 +259,76 @@ func (s *InMemoryStore) AddUser(user models.User) {
 // This follows the Go convention of returning (value, ok) instead of raising
 // exceptions. The caller checks the boolean to handle the "not found" case.
 func (s *InMemoryStore) GetUser(id uuid.UUID) (models.User, bool) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.userCache != nil {
+		if user, ok := s.userCache.get(id); ok {
+			return user, true
+		}
+	}
 
 	user, exists := s.users[id]
+	// Only real users are cached; a miss is cheap to repeat and caching
+	// it would need invalidating when that ID is later added.
+	if exists && s.userCache != nil {
+		s.userCache.put(user)
+	}
 	return user, exists
 }
 
+// SetUserCacheSize enables a read-through LRU cache of up to size users
+// for GetUser, or disables it if size is zero or less. Changing the size
+// starts with an empty cache.
+//
+// Every write to a user (AddUser, UpdateUser, DeleteUser) invalidates that
+// user's cache entry under the same lock as the write, so GetUser never
+// returns a stale profile. With the users map in memory the cache saves
+// little today; it exists so a slower backing store can sit behind the
+// same GetUser contract without every read paying for it.
+func (s *InMemoryStore) SetUserCacheSize(size int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if size <= 0 {
+		s.userCache = nil
+		return
+	}
+	s.userCache = newUserCache(size)
+}
+
+// invalidateUserLocked drops id from the user cache, if one is enabled.
+// The caller must hold s.mu.
+func (s *InMemoryStore) invalidateUserLocked(id uuid.UUID) {
+	if s.userCache != nil {
+		s.userCache.invalidate(id)
+	}
+}
+
+// GetUsers looks up several users at once. The lookups happen under a single
+// lock acquisition, which is cheaper than calling GetUser in a loop and gives
+// the caller a consistent view of the users map.
+//
+// Only users that exist are included in the returned map; callers can detect
+// missing IDs with the comma-ok idiom.
+func (s *InMemoryStore) GetUsers(ids []uuid.UUID) map[uuid.UUID]models.User {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make(map[uuid.UUID]models.User, len(ids))
+	for _, id := range ids {
+		if user, exists := s.users[id]; exists {
+			result[id] = user
+		}
+	}
+	return result
+}
+
 // GetAllUsers returns a slice containing all users in the store. The order
 // is not guaranteed because Go maps do not maintain insertion order.
 func (s *InMemoryStore) GetAllUsers() []models.User {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 
 	// Pre-allocate the slice with the exact capacity we need. This avoids
 	// unnecessary memory reallocations as we append items.
@@ -105,25 +339,104 @@ func (s *InMemoryStore) GetAllUsers() []models.User {
 	return result
 }
 
+// GetUsersInZone returns all users whose ZoneID equals the given zone. Like
+// GetAllUsers, the order of the result is not guaranteed.
+func (s *InMemoryStore) GetUsersInZone(zoneID string) []models.User {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []models.User
+	for _, user := range s.users {
+		if user.ZoneID == zoneID {
+			result = append(result, user)
+		}
+	}
+	return result
+}
+
 // ---------------------------------------------------------------------------
 // Swipe operations
 // ---------------------------------------------------------------------------
 
-// AddSwipe records a new swipe action in the store.
+// AddSwipe records a new swipe action in the store. If a swipe log cap is
+// set and the log is now over it, the oldest evictable swipes are pruned.
 func (s *InMemoryStore) AddSwipe(swipe models.Swipe) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	s.swipes = append(s.swipes, swipe)
+	if s.maxSwipes > 0 && len(s.swipes) > s.maxSwipes {
+		s.pruneSwipesLocked()
+	}
 }
 
-// GetSwipesByUser returns all swipe records where the given user was the swiper.
-// This is used by the feed service to determine which users have already been
-// swiped on (the "seen-state" filter).
-func (s *InMemoryStore) GetSwipesByUser(userID uuid.UUID) []models.Swipe {
+// SetMaxSwipes caps the swipe log at max entries to bound memory. Zero or
+// less removes the cap.
+//
+// Only PASS swipes are ever evicted. A LIKE is either still pending (the
+// other user may yet like back, and match detection needs to find it) or
+// has produced a match, so dropping one would lose a match. The trade-off
+// is that an evicted PASS no longer counts as "seen", so that candidate can
+// reappear in the swiper's feed. If the log is over the cap with nothing
+// left to evict, it is allowed to stay over.
+func (s *InMemoryStore) SetMaxSwipes(max int) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if max < 0 {
+		max = 0
+	}
+	s.maxSwipes = max
+	if s.maxSwipes > 0 && len(s.swipes) > s.maxSwipes {
+		s.pruneSwipesLocked()
+	}
+}
+
+// pruneSwipesLocked drops the oldest PASS swipes until the log fits within
+// maxSwipes. The "Locked" suffix is a Go convention meaning the caller must
+// already hold s.mu.
+//
+// The swipe slice is the only place swipes are stored, so compacting it in
+// place (keeping order) is all it takes to stay consistent.
+func (s *InMemoryStore) pruneSwipesLocked() {
+	excess := len(s.swipes) - s.maxSwipes
+
+	// Filter in place: kept swipes are copied toward the front of the same
+	// backing array, which avoids allocating a new slice.
+	kept := s.swipes[:0]
+	for _, swipe := range s.swipes {
+		if excess > 0 && swipe.Action == models.SwipeActionPass {
+			excess--
+			continue
+		}
+		kept = append(kept, swipe)
+	}
+
+	// Zero the now-unused tail so the garbage collector doesn't see stale
+	// copies through the backing array.
+	clear(s.swipes[len(kept):])
+	s.swipes = kept
+}
+
+// GetAllSwipes returns a copy of every swipe record in chronological order.
+func (s *InMemoryStore) GetAllSwipes() []models.Swipe {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// copy() into a new slice so callers can't modify the store's backing array.
+	result := make([]models.Swipe, len(s.swipes))
+	copy(result, s.swipes)
+	return result
+}
+
+// GetSwipesByUser returns all swipe records where the given user was the swiper,
+// in the order they were added to the log. That is chronological for swipes
+// made through the API, but not necessarily for swipes loaded from a file;
+// use GetSwipesByUserSorted when the order matters.
+func (s *InMemoryStore) GetSwipesByUser(userID uuid.UUID) []models.Swipe {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
 	// Filter the swipes slice for entries matching the given swiper ID.
 	// In Go, there's no built-in "filter" function like Python's list
 	// comprehension — we use a simple loop instead.
@@ -136,18 +449,137 @@ func (s *InMemoryStore) GetSwipesByUser(userID uuid.UUID) []models.Swipe {
 	return result
 }
 
+// GetSwipesByUserSorted returns the same swipes as GetSwipesByUser, sorted
+// by Timestamp: oldest first, or newest first when descending is true.
+// Swipes with equal timestamps are ordered as if the later-logged one were
+// newer, so the two directions are exact reverses of each other.
+func (s *InMemoryStore) GetSwipesByUserSorted(userID uuid.UUID, descending bool) []models.Swipe {
+	swipes := s.GetSwipesByUser(userID)
+
+	// The log is almost always in order already, so a stable sort only has
+	// to fix up swipes that were loaded out of order. Reversing first makes
+	// ties come out later-logged first in the descending case.
+	if descending {
+		slices.Reverse(swipes)
+		sort.SliceStable(swipes, func(i, j int) bool {
+			return swipes[i].Timestamp.After(swipes[j].Timestamp)
+		})
+		return swipes
+	}
+	sort.SliceStable(swipes, func(i, j int) bool {
+		return swipes[i].Timestamp.Before(swipes[j].Timestamp)
+	})
+	return swipes
+}
+
+// GetLikesReceived returns all LIKE swipes where the given user was the one
+// being swiped on, in chronological order.
+func (s *InMemoryStore) GetLikesReceived(userID uuid.UUID) []models.Swipe {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []models.Swipe
+	for _, swipe := range s.swipes {
+		if swipe.SwipedID == userID && swipe.Action == models.SwipeActionLike {
+			result = append(result, swipe)
+		}
+	}
+	return result
+}
+
+// CountLikesReceived returns how many LIKE swipes each of the given users
+// has received, in one pass over the swipes under one read lock. Every ID
+// passed in has an entry, zero if they have no likes; repeated LIKEs from
+// the same swiper each count.
+func (s *InMemoryStore) CountLikesReceived(ids []uuid.UUID) map[uuid.UUID]int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	counts := make(map[uuid.UUID]int, len(ids))
+	for _, id := range ids {
+		counts[id] = 0
+	}
+	for _, swipe := range s.swipes {
+		if swipe.Action != models.SwipeActionLike {
+			continue
+		}
+		if _, ok := counts[swipe.SwipedID]; ok {
+			counts[swipe.SwipedID]++
+		}
+	}
+	return counts
+}
+
+// CountOutstandingLikes returns how many users userID has LIKEd without
+// (yet) matching: each liked user counts once, however many LIKEs they got,
+// and a like stops counting as soon as the pair is matched. Only the latest
+// swipe on each user counts, as in FindSwipe, so a LIKE followed by a PASS
+// is not outstanding: it can no longer lead to a match.
+func (s *InMemoryStore) CountOutstandingLikes(userID uuid.UUID) int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// s.swipes is in the order the swipes were made, so the last action
+	// written for each user is the latest.
+	latest := make(map[uuid.UUID]models.SwipeAction)
+	for _, swipe := range s.swipes {
+		if swipe.SwiperID == userID {
+			latest[swipe.SwipedID] = swipe.Action
+		}
+	}
+
+	count := 0
+	for swipedID, action := range latest {
+		if action != models.SwipeActionLike {
+			continue
+		}
+		if _, matched := s.matchPairs[pairKey(userID, swipedID)]; !matched {
+			count++
+		}
+	}
+	return count
+}
+
+// GetUnswipedUsers returns every user nobody has swiped on yet, whether
+// with a LIKE or a PASS. The order is not guaranteed.
+//
+// Only the swipe log is consulted, so if a swipe log cap has pruned a user's
+// only incoming swipes, that user is reported as unswiped again.
+func (s *InMemoryStore) GetUnswipedUsers() []models.User {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	swiped := make(map[uuid.UUID]struct{}, len(s.users))
+	for _, swipe := range s.swipes {
+		swiped[swipe.SwipedID] = struct{}{}
+	}
+
+	var result []models.User
+	for id, user := range s.users {
+		if _, ok := swiped[id]; !ok {
+			result = append(result, user)
+		}
+	}
+	return result
+}
+
 // FindSwipe searches for a specific swipe from one user to another.
 // It returns a pointer to the Swipe if found, or nil if no such swipe exists.
+// A user can swipe the same person more than once (after a PASS expires,
+// say), and only the latest swipe reflects what they think now, so that is
+// the one returned.
 //
 // Using a pointer return (*models.Swipe) is the Go idiom for "maybe a value."
 // Python would use Optional[Swipe] or return None; Go uses nil pointers.
 func (s *InMemoryStore) FindSwipe(swiperID, swipedID uuid.UUID) *models.Swipe {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 
-	// Linear scan through all swipes. In production, you'd want an index
-	// (e.g., a map keyed by (swiperID, swipedID)) for O(1) lookup.
-	for _, swipe := range s.swipes {
+	// Linear scan through all swipes, newest first: s.swipes is in the order
+	// the swipes were made. In production, you'd want an index (e.g., a map
+	// keyed by (swiperID, swipedID)) for O(1) lookup.
+	for i := len(s.swipes) - 1; i >= 0; i-- {
+		swipe := s.swipes[i]
 		if swipe.SwiperID == swiperID && swipe.SwipedID == swipedID {
 			// Return a pointer to a copy of the swipe. We copy it so the
 			// caller can't accidentally modify the store's internal data.
@@ -158,23 +590,348 @@ func (s *InMemoryStore) FindSwipe(swiperID, swipedID uuid.UUID) *models.Swipe {
 	return nil
 }
 
+// ---------------------------------------------------------------------------
+// Feed snapshot
+// ---------------------------------------------------------------------------
+
+// FeedSnapshot is a consistent, point-in-time view of the data needed to
+// build a user's feed.
+type FeedSnapshot struct {
+	// User is the user requesting the feed.
+	User models.User
+
+	// Candidates holds every user in the store (including the requester);
+	// the feed service applies its filters to this slice.
+	Candidates []models.User
+
+	// Seen is the set of user IDs the requester has already swiped on,
+	// leaving out PASSes that have expired (see SnapshotForFeed).
+	Seen map[uuid.UUID]struct{}
+
+	// Blocked is the set of user IDs the requester has blocked or been
+	// blocked by.
+	Blocked map[uuid.UUID]struct{}
+}
+
+// SnapshotForFeed reads the requesting user, all candidate users, and the
+// requester's seen-set under a single lock. Reading them separately would
+// leave a window where a swipe lands between the two reads, letting a
+// just-swiped user slip back into the feed.
+//
+// A PASS made before passesSince has expired and doesn't count as seen, so
+// that candidate can be rediscovered. LIKEs never expire, and a zero
+// passesSince keeps every PASS.
+//
+// The boolean result is false if the requesting user does not exist.
+func (s *InMemoryStore) SnapshotForFeed(userID uuid.UUID, passesSince time.Time) (FeedSnapshot, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	user, exists := s.users[userID]
+	if !exists {
+		return FeedSnapshot{}, false
+	}
+
+	candidates := make([]models.User, 0, len(s.users))
+	for _, candidate := range s.users {
+		candidates = append(candidates, candidate)
+	}
+
+	seen := make(map[uuid.UUID]struct{})
+	for _, swipe := range s.swipes {
+		if swipe.SwiperID != userID {
+			continue
+		}
+		if swipe.Action == models.SwipeActionPass && swipe.Timestamp.Before(passesSince) {
+			continue
+		}
+		seen[swipe.SwipedID] = struct{}{}
+	}
+
+	blocked := make(map[uuid.UUID]struct{})
+	for key := range s.blocks {
+		if key[0] == userID {
+			blocked[key[1]] = struct{}{}
+		} else if key[1] == userID {
+			blocked[key[0]] = struct{}{}
+		}
+	}
+
+	return FeedSnapshot{User: user, Candidates: candidates, Seen: seen, Blocked: blocked}, true
+}
+
+// UndoLastSwipe removes the most recent swipe made by userID and returns
+// it, or returns (nil, false) if the user has no swipes. Removing a LIKE also
+// removes any match it produced, so the pair goes back to a one-sided LIKE
+// from the other user. Both happen under one lock so no reader can see the
+// match without the swipe behind it.
+func (s *InMemoryStore) UndoLastSwipe(userID uuid.UUID) (*models.Swipe, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	undone, _ := s.undoLastSwipeLocked(userID, false)
+	return undone, undone != nil
+}
+
+// UndoLastSwipeUnlessMatched is UndoLastSwipe for when matches must be kept.
+// If undoing the latest swipe would remove a match, nothing changes and
+// matched is true. Otherwise the swipe is removed and returned, or undone
+// is nil if the user has no swipes. Checking and removing under one lock
+// means a match formed in between can't slip through.
+func (s *InMemoryStore) UndoLastSwipeUnlessMatched(userID uuid.UUID) (undone *models.Swipe, matched bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.undoLastSwipeLocked(userID, true)
+}
+
+// undoLastSwipeLocked implements UndoLastSwipe and
+// UndoLastSwipeUnlessMatched. The caller must hold s.mu.
+func (s *InMemoryStore) undoLastSwipeLocked(userID uuid.UUID, keepMatches bool) (undone *models.Swipe, matched bool) {
+	// Walk backwards: swipes are in chronological order, so the first hit
+	// is the latest.
+	for i := len(s.swipes) - 1; i >= 0; i-- {
+		swipe := s.swipes[i]
+		if swipe.SwiperID != userID {
+			continue
+		}
+
+		// The match goes only with the last LIKE behind it; a repeated
+		// LIKE still holds it up.
+		_, paired := s.matchPairs[pairKey(swipe.SwiperID, swipe.SwipedID)]
+		unmatches := paired && swipe.Action == models.SwipeActionLike &&
+			s.countLikesLocked(swipe.SwiperID, swipe.SwipedID) == 1
+		if unmatches && keepMatches {
+			return nil, true
+		}
+
+		s.swipes = append(s.swipes[:i], s.swipes[i+1:]...)
+		if unmatches {
+			s.removePairMatchesLocked(swipe.SwiperID, swipe.SwipedID)
+		}
+		return &swipe, false
+	}
+	return nil, false
+}
+
+// RemoveSwipe deletes swiperID's swipe on swipedID, such as one an admin
+// has found to be abuse, and reports whether there was one. A repeated
+// swipe on the same person is removed along with the original, since
+// leaving a copy behind would leave the swipe in effect. If a removed LIKE
+// was behind a match, the match is removed too, as with UndoLastSwipe;
+// swipedID's own swipe on swiperID is untouched.
+func (s *InMemoryStore) RemoveSwipe(swiperID, swipedID uuid.UUID) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	removedLike := false
+	kept := s.swipes[:0]
+	for _, swipe := range s.swipes {
+		if swipe.SwiperID == swiperID && swipe.SwipedID == swipedID {
+			removedLike = removedLike || swipe.Action == models.SwipeActionLike
+			continue
+		}
+		kept = append(kept, swipe)
+	}
+	removed := len(kept) < len(s.swipes)
+	clear(s.swipes[len(kept):])
+	s.swipes = kept
+
+	if removedLike {
+		s.removePairMatchesLocked(swiperID, swipedID)
+	}
+	return removed
+}
+
+// countLikesLocked counts swiperID's LIKEs on swipedID in the log. The
+// caller must hold s.mu.
+func (s *InMemoryStore) countLikesLocked(swiperID, swipedID uuid.UUID) int {
+	count := 0
+	for _, swipe := range s.swipes {
+		if swipe.SwiperID == swiperID && swipe.SwipedID == swipedID && swipe.Action == models.SwipeActionLike {
+			count++
+		}
+	}
+	return count
+}
+
+// removePairMatchesLocked deletes every match between a and b, whichever
+// side each is on. The caller must hold s.mu.
+func (s *InMemoryStore) removePairMatchesLocked(a, b uuid.UUID) {
+	kept := s.matches[:0]
+	for _, match := range s.matches {
+		if (match.User1ID == a && match.User2ID == b) || (match.User1ID == b && match.User2ID == a) {
+			s.forgetMatchLocked(match)
+			continue
+		}
+		kept = append(kept, match)
+	}
+	clear(s.matches[len(kept):])
+	s.matches = kept
+}
+
 // ---------------------------------------------------------------------------
 // Match operations
 // ---------------------------------------------------------------------------
 
-// AddMatch records a new mutual match between two users.
-func (s *InMemoryStore) AddMatch(match models.Match) {
+// pairKey returns the key for the pair of users a and b in matchPairs.
+// The smaller ID always comes first, so (a, b) and (b, a) give the same
+// key. Arrays are comparable in Go, so [2]uuid.UUID works as a map key.
+func pairKey(a, b uuid.UUID) [2]uuid.UUID {
+	if bytes.Compare(b[:], a[:]) < 0 {
+		a, b = b, a
+	}
+	return [2]uuid.UUID{a, b}
+}
+
+// AddMatch records a new mutual match between two users and reports
+// whether it was stored. A pair can only be matched once: if the two users
+// are already matched, in either order, the store is left unchanged and
+// AddMatch returns false.
+//
+// Callers normally set match.ID; if it is left as uuid.Nil, a fresh ID is
+// assigned so every stored match can be looked up by ID.
+func (s *InMemoryStore) AddMatch(match models.Match) bool {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	key := pairKey(match.User1ID, match.User2ID)
+	if _, matched := s.matchPairs[key]; matched {
+		return false
+	}
+	if match.ID == uuid.Nil {
+		match.ID = uuid.New()
+	}
 	s.matches = append(s.matches, match)
+	s.matchPairs[key] = struct{}{}
+	return true
+}
+
+// AreMatched reports whether users a and b have a match, in either order.
+func (s *InMemoryStore) AreMatched(a, b uuid.UUID) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	_, matched := s.matchPairs[pairKey(a, b)]
+	return matched
+}
+
+// FindMatch returns the match between users a and b, whichever of them is
+// User1, or nil if they aren't matched. Like FindSwipe, it returns a
+// pointer to a copy.
+func (s *InMemoryStore) FindMatch(a, b uuid.UUID) *models.Match {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// matchPairs answers "are they matched?" without a scan, so unmatched
+	// pairs, the common case for a check, return at once.
+	if _, matched := s.matchPairs[pairKey(a, b)]; !matched {
+		return nil
+	}
+	for _, match := range s.matches {
+		if (match.User1ID == a && match.User2ID == b) || (match.User1ID == b && match.User2ID == a) {
+			result := match
+			return &result
+		}
+	}
+	return nil
+}
+
+// GetMatchByID retrieves a match by its ID, using the same (value, ok)
+// convention as GetUser.
+func (s *InMemoryStore) GetMatchByID(id uuid.UUID) (models.Match, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, match := range s.matches {
+		if match.ID == id {
+			return match, true
+		}
+	}
+	return models.Match{}, false
+}
+
+// RemoveMatch deletes the match with the given ID. It returns false if no
+// such match exists.
+func (s *InMemoryStore) RemoveMatch(id uuid.UUID) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, match := range s.matches {
+		if match.ID == id {
+			// Remove element i while preserving chronological order.
+			// append(s[:i], s[i+1:]...) shifts the tail left by one.
+			s.matches = append(s.matches[:i], s.matches[i+1:]...)
+			s.forgetMatchLocked(match)
+			return true
+		}
+	}
+	return false
+}
+
+// forgetMatchLocked clears the bookkeeping kept alongside a match that is
+// being removed from s.matches: its pair entry and both seen receipts. The
+// caller must hold s.mu.
+func (s *InMemoryStore) forgetMatchLocked(match models.Match) {
+	delete(s.matchPairs, pairKey(match.User1ID, match.User2ID))
+	delete(s.seenMatches[match.User1ID], match.ID)
+	delete(s.seenMatches[match.User2ID], match.ID)
+}
+
+// MarkMatchSeen records that userID has seen the match, so clients can stop
+// highlighting it for them. It only affects userID: their partner's receipt
+// is separate. Marking a match seen again is harmless. It returns false if
+// the match doesn't exist or userID isn't part of it.
+func (s *InMemoryStore) MarkMatchSeen(matchID, userID uuid.UUID) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, match := range s.matches {
+		if match.ID != matchID {
+			continue
+		}
+		if match.User1ID != userID && match.User2ID != userID {
+			return false
+		}
+		seen, ok := s.seenMatches[userID]
+		if !ok {
+			seen = make(map[uuid.UUID]struct{})
+			s.seenMatches[userID] = seen
+		}
+		seen[matchID] = struct{}{}
+		return true
+	}
+	return false
+}
+
+// GetSeenMatches returns the set of match IDs userID has marked seen. The
+// result is a copy, so callers may keep or change it.
+func (s *InMemoryStore) GetSeenMatches(userID uuid.UUID) map[uuid.UUID]struct{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make(map[uuid.UUID]struct{}, len(s.seenMatches[userID]))
+	for id := range s.seenMatches[userID] {
+		result[id] = struct{}{}
+	}
+	return result
+}
+
+// GetAllMatches returns a copy of every match record in creation order.
+func (s *InMemoryStore) GetAllMatches() []models.Match {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]models.Match, len(s.matches))
+	copy(result, s.matches)
+	return result
 }
 
 // GetMatchesForUser returns all matches involving the given user, regardless
 // of whether they are user1 or user2 in the match record.
 func (s *InMemoryStore) GetMatchesForUser(userID uuid.UUID) []models.Match {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 
 	var result []models.Match
 	for _, match := range s.matches {
@@ -186,12 +943,242 @@ func (s *InMemoryStore) GetMatchesForUser(userID uuid.UUID) []models.Match {
 	return result
 }
 
+// GetMatchesForUserBefore returns up to limit of the user's matches created
+// strictly before the given time, newest first. A zero before means no
+// upper bound, which gives the first page. Matches with equal timestamps
+// are ordered by ID so repeated calls agree on the order.
+func (s *InMemoryStore) GetMatchesForUserBefore(userID uuid.UUID, before time.Time, limit int) []models.Match {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []models.Match
+	for _, match := range s.matches {
+		if match.User1ID != userID && match.User2ID != userID {
+			continue
+		}
+		if !before.IsZero() && !match.Timestamp.Before(before) {
+			continue
+		}
+		result = append(result, match)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].Timestamp.Equal(result[j].Timestamp) {
+			return result[i].Timestamp.After(result[j].Timestamp)
+		}
+		return bytes.Compare(result[i].ID[:], result[j].ID[:]) < 0
+	})
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
+
+// ---------------------------------------------------------------------------
+// Block operations
+// ---------------------------------------------------------------------------
+
+// AddBlock records that block.BlockerID has blocked block.BlockedID, and
+// removes any match between the two in the same step, so no reader sees a
+// blocked pair still matched. It returns the stored block and true, or, if
+// the blocker had already blocked that user, the original block and false.
+func (s *InMemoryStore) AddBlock(block models.Block) (models.Block, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := [2]uuid.UUID{block.BlockerID, block.BlockedID}
+	if existing, ok := s.blocks[key]; ok {
+		return existing, false
+	}
+	s.blocks[key] = block
+	s.removePairMatchesLocked(block.BlockerID, block.BlockedID)
+	return block, true
+}
+
+// IsBlocked reports whether either user has blocked the other. A block
+// works both ways, so the argument order doesn't matter.
+func (s *InMemoryStore) IsBlocked(a, b uuid.UUID) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	_, ab := s.blocks[[2]uuid.UUID{a, b}]
+	_, ba := s.blocks[[2]uuid.UUID{b, a}]
+	return ab || ba
+}
+
+// GetBlockedBy returns the blocks userID has made, oldest first. Blocks
+// made against userID are not included.
+func (s *InMemoryStore) GetBlockedBy(userID uuid.UUID) []models.Block {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]models.Block, 0)
+	for key, block := range s.blocks {
+		if key[0] == userID {
+			result = append(result, block)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Timestamp.Before(result[j].Timestamp)
+	})
+	return result
+}
+
+// ---------------------------------------------------------------------------
+// Report operations
+// ---------------------------------------------------------------------------
+
+// AddReport stores an abuse report. A report with a nil ID is given a new
+// one. Reports are kept even if either user is later deleted, so the
+// trust-and-safety record stays complete.
+func (s *InMemoryStore) AddReport(report models.Report) models.Report {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if report.ID == uuid.Nil {
+		report.ID = uuid.New()
+	}
+	s.reports = append(s.reports, report)
+	return report
+}
+
+// GetReports returns a copy of every report in the order they were filed.
+func (s *InMemoryStore) GetReports() []models.Report {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]models.Report, len(s.reports))
+	copy(result, s.reports)
+	return result
+}
+
+// ---------------------------------------------------------------------------
+// Exposure tracking
+// ---------------------------------------------------------------------------
+
+// RecordExposure notes that the given user was served at the top of a feed.
+func (s *InMemoryStore) RecordExposure(userID uuid.UUID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.exposures[userID]++
+}
+
+// GetExposureCounts returns a copy of the per-user top-of-feed exposure
+// counts. Users who have never topped a feed are absent (a missing key reads
+// as zero, which is exactly what callers want).
+func (s *InMemoryStore) GetExposureCounts() map[uuid.UUID]int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// Copy the map so callers can't mutate the store's internal state.
+	result := make(map[uuid.UUID]int, len(s.exposures))
+	for id, count := range s.exposures {
+		result[id] = count
+	}
+	return result
+}
+
+// ---------------------------------------------------------------------------
+// Statistics
+// ---------------------------------------------------------------------------
+
+// Stats returns store-wide totals. Everything is counted under one read
+// lock, in a single pass over each collection, so the numbers agree with
+// each other: TotalSwipes is always Likes plus Passes, for example.
+func (s *InMemoryStore) Stats() models.Stats {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	stats := models.Stats{
+		TotalUsers:   len(s.users),
+		TotalSwipes:  len(s.swipes),
+		TotalMatches: len(s.matches),
+		UsersByZone:  make(map[string]int),
+	}
+	for _, user := range s.users {
+		stats.UsersByZone[user.ZoneID]++
+	}
+	for _, swipe := range s.swipes {
+		switch swipe.Action {
+		case models.SwipeActionLike:
+			stats.Likes++
+		case models.SwipeActionPass:
+			stats.Passes++
+		}
+	}
+	return stats
+}
+
+// ---------------------------------------------------------------------------
+// Integrity checks
+// ---------------------------------------------------------------------------
+
+// Validate scans the store for integrity problems and returns a description
+// of each one found; an empty result means the data is consistent. The
+// normal write paths never produce these problems, so they point at a bad
+// import or a bug. It checks for:
+//   - swipes where the swiper and swiped user are the same
+//   - swipes and matches that reference users who don't exist
+//   - matches between a user and themselves
+//   - more than one match for the same pair of users
+//
+// Swipes and matches are scanned in stored order, so the output is stable
+// between runs.
+func (s *InMemoryStore) Validate() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	issues := []string{}
+	missing := func(id uuid.UUID) bool {
+		_, ok := s.users[id]
+		return !ok
+	}
+
+	for i, swipe := range s.swipes {
+		if swipe.SwiperID == swipe.SwipedID {
+			issues = append(issues, fmt.Sprintf("swipe %d: user %s swiped on themselves", i, swipe.SwiperID))
+		}
+		if missing(swipe.SwiperID) {
+			issues = append(issues, fmt.Sprintf("swipe %d: swiper %s does not exist", i, swipe.SwiperID))
+		}
+		if missing(swipe.SwipedID) {
+			issues = append(issues, fmt.Sprintf("swipe %d: swiped user %s does not exist", i, swipe.SwipedID))
+		}
+	}
+
+	// AddMatch and LoadFromFile never store a second match for a pair.
+	// Checking the matches themselves, rather than trusting matchPairs,
+	// verifies that they don't.
+	pairs := make(map[[2]uuid.UUID]uuid.UUID)
+	for _, match := range s.matches {
+		if match.User1ID == match.User2ID {
+			issues = append(issues, fmt.Sprintf("match %s: user %s matched with themselves", match.ID, match.User1ID))
+		}
+		for _, id := range []uuid.UUID{match.User1ID, match.User2ID} {
+			if missing(id) {
+				issues = append(issues, fmt.Sprintf("match %s: user %s does not exist", match.ID, id))
+			}
+		}
+
+		key := pairKey(match.User1ID, match.User2ID)
+		if first, dup := pairs[key]; dup {
+			issues = append(issues, fmt.Sprintf("match %s: duplicates match %s for users %s and %s", match.ID, first, key[0], key[1]))
+			continue
+		}
+		pairs[key] = match.ID
+	}
+
+	return issues
+}
+
 // ---------------------------------------------------------------------------
 // Utility
 // ---------------------------------------------------------------------------
 
-// Reset clears all data from the store. This is primarily used in tests to
-// ensure each test starts with a clean slate (test isolation).
+// Reset clears all data from the store and removes any swipe log cap and
+// user cache. This is primarily used in tests to ensure each test starts
+// with a clean slate (test isolation).
 func (s *InMemoryStore) Reset() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -201,4 +1188,11 @@ func (s *InMemoryStore) Reset() {
 	s.users = make(map[uuid.UUID]models.User)
 	s.swipes = make([]models.Swipe, 0)
 	s.matches = make([]models.Match, 0)
+	s.matchPairs = make(map[[2]uuid.UUID]struct{})
+	s.seenMatches = make(map[uuid.UUID]map[uuid.UUID]struct{})
+	s.blocks = make(map[[2]uuid.UUID]models.Block)
+	s.reports = make([]models.Report, 0)
+	s.exposures = make(map[uuid.UUID]int)
+	s.maxSwipes = 0
+	s.userCache = nil
 }
@@ -13,6 +13,14 @@
 package store
 
 import (
+	"bytes"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -110,6 +118,186 @@ func TestGetAllUsers(t *testing.T) {
 	}
 }
 
+func TestUpdateUser(t *testing.T) {
+	s := resetStore(t)
+	user := makeUser("Alice", "zone-a")
+	s.AddUser(user)
+
+	user.Name = "Alicia"
+	if !s.UpdateUser(user) {
+		t.Fatal("expected update of existing user to succeed")
+	}
+	if got, _ := s.GetUser(user.ID); got.Name != "Alicia" {
+		t.Errorf("name: got %q, want Alicia", got.Name)
+	}
+
+	// Updating an unknown user must not create it.
+	ghost := makeUser("Ghost", "zone-a")
+	if s.UpdateUser(ghost) {
+		t.Error("expected update of unknown user to fail")
+	}
+	if _, exists := s.GetUser(ghost.ID); exists {
+		t.Error("UpdateUser should not create users")
+	}
+}
+
+func TestModifyUser(t *testing.T) {
+	s := resetStore(t)
+	user := makeUser("Alice", "zone-a")
+	s.AddUser(user)
+
+	// Concurrent changes to different fields must not undo each other, as
+	// a GetUser/UpdateUser pair would.
+	later := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			s.ModifyUser(user.ID, func(u *models.User) { u.Age++ })
+		}()
+		go func() {
+			defer wg.Done()
+			s.TouchUser(user.ID, later)
+		}()
+	}
+	wg.Wait()
+
+	got, _ := s.GetUser(user.ID)
+	if got.Age != user.Age+50 {
+		t.Errorf("age: got %d, want %d", got.Age, user.Age+50)
+	}
+	if !got.LastActiveAt.Equal(later) {
+		t.Errorf("LastActiveAt: got %v, want %v", got.LastActiveAt, later)
+	}
+
+	called := false
+	if _, ok := s.ModifyUser(uuid.New(), func(*models.User) { called = true }); ok || called {
+		t.Error("expected ModifyUser on an unknown user to fail without calling modify")
+	}
+}
+
+func TestTouchUser(t *testing.T) {
+	s := resetStore(t)
+	user := makeUser("Alice", "zone-a")
+	s.AddUser(user)
+
+	later := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	if !s.TouchUser(user.ID, later) {
+		t.Fatal("expected TouchUser on an existing user to succeed")
+	}
+	if got, _ := s.GetUser(user.ID); !got.LastActiveAt.Equal(later) {
+		t.Errorf("LastActiveAt: got %v, want %v", got.LastActiveAt, later)
+	}
+
+	// An older time leaves the timestamp alone.
+	s.TouchUser(user.ID, later.Add(-time.Hour))
+	if got, _ := s.GetUser(user.ID); !got.LastActiveAt.Equal(later) {
+		t.Errorf("LastActiveAt moved backwards to %v", got.LastActiveAt)
+	}
+
+	// A newer time within touchInterval is skipped; one past it is written.
+	s.TouchUser(user.ID, later.Add(touchInterval/2))
+	if got, _ := s.GetUser(user.ID); !got.LastActiveAt.Equal(later) {
+		t.Errorf("LastActiveAt updated within touchInterval, to %v", got.LastActiveAt)
+	}
+	s.TouchUser(user.ID, later.Add(touchInterval))
+	if got, _ := s.GetUser(user.ID); !got.LastActiveAt.Equal(later.Add(touchInterval)) {
+		t.Errorf("LastActiveAt: got %v, want %v", got.LastActiveAt, later.Add(touchInterval))
+	}
+
+	if s.TouchUser(uuid.New(), later) {
+		t.Error("expected TouchUser on an unknown user to fail")
+	}
+}
+
+func TestDeleteUser_Cascades(t *testing.T) {
+	s := resetStore(t)
+
+	alice := makeUser("Alice", "zone-a")
+	bob := makeUser("Bob", "zone-a")
+	carol := makeUser("Carol", "zone-a")
+	for _, u := range []models.User{alice, bob, carol} {
+		s.AddUser(u)
+	}
+	now := time.Now().UTC()
+	s.AddSwipe(models.Swipe{SwiperID: alice.ID, SwipedID: bob.ID, Action: models.SwipeActionLike, Timestamp: now})
+	s.AddSwipe(models.Swipe{SwiperID: bob.ID, SwipedID: alice.ID, Action: models.SwipeActionLike, Timestamp: now})
+	s.AddSwipe(models.Swipe{SwiperID: bob.ID, SwipedID: carol.ID, Action: models.SwipeActionPass, Timestamp: now})
+	s.AddMatch(models.Match{User1ID: alice.ID, User2ID: bob.ID, Timestamp: now})
+	s.RecordExposure(alice.ID)
+
+	if !s.DeleteUser(alice.ID) {
+		t.Fatal("expected DeleteUser to report success")
+	}
+
+	if _, exists := s.GetUser(alice.ID); exists {
+		t.Error("Alice should be gone")
+	}
+	if swipes := s.GetAllSwipes(); len(swipes) != 1 || swipes[0].SwipedID != carol.ID {
+		t.Errorf("expected only Bob's swipe on Carol to remain, got %+v", swipes)
+	}
+	if matches := s.GetMatchesForUser(bob.ID); len(matches) != 0 {
+		t.Errorf("expected Bob's match with Alice to be removed, got %d", len(matches))
+	}
+	if _, ok := s.GetExposureCounts()[alice.ID]; ok {
+		t.Error("expected Alice's exposure count to be removed")
+	}
+
+	if s.DeleteUser(alice.ID) {
+		t.Error("deleting a missing user should return false")
+	}
+}
+
+func TestGetUsers_OnlyReturnsFoundUsers(t *testing.T) {
+	s := resetStore(t)
+
+	alice := makeUser("Alice", "zone-a")
+	bob := makeUser("Bob", "zone-b")
+	s.AddUser(alice)
+	s.AddUser(bob)
+
+	missing := uuid.New()
+	got := s.GetUsers([]uuid.UUID{alice.ID, missing, bob.ID})
+
+	if len(got) != 2 {
+		t.Fatalf("expected 2 users, got %d", len(got))
+	}
+	if got[alice.ID].Name != "Alice" {
+		t.Errorf("expected Alice for %s, got %q", alice.ID, got[alice.ID].Name)
+	}
+	if got[bob.ID].Name != "Bob" {
+		t.Errorf("expected Bob for %s, got %q", bob.ID, got[bob.ID].Name)
+	}
+	if _, exists := got[missing]; exists {
+		t.Error("missing ID should not be present in the result")
+	}
+}
+
+func TestGetUsers_EmptyInput(t *testing.T) {
+	s := resetStore(t)
+	s.AddUser(makeUser("Alice", "zone-a"))
+
+	if got := s.GetUsers(nil); len(got) != 0 {
+		t.Errorf("expected empty map, got %d entries", len(got))
+	}
+}
+
+func TestGetUsersInZone(t *testing.T) {
+	s := resetStore(t)
+
+	s.AddUser(makeUser("Alice", "zone-a"))
+	s.AddUser(makeUser("Bob", "zone-a"))
+	s.AddUser(makeUser("Charlie", "zone-b"))
+
+	if users := s.GetUsersInZone("zone-a"); len(users) != 2 {
+		t.Errorf("expected 2 users in zone-a, got %d", len(users))
+	}
+	if users := s.GetUsersInZone("zone-c"); len(users) != 0 {
+		t.Errorf("expected 0 users in zone-c, got %d", len(users))
+	}
+}
+
 // ---------------------------------------------------------------------------
 // Swipe operation tests
 // ---------------------------------------------------------------------------
@@ -147,6 +335,336 @@ func TestAddSwipeAndGetByUser(t *testing.T) {
 	}
 }
 
+func TestGetSwipesByUserSorted(t *testing.T) {
+	s := resetStore(t)
+
+	alice := makeUser("Alice", "zone-a")
+	s.AddUser(alice)
+	base := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	// Log the swipes out of timestamp order, as a loaded file might, with
+	// two sharing a timestamp.
+	targets := make(map[uuid.UUID]string)
+	for _, swipe := range []struct {
+		name   string
+		offset time.Duration
+	}{
+		{"second", 2 * time.Minute},
+		{"first", time.Minute},
+		{"last", 3 * time.Minute},
+		{"tied-earlier", 2 * time.Minute},
+	} {
+		id := uuid.New()
+		targets[id] = swipe.name
+		s.AddSwipe(models.Swipe{SwiperID: alice.ID, SwipedID: id, Action: models.SwipeActionPass, Timestamp: base.Add(swipe.offset)})
+	}
+	// Someone else's swipe is never included.
+	s.AddSwipe(models.Swipe{SwiperID: uuid.New(), SwipedID: alice.ID, Action: models.SwipeActionLike, Timestamp: base})
+
+	tests := []struct {
+		name       string
+		descending bool
+		want       []string
+	}{
+		{"ascending", false, []string{"first", "second", "tied-earlier", "last"}},
+		{"descending", true, []string{"last", "tied-earlier", "second", "first"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, swipe := range s.GetSwipesByUserSorted(alice.ID, tt.descending) {
+				got = append(got, targets[swipe.SwipedID])
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUndoLastSwipe(t *testing.T) {
+	s := resetStore(t)
+
+	alice := makeUser("Alice", "zone-a")
+	bob := makeUser("Bob", "zone-a")
+	carol := makeUser("Carol", "zone-a")
+	for _, u := range []models.User{alice, bob, carol} {
+		s.AddUser(u)
+	}
+	now := time.Now().UTC()
+	s.AddSwipe(models.Swipe{SwiperID: alice.ID, SwipedID: carol.ID, Action: models.SwipeActionPass, Timestamp: now})
+	s.AddSwipe(models.Swipe{SwiperID: bob.ID, SwipedID: alice.ID, Action: models.SwipeActionLike, Timestamp: now.Add(time.Second)})
+	s.AddSwipe(models.Swipe{SwiperID: alice.ID, SwipedID: bob.ID, Action: models.SwipeActionLike, Timestamp: now.Add(2 * time.Second)})
+	s.AddMatch(models.Match{User1ID: alice.ID, User2ID: bob.ID, Timestamp: now.Add(2 * time.Second)})
+
+	// Alice's latest swipe is the LIKE on Bob; undoing it removes the match
+	// but leaves Bob's LIKE on Alice in place.
+	undone, ok := s.UndoLastSwipe(alice.ID)
+	if !ok || undone.SwipedID != bob.ID || undone.Action != models.SwipeActionLike {
+		t.Fatalf("expected Alice's LIKE on Bob to be undone, got %+v, %v", undone, ok)
+	}
+	if matches := s.GetAllMatches(); len(matches) != 0 {
+		t.Errorf("expected the match to be removed, got %d", len(matches))
+	}
+	if s.FindSwipe(bob.ID, alice.ID) == nil {
+		t.Error("Bob's LIKE on Alice should be kept")
+	}
+
+	// The next undo goes back to the PASS on Carol.
+	undone, ok = s.UndoLastSwipe(alice.ID)
+	if !ok || undone.SwipedID != carol.ID {
+		t.Fatalf("expected Alice's PASS on Carol to be undone, got %+v, %v", undone, ok)
+	}
+
+	if undone, ok := s.UndoLastSwipe(alice.ID); ok {
+		t.Errorf("expected nothing left to undo, got %+v", undone)
+	}
+	if swipes := s.GetAllSwipes(); len(swipes) != 1 {
+		t.Errorf("expected only Bob's swipe to remain, got %d", len(swipes))
+	}
+}
+
+func TestRemoveSwipe(t *testing.T) {
+	s := resetStore(t)
+
+	alice := makeUser("Alice", "zone-a")
+	bob := makeUser("Bob", "zone-a")
+	carol := makeUser("Carol", "zone-a")
+	for _, u := range []models.User{alice, bob, carol} {
+		s.AddUser(u)
+	}
+	now := time.Now().UTC()
+	s.AddSwipe(models.Swipe{SwiperID: alice.ID, SwipedID: bob.ID, Action: models.SwipeActionLike, Timestamp: now})
+	s.AddSwipe(models.Swipe{SwiperID: bob.ID, SwipedID: alice.ID, Action: models.SwipeActionLike, Timestamp: now})
+	s.AddSwipe(models.Swipe{SwiperID: alice.ID, SwipedID: bob.ID, Action: models.SwipeActionLike, Timestamp: now.Add(time.Second)})
+	s.AddSwipe(models.Swipe{SwiperID: alice.ID, SwipedID: carol.ID, Action: models.SwipeActionPass, Timestamp: now})
+	s.AddMatch(models.Match{User1ID: alice.ID, User2ID: bob.ID, Timestamp: now})
+
+	t.Run("without a match", func(t *testing.T) {
+		if !s.RemoveSwipe(alice.ID, carol.ID) {
+			t.Fatal("expected the PASS on Carol to be removed")
+		}
+		if s.FindSwipe(alice.ID, carol.ID) != nil {
+			t.Error("the PASS on Carol should be gone")
+		}
+		if s.FindMatch(alice.ID, bob.ID) == nil {
+			t.Error("an unrelated match should be kept")
+		}
+	})
+
+	t.Run("with a match", func(t *testing.T) {
+		// Both of Alice's LIKEs on Bob go, and the match with them.
+		if !s.RemoveSwipe(alice.ID, bob.ID) {
+			t.Fatal("expected the LIKE on Bob to be removed")
+		}
+		if s.FindSwipe(alice.ID, bob.ID) != nil {
+			t.Error("every LIKE on Bob should be gone")
+		}
+		if s.FindMatch(alice.ID, bob.ID) != nil {
+			t.Error("the match should be removed")
+		}
+		if s.FindSwipe(bob.ID, alice.ID) == nil {
+			t.Error("Bob's LIKE on Alice should be kept")
+		}
+	})
+
+	t.Run("no such swipe", func(t *testing.T) {
+		if s.RemoveSwipe(alice.ID, bob.ID) {
+			t.Error("expected false once the swipe is gone")
+		}
+		if swipes := s.GetAllSwipes(); len(swipes) != 1 {
+			t.Errorf("expected only Bob's swipe to remain, got %d", len(swipes))
+		}
+	})
+}
+
+func TestUndoLastSwipeUnlessMatched(t *testing.T) {
+	s := resetStore(t)
+
+	alice := makeUser("Alice", "zone-a")
+	bob := makeUser("Bob", "zone-a")
+	for _, u := range []models.User{alice, bob} {
+		s.AddUser(u)
+	}
+
+	if undone, matched := s.UndoLastSwipeUnlessMatched(alice.ID); undone != nil || matched {
+		t.Fatalf("expected nothing to undo, got %+v, %v", undone, matched)
+	}
+
+	now := time.Now().UTC()
+	s.AddSwipe(models.Swipe{SwiperID: bob.ID, SwipedID: alice.ID, Action: models.SwipeActionLike, Timestamp: now})
+	s.AddSwipe(models.Swipe{SwiperID: alice.ID, SwipedID: bob.ID, Action: models.SwipeActionLike, Timestamp: now.Add(time.Second)})
+	s.AddMatch(models.Match{User1ID: alice.ID, User2ID: bob.ID, Timestamp: now.Add(time.Second)})
+
+	// Undoing Alice's LIKE would remove the match, so it is refused and
+	// nothing changes.
+	undone, matched := s.UndoLastSwipeUnlessMatched(alice.ID)
+	if undone != nil || !matched {
+		t.Fatalf("expected the undo to be refused, got %+v, %v", undone, matched)
+	}
+	if s.FindMatch(alice.ID, bob.ID) == nil {
+		t.Error("the match should be kept")
+	}
+	if swipes := s.GetAllSwipes(); len(swipes) != 2 {
+		t.Errorf("expected both swipes to remain, got %d", len(swipes))
+	}
+
+	// A repeated LIKE isn't what keeps the match alive — the earlier LIKE
+	// still does — so it can be undone.
+	s.AddSwipe(models.Swipe{SwiperID: alice.ID, SwipedID: bob.ID, Action: models.SwipeActionLike, Timestamp: now.Add(2 * time.Second)})
+	undone, matched = s.UndoLastSwipeUnlessMatched(alice.ID)
+	if undone == nil || matched {
+		t.Fatalf("expected the repeated LIKE to be undone, got %+v, %v", undone, matched)
+	}
+	if s.FindMatch(alice.ID, bob.ID) == nil {
+		t.Error("the match should survive undoing a repeated LIKE")
+	}
+}
+
+func TestMaxSwipes_EvictsOldestPassesOnly(t *testing.T) {
+	s := resetStore(t)
+	s.SetMaxSwipes(3)
+
+	alice := makeUser("Alice", "zone-a")
+	bob := makeUser("Bob", "zone-a")
+	carol := makeUser("Carol", "zone-a")
+	dave := makeUser("Dave", "zone-a")
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// A matched pair of likes, then two old passes, then a pending like.
+	s.AddSwipe(models.Swipe{SwiperID: alice.ID, SwipedID: bob.ID, Action: models.SwipeActionLike, Timestamp: base})
+	s.AddSwipe(models.Swipe{SwiperID: bob.ID, SwipedID: alice.ID, Action: models.SwipeActionLike, Timestamp: base.Add(1 * time.Minute)})
+	s.AddMatch(models.Match{User1ID: alice.ID, User2ID: bob.ID, Timestamp: base.Add(1 * time.Minute)})
+	s.AddSwipe(models.Swipe{SwiperID: alice.ID, SwipedID: carol.ID, Action: models.SwipeActionPass, Timestamp: base.Add(2 * time.Minute)})
+	s.AddSwipe(models.Swipe{SwiperID: bob.ID, SwipedID: dave.ID, Action: models.SwipeActionPass, Timestamp: base.Add(3 * time.Minute)})
+	s.AddSwipe(models.Swipe{SwiperID: carol.ID, SwipedID: dave.ID, Action: models.SwipeActionLike, Timestamp: base.Add(4 * time.Minute)})
+
+	swipes := s.GetAllSwipes()
+	if len(swipes) != 3 {
+		t.Fatalf("expected 3 swipes after pruning, got %d", len(swipes))
+	}
+	for _, swipe := range swipes {
+		if swipe.Action != models.SwipeActionLike {
+			t.Errorf("expected only likes to survive, found %v from %v", swipe.Action, swipe.Timestamp)
+		}
+	}
+
+	// The match-critical and pending likes are still findable, in order.
+	if s.FindSwipe(alice.ID, bob.ID) == nil || s.FindSwipe(bob.ID, alice.ID) == nil {
+		t.Error("likes behind a match were evicted")
+	}
+	if s.FindSwipe(carol.ID, dave.ID) == nil {
+		t.Error("pending like was evicted")
+	}
+	if !swipes[0].Timestamp.Before(swipes[2].Timestamp) {
+		t.Error("expected chronological order to be preserved")
+	}
+}
+
+func TestMaxSwipes_KeepsNewestPasses(t *testing.T) {
+	s := resetStore(t)
+	s.SetMaxSwipes(2)
+
+	alice := makeUser("Alice", "zone-a")
+	targets := []models.User{makeUser("B", "zone-a"), makeUser("C", "zone-a"), makeUser("D", "zone-a")}
+	for _, target := range targets {
+		s.AddSwipe(models.Swipe{SwiperID: alice.ID, SwipedID: target.ID, Action: models.SwipeActionPass, Timestamp: time.Now().UTC()})
+	}
+
+	if s.FindSwipe(alice.ID, targets[0].ID) != nil {
+		t.Error("expected the oldest pass to be evicted")
+	}
+	for _, target := range targets[1:] {
+		if s.FindSwipe(alice.ID, target.ID) == nil {
+			t.Errorf("expected pass on %s to be kept", target.Name)
+		}
+	}
+}
+
+func TestMaxSwipes_AllLikesMayExceedCap(t *testing.T) {
+	s := resetStore(t)
+	s.SetMaxSwipes(1)
+
+	alice := makeUser("Alice", "zone-a")
+	for i := 0; i < 3; i++ {
+		s.AddSwipe(models.Swipe{SwiperID: alice.ID, SwipedID: uuid.New(), Action: models.SwipeActionLike, Timestamp: time.Now().UTC()})
+	}
+
+	if n := len(s.GetAllSwipes()); n != 3 {
+		t.Errorf("expected all 3 likes kept despite the cap, got %d", n)
+	}
+}
+
+func TestGetLikesReceived(t *testing.T) {
+	s := resetStore(t)
+
+	alice := makeUser("Alice", "zone-a")
+	bob := makeUser("Bob", "zone-a")
+	charlie := makeUser("Charlie", "zone-a")
+
+	now := time.Now().UTC()
+	s.AddSwipe(models.Swipe{SwiperID: bob.ID, SwipedID: alice.ID, Action: models.SwipeActionLike, Timestamp: now})
+	s.AddSwipe(models.Swipe{SwiperID: charlie.ID, SwipedID: alice.ID, Action: models.SwipeActionPass, Timestamp: now})
+	s.AddSwipe(models.Swipe{SwiperID: alice.ID, SwipedID: bob.ID, Action: models.SwipeActionLike, Timestamp: now})
+
+	likes := s.GetLikesReceived(alice.ID)
+	if len(likes) != 1 {
+		t.Fatalf("expected 1 like received, got %d", len(likes))
+	}
+	if likes[0].SwiperID != bob.ID {
+		t.Error("expected the like to come from Bob")
+	}
+}
+
+func TestCountLikesReceived(t *testing.T) {
+	s := resetStore(t)
+
+	alice := makeUser("Alice", "zone-a")
+	bob := makeUser("Bob", "zone-a")
+	carol := makeUser("Carol", "zone-a")
+	dave := makeUser("Dave", "zone-a")
+
+	now := time.Now().UTC()
+	s.AddSwipe(models.Swipe{SwiperID: alice.ID, SwipedID: bob.ID, Action: models.SwipeActionLike, Timestamp: now})
+	s.AddSwipe(models.Swipe{SwiperID: carol.ID, SwipedID: bob.ID, Action: models.SwipeActionLike, Timestamp: now})
+	s.AddSwipe(models.Swipe{SwiperID: alice.ID, SwipedID: carol.ID, Action: models.SwipeActionPass, Timestamp: now})
+	s.AddSwipe(models.Swipe{SwiperID: bob.ID, SwipedID: dave.ID, Action: models.SwipeActionLike, Timestamp: now})
+
+	// Dave's like isn't asked about, so he has no entry.
+	got := s.CountLikesReceived([]uuid.UUID{bob.ID, carol.ID})
+	want := map[uuid.UUID]int{bob.ID: 2, carol.ID: 0}
+	if !maps.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestCountOutstandingLikes(t *testing.T) {
+	s := resetStore(t)
+
+	alice := makeUser("Alice", "zone-a")
+	bob := makeUser("Bob", "zone-a")
+	carol := makeUser("Carol", "zone-a")
+	dave := makeUser("Dave", "zone-a")
+
+	now := time.Now().UTC()
+	like := func(to uuid.UUID, action models.SwipeAction) {
+		s.AddSwipe(models.Swipe{SwiperID: alice.ID, SwipedID: to, Action: action, Timestamp: now})
+	}
+	// Bob is liked twice but counts once.
+	like(bob.ID, models.SwipeActionLike)
+	like(bob.ID, models.SwipeActionLike)
+	// Carol was liked, then passed: that like can't lead to a match.
+	like(carol.ID, models.SwipeActionLike)
+	like(carol.ID, models.SwipeActionPass)
+	// Dave is liked and matched.
+	like(dave.ID, models.SwipeActionLike)
+	s.AddMatch(models.Match{ID: uuid.New(), User1ID: alice.ID, User2ID: dave.ID, Timestamp: now})
+
+	if n := s.CountOutstandingLikes(alice.ID); n != 1 {
+		t.Errorf("outstanding likes: got %d, want 1 (Bob)", n)
+	}
+}
+
 func TestFindSwipe(t *testing.T) {
 	s := resetStore(t)
 
@@ -175,6 +693,68 @@ func TestFindSwipe(t *testing.T) {
 	if notFound != nil {
 		t.Error("expected no swipe from Bob to Alice")
 	}
+
+	// A later swipe on the same person replaces the earlier one.
+	s.AddSwipe(models.Swipe{SwiperID: alice.ID, SwipedID: bob.ID, Action: models.SwipeActionPass, Timestamp: time.Now().UTC()})
+	if found := s.FindSwipe(alice.ID, bob.ID); found == nil || found.Action != models.SwipeActionPass {
+		t.Errorf("expected the latest swipe (PASS), got %v", found)
+	}
+}
+
+func TestSnapshotForFeed(t *testing.T) {
+	s := resetStore(t)
+
+	alice := makeUser("Alice", "zone-a")
+	bob := makeUser("Bob", "zone-a")
+	s.AddUser(alice)
+	s.AddUser(bob)
+	s.AddSwipe(models.Swipe{
+		SwiperID:  alice.ID,
+		SwipedID:  bob.ID,
+		Action:    models.SwipeActionPass,
+		Timestamp: time.Now().UTC(),
+	})
+
+	snap, ok := s.SnapshotForFeed(alice.ID, time.Time{})
+	if !ok {
+		t.Fatal("expected snapshot for existing user")
+	}
+	if snap.User.ID != alice.ID {
+		t.Errorf("snapshot user: got %s, want %s", snap.User.ID, alice.ID)
+	}
+	if len(snap.Candidates) != 2 {
+		t.Errorf("expected 2 candidates, got %d", len(snap.Candidates))
+	}
+	if _, seen := snap.Seen[bob.ID]; !seen {
+		t.Error("expected Bob in Alice's seen-set")
+	}
+
+	if _, ok := s.SnapshotForFeed(uuid.New(), time.Time{}); ok {
+		t.Error("expected no snapshot for unknown user")
+	}
+}
+
+func TestSnapshotForFeed_PassesSince(t *testing.T) {
+	s := resetStore(t)
+
+	alice := makeUser("Alice", "zone-a")
+	bob := makeUser("Bob", "zone-a")
+	carol := makeUser("Carol", "zone-a")
+	for _, u := range []models.User{alice, bob, carol} {
+		s.AddUser(u)
+	}
+	cutoff := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	old := cutoff.Add(-time.Hour)
+	s.AddSwipe(models.Swipe{SwiperID: alice.ID, SwipedID: bob.ID, Action: models.SwipeActionPass, Timestamp: old})
+	s.AddSwipe(models.Swipe{SwiperID: alice.ID, SwipedID: carol.ID, Action: models.SwipeActionLike, Timestamp: old})
+
+	snap, _ := s.SnapshotForFeed(alice.ID, cutoff)
+	if _, seen := snap.Seen[bob.ID]; seen {
+		t.Error("a PASS before the cutoff should not count as seen")
+	}
+	if _, seen := snap.Seen[carol.ID]; !seen {
+		t.Error("a LIKE should count as seen however old it is")
+	}
 }
 
 // ---------------------------------------------------------------------------
@@ -215,30 +795,181 @@ func TestAddMatchAndGetForUser(t *testing.T) {
 	}
 }
 
-// ---------------------------------------------------------------------------
-// Reset tests
-// ---------------------------------------------------------------------------
-
-func TestReset_ClearsAllData(t *testing.T) {
+func TestAddMatch_AssignsMissingID(t *testing.T) {
 	s := resetStore(t)
+	alice := makeUser("Alice", "zone-a")
 
-	// Add some data.
-	user := makeUser("Alice", "zone-a")
-	s.AddUser(user)
-	s.AddSwipe(models.Swipe{
-		SwiperID:  user.ID,
-		SwipedID:  uuid.New(),
-		Action:    models.SwipeActionPass,
-		Timestamp: time.Now().UTC(),
-	})
-	s.AddMatch(models.Match{
-		User1ID:   user.ID,
-		User2ID:   uuid.New(),
-		Timestamp: time.Now().UTC(),
-	})
+	s.AddMatch(models.Match{User1ID: alice.ID, User2ID: uuid.New(), Timestamp: time.Now().UTC()})
 
-	// Reset should clear everything.
-	s.Reset()
+	matches := s.GetMatchesForUser(alice.ID)
+	if len(matches) != 1 || matches[0].ID == uuid.Nil {
+		t.Fatalf("expected one match with an assigned ID, got %+v", matches)
+	}
+}
+
+func TestGetMatchByIDAndRemoveMatch(t *testing.T) {
+	s := resetStore(t)
+
+	alice := makeUser("Alice", "zone-a")
+	match := models.Match{ID: uuid.New(), User1ID: alice.ID, User2ID: uuid.New(), Timestamp: time.Now().UTC()}
+	other := models.Match{ID: uuid.New(), User1ID: alice.ID, User2ID: uuid.New(), Timestamp: time.Now().UTC()}
+	s.AddMatch(match)
+	s.AddMatch(other)
+
+	got, exists := s.GetMatchByID(match.ID)
+	if !exists || got.User2ID != match.User2ID {
+		t.Fatalf("expected to find match %s", match.ID)
+	}
+
+	if !s.RemoveMatch(match.ID) {
+		t.Fatal("expected RemoveMatch to report success")
+	}
+	if _, exists := s.GetMatchByID(match.ID); exists {
+		t.Error("match should be gone after removal")
+	}
+	if _, exists := s.GetMatchByID(other.ID); !exists {
+		t.Error("unrelated match should survive removal")
+	}
+	if s.RemoveMatch(match.ID) {
+		t.Error("removing a missing match should return false")
+	}
+}
+
+func TestFindMatch(t *testing.T) {
+	s := resetStore(t)
+
+	alice, bob, carol := uuid.New(), uuid.New(), uuid.New()
+	match := models.Match{ID: uuid.New(), User1ID: alice, User2ID: bob, Timestamp: time.Now().UTC()}
+	s.AddMatch(match)
+
+	// Either ordering finds the match.
+	for _, pair := range [][2]uuid.UUID{{alice, bob}, {bob, alice}} {
+		got := s.FindMatch(pair[0], pair[1])
+		if got == nil || got.ID != match.ID {
+			t.Errorf("FindMatch(%s, %s): got %v, want match %s", pair[0], pair[1], got, match.ID)
+		}
+	}
+	if got := s.FindMatch(alice, carol); got != nil {
+		t.Errorf("expected no match for an unmatched pair, got %v", got)
+	}
+
+	s.RemoveMatch(match.ID)
+	if got := s.FindMatch(alice, bob); got != nil {
+		t.Errorf("expected no match after removal, got %v", got)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Exposure tracking tests
+// ---------------------------------------------------------------------------
+
+func TestRecordExposure(t *testing.T) {
+	s := resetStore(t)
+	id := uuid.New()
+
+	s.RecordExposure(id)
+	s.RecordExposure(id)
+
+	counts := s.GetExposureCounts()
+	if counts[id] != 2 {
+		t.Errorf("expected 2 exposures, got %d", counts[id])
+	}
+
+	// Mutating the returned map must not affect the store.
+	counts[id] = 100
+	if got := s.GetExposureCounts()[id]; got != 2 {
+		t.Errorf("store exposure count changed via returned map: got %d", got)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Reset tests
+// ---------------------------------------------------------------------------
+
+func TestValidate_CleanStore(t *testing.T) {
+	s := resetStore(t)
+
+	alice := makeUser("Alice", "zone-a")
+	bob := makeUser("Bob", "zone-a")
+	s.AddUser(alice)
+	s.AddUser(bob)
+	s.AddSwipe(models.Swipe{SwiperID: alice.ID, SwipedID: bob.ID, Action: models.SwipeActionLike, Timestamp: time.Now().UTC()})
+	s.AddSwipe(models.Swipe{SwiperID: bob.ID, SwipedID: alice.ID, Action: models.SwipeActionLike, Timestamp: time.Now().UTC()})
+	s.AddMatch(models.Match{User1ID: alice.ID, User2ID: bob.ID, Timestamp: time.Now().UTC()})
+
+	if issues := s.Validate(); len(issues) != 0 {
+		t.Errorf("expected no issues, got %v", issues)
+	}
+}
+
+func TestValidate_ReportsInconsistencies(t *testing.T) {
+	s := resetStore(t)
+
+	alice := makeUser("Alice", "zone-a")
+	bob := makeUser("Bob", "zone-a")
+	s.AddUser(alice)
+	s.AddUser(bob)
+	ghost := uuid.New() // Never added to the store.
+	now := time.Now().UTC()
+
+	s.AddSwipe(models.Swipe{SwiperID: alice.ID, SwipedID: alice.ID, Action: models.SwipeActionLike, Timestamp: now})
+	s.AddSwipe(models.Swipe{SwiperID: ghost, SwipedID: bob.ID, Action: models.SwipeActionPass, Timestamp: now})
+	s.AddMatch(models.Match{User1ID: alice.ID, User2ID: ghost, Timestamp: now})
+	s.AddMatch(models.Match{User1ID: alice.ID, User2ID: bob.ID, Timestamp: now})
+	// AddMatch refuses a second match for a pair, so plant one directly to
+	// check that Validate would still catch it.
+	dup := models.Match{ID: uuid.New(), User1ID: bob.ID, User2ID: alice.ID, Timestamp: now} // Same pair, reversed.
+	if s.AddMatch(dup) {
+		t.Fatal("expected AddMatch to refuse a duplicate pair")
+	}
+	s.matches = append(s.matches, dup)
+
+	issues := s.Validate()
+
+	tests := []struct {
+		name     string
+		contains string
+	}{
+		{"self swipe", "swiped on themselves"},
+		{"swipe from missing user", "swiper " + ghost.String() + " does not exist"},
+		{"match with missing user", "user " + ghost.String() + " does not exist"},
+		{"duplicate match", "duplicates match"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, issue := range issues {
+				if strings.Contains(issue, tc.contains) {
+					return
+				}
+			}
+			t.Errorf("expected an issue containing %q, got %v", tc.contains, issues)
+		})
+	}
+	if len(issues) != 4 {
+		t.Errorf("expected exactly 4 issues, got %d: %v", len(issues), issues)
+	}
+}
+
+func TestReset_ClearsAllData(t *testing.T) {
+	s := resetStore(t)
+
+	// Add some data.
+	user := makeUser("Alice", "zone-a")
+	s.AddUser(user)
+	s.AddSwipe(models.Swipe{
+		SwiperID:  user.ID,
+		SwipedID:  uuid.New(),
+		Action:    models.SwipeActionPass,
+		Timestamp: time.Now().UTC(),
+	})
+	s.AddMatch(models.Match{
+		User1ID:   user.ID,
+		User2ID:   uuid.New(),
+		Timestamp: time.Now().UTC(),
+	})
+
+	// Reset should clear everything.
+	s.Reset()
 
 	if users := s.GetAllUsers(); len(users) != 0 {
 		t.Errorf("expected 0 users after reset, got %d", len(users))
@@ -250,3 +981,745 @@ func TestReset_ClearsAllData(t *testing.T) {
 		t.Errorf("expected 0 matches after reset, got %d", len(matches))
 	}
 }
+
+// ---------------------------------------------------------------------------
+// User cache tests
+// ---------------------------------------------------------------------------
+
+func TestUserCache_NoStaleReadsAfterMutation(t *testing.T) {
+	s := resetStore(t)
+	s.SetUserCacheSize(2)
+
+	alice := makeUser("Alice", "zone-a")
+	s.AddUser(alice)
+
+	// Warm the cache, then change the user behind it.
+	if got, _ := s.GetUser(alice.ID); got.Name != "Alice" {
+		t.Fatalf("expected Alice, got %q", got.Name)
+	}
+	alice.Name = "Alicia"
+	s.UpdateUser(alice)
+	if got, _ := s.GetUser(alice.ID); got.Name != "Alicia" {
+		t.Errorf("after UpdateUser: expected Alicia, got %q", got.Name)
+	}
+
+	// AddUser over an existing ID also replaces the cached copy.
+	alice.Name = "Ali"
+	s.AddUser(alice)
+	if got, _ := s.GetUser(alice.ID); got.Name != "Ali" {
+		t.Errorf("after AddUser: expected Ali, got %q", got.Name)
+	}
+
+	s.DeleteUser(alice.ID)
+	if _, exists := s.GetUser(alice.ID); exists {
+		t.Error("after DeleteUser: expected the cached user to be gone")
+	}
+}
+
+func TestUserCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	c := newUserCache(2)
+	a, b, d := makeUser("A", "z"), makeUser("B", "z"), makeUser("D", "z")
+
+	c.put(a)
+	c.put(b)
+	c.get(a.ID) // A is now more recent than B.
+	c.put(d)    // Over capacity: B is evicted.
+
+	if _, ok := c.get(b.ID); ok {
+		t.Error("expected B to be evicted")
+	}
+	for _, u := range []models.User{a, d} {
+		if _, ok := c.get(u.ID); !ok {
+			t.Errorf("expected %s to still be cached", u.Name)
+		}
+	}
+}
+
+func TestUserCache_ResultsMatchUncached(t *testing.T) {
+	s := resetStore(t)
+	users := make([]models.User, 5)
+	for i := range users {
+		users[i] = makeUser(fmt.Sprintf("User%d", i), "zone-a")
+		s.AddUser(users[i])
+	}
+
+	// A cache smaller than the data set exercises hits, misses, and
+	// evictions; every lookup must agree with the map.
+	s.SetUserCacheSize(2)
+	for round := 0; round < 3; round++ {
+		for _, want := range users {
+			got, exists := s.GetUser(want.ID)
+			if !exists || got.Name != want.Name {
+				t.Fatalf("round %d: expected %s, got %+v (exists=%v)", round, want.Name, got, exists)
+			}
+		}
+		if _, exists := s.GetUser(uuid.New()); exists {
+			t.Fatal("unknown ID should not be found")
+		}
+	}
+}
+
+func TestUserCache_ConcurrentReadersAndWriters(t *testing.T) {
+	s := resetStore(t)
+	s.SetUserCacheSize(8)
+
+	users := make([]models.User, 16)
+	for i := range users {
+		users[i] = makeUser(fmt.Sprintf("User%d", i), "zone-a")
+		s.AddUser(users[i])
+	}
+
+	// Readers share the store's read lock and all touch the cache, so
+	// this is mainly a check for the race detector (go test -race).
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				u := users[(g+i)%len(users)]
+				if g == 0 {
+					u.Age = i
+					s.UpdateUser(u)
+					continue
+				}
+				if _, exists := s.GetUser(u.ID); !exists {
+					t.Errorf("user %s disappeared", u.Name)
+					return
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	// After the writer finishes, cached reads must show its last write.
+	for i := range users {
+		want, _ := s.GetUser(users[i].ID)
+		s.SetUserCacheSize(0)
+		got, _ := s.GetUser(users[i].ID)
+		s.SetUserCacheSize(8)
+		if got.Age != want.Age {
+			t.Errorf("%s: cached age %d, stored age %d", users[i].Name, want.Age, got.Age)
+		}
+	}
+}
+
+func BenchmarkGetUser(b *testing.B) {
+	for _, cacheSize := range []int{0, 1000} {
+		b.Run(fmt.Sprintf("cache=%d", cacheSize), func(b *testing.B) {
+			s := GetStore()
+			s.Reset()
+			b.Cleanup(s.Reset)
+			s.SetUserCacheSize(cacheSize)
+
+			ids := make([]uuid.UUID, 10000)
+			for i := range ids {
+				user := makeUser("User", "zone-a")
+				s.AddUser(user)
+				ids[i] = user.ID
+			}
+
+			// Most reads hit a small hot set, as with popular profiles.
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				s.GetUser(ids[i%500])
+			}
+		})
+	}
+}
+
+func TestGetMatchesForUserBefore(t *testing.T) {
+	s := resetStore(t)
+
+	alice := makeUser("Alice", "zone-a")
+	s.AddUser(alice)
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	// Stored out of time order, with two matches sharing a timestamp.
+	tied1, tied2 := uuid.New(), uuid.New()
+	if bytes.Compare(tied1[:], tied2[:]) > 0 {
+		tied1, tied2 = tied2, tied1
+	}
+	newest := models.Match{ID: uuid.New(), User1ID: alice.ID, User2ID: uuid.New(), Timestamp: base.Add(2 * time.Hour)}
+	oldest := models.Match{ID: uuid.New(), User1ID: uuid.New(), User2ID: alice.ID, Timestamp: base}
+	s.AddMatch(oldest)
+	s.AddMatch(models.Match{ID: tied2, User1ID: alice.ID, User2ID: uuid.New(), Timestamp: base.Add(time.Hour)})
+	s.AddMatch(newest)
+	s.AddMatch(models.Match{ID: tied1, User1ID: alice.ID, User2ID: uuid.New(), Timestamp: base.Add(time.Hour)})
+	s.AddMatch(models.Match{ID: uuid.New(), User1ID: uuid.New(), User2ID: uuid.New(), Timestamp: base}) // not Alice's
+
+	tests := []struct {
+		name   string
+		before time.Time
+		limit  int
+		want   []uuid.UUID
+	}{
+		{"no bound, newest first, ties by ID", time.Time{}, 0, []uuid.UUID{newest.ID, tied1, tied2, oldest.ID}},
+		{"limit", time.Time{}, 2, []uuid.UUID{newest.ID, tied1}},
+		{"strictly before", base.Add(2 * time.Hour), 0, []uuid.UUID{tied1, tied2, oldest.ID}},
+		{"nothing older", base, 0, nil},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := s.GetMatchesForUserBefore(alice.ID, tc.before, tc.limit)
+			if len(got) != len(tc.want) {
+				t.Fatalf("expected %d matches, got %d", len(tc.want), len(got))
+			}
+			for i, id := range tc.want {
+				if got[i].ID != id {
+					t.Errorf("position %d: expected %s, got %s", i, id, got[i].ID)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkConcurrentReads measures read throughput when many goroutines
+// query the store at once, as a burst of feed requests does. Read methods
+// take a shared lock, so readers don't wait on each other.
+func BenchmarkConcurrentReads(b *testing.B) {
+	s := GetStore()
+	s.Reset()
+	b.Cleanup(s.Reset)
+
+	users := make([]models.User, 1000)
+	for i := range users {
+		users[i] = makeUser("User", "zone-a")
+		s.AddUser(users[i])
+	}
+	now := time.Now().UTC()
+	for i := 0; i < 5000; i++ {
+		s.AddSwipe(models.Swipe{
+			SwiperID:  users[i%len(users)].ID,
+			SwipedID:  users[(i*7+1)%len(users)].ID,
+			Action:    models.SwipeActionPass,
+			Timestamp: now,
+		})
+	}
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			user := users[i%len(users)]
+			s.GetUser(user.ID)
+			s.GetSwipesByUser(user.ID)
+			s.GetMatchesForUser(user.ID)
+			i++
+		}
+	})
+}
+
+// ---------------------------------------------------------------------------
+// Persistence tests
+// ---------------------------------------------------------------------------
+
+func TestSaveAndLoadFile_RoundTrip(t *testing.T) {
+	s := resetStore(t)
+	// t.TempDir is removed automatically when the test finishes.
+	path := filepath.Join(t.TempDir(), "store.json")
+
+	alice := makeUser("Alice", "zone-a")
+	alice.Tags = []string{"hiking"}
+	bob := makeUser("Bob", "zone-a")
+	s.AddUser(alice)
+	s.AddUser(bob)
+	now := time.Now().UTC()
+	s.AddSwipe(models.Swipe{SwiperID: alice.ID, SwipedID: bob.ID, Action: models.SwipeActionLike, Timestamp: now})
+	s.AddSwipe(models.Swipe{SwiperID: bob.ID, SwipedID: alice.ID, Action: models.SwipeActionLike, Timestamp: now.Add(time.Second)})
+	s.AddMatch(models.Match{User1ID: bob.ID, User2ID: alice.ID, Timestamp: now.Add(time.Second)})
+
+	wantSwipes := s.GetAllSwipes()
+	wantMatches := s.GetAllMatches()
+
+	if err := s.SaveToFile(path); err != nil {
+		t.Fatalf("SaveToFile: %v", err)
+	}
+
+	s.Reset()
+	if err := s.LoadFromFile(path); err != nil {
+		t.Fatalf("LoadFromFile: %v", err)
+	}
+
+	got, ok := s.GetUser(alice.ID)
+	if !ok {
+		t.Fatal("expected Alice to be loaded")
+	}
+	if got.Name != "Alice" || len(got.Tags) != 1 || got.Tags[0] != "hiking" {
+		t.Errorf("Alice loaded as %+v", got)
+	}
+	if _, ok := s.GetUser(bob.ID); !ok {
+		t.Error("expected Bob to be loaded")
+	}
+
+	gotSwipes := s.GetAllSwipes()
+	if len(gotSwipes) != len(wantSwipes) {
+		t.Fatalf("expected %d swipes, got %d", len(wantSwipes), len(gotSwipes))
+	}
+	for i := range wantSwipes {
+		// time.Time values must be compared with Equal, not ==.
+		if gotSwipes[i].SwiperID != wantSwipes[i].SwiperID || !gotSwipes[i].Timestamp.Equal(wantSwipes[i].Timestamp) {
+			t.Errorf("swipe %d: expected %+v, got %+v", i, wantSwipes[i], gotSwipes[i])
+		}
+	}
+
+	gotMatches := s.GetAllMatches()
+	if len(gotMatches) != 1 || gotMatches[0].ID != wantMatches[0].ID {
+		t.Errorf("expected match %s, got %+v", wantMatches[0].ID, gotMatches)
+	}
+}
+
+func TestLoadFromFile_MissingFileStartsEmpty(t *testing.T) {
+	s := resetStore(t)
+
+	if err := s.LoadFromFile(filepath.Join(t.TempDir(), "missing.json")); err != nil {
+		t.Fatalf("expected no error for a missing file, got %v", err)
+	}
+	if len(s.GetAllUsers()) != 0 || len(s.GetAllSwipes()) != 0 || len(s.GetAllMatches()) != 0 {
+		t.Error("expected the store to stay empty")
+	}
+}
+
+func TestLoadFromFile_CorruptFileLeavesStoreUnchanged(t *testing.T) {
+	s := resetStore(t)
+	path := filepath.Join(t.TempDir(), "store.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	alice := makeUser("Alice", "zone-a")
+	s.AddUser(alice)
+
+	if err := s.LoadFromFile(path); err == nil {
+		t.Fatal("expected an error for a corrupt file")
+	}
+	if _, ok := s.GetUser(alice.ID); !ok {
+		t.Error("expected existing data to survive a failed load")
+	}
+}
+
+func TestLoadFromFile_ClearsUserCache(t *testing.T) {
+	s := resetStore(t)
+	s.SetUserCacheSize(10)
+	path := filepath.Join(t.TempDir(), "store.json")
+
+	alice := makeUser("Alice", "zone-a")
+	s.AddUser(alice)
+	if err := s.SaveToFile(path); err != nil {
+		t.Fatalf("SaveToFile: %v", err)
+	}
+
+	// Cache a renamed Alice, then load the file with the original name.
+	renamed := alice
+	renamed.Name = "Alicia"
+	s.UpdateUser(renamed)
+	s.GetUser(alice.ID)
+
+	if err := s.LoadFromFile(path); err != nil {
+		t.Fatalf("LoadFromFile: %v", err)
+	}
+	if got, _ := s.GetUser(alice.ID); got.Name != "Alice" {
+		t.Errorf("expected the loaded name Alice, got %q (stale cache)", got.Name)
+	}
+}
+
+func TestSaveToFile_ReplacesExistingFile(t *testing.T) {
+	s := resetStore(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "store.json")
+
+	s.AddUser(makeUser("Alice", "zone-a"))
+	if err := s.SaveToFile(path); err != nil {
+		t.Fatalf("first SaveToFile: %v", err)
+	}
+	s.AddUser(makeUser("Bob", "zone-a"))
+	if err := s.SaveToFile(path); err != nil {
+		t.Fatalf("second SaveToFile: %v", err)
+	}
+
+	s.Reset()
+	if err := s.LoadFromFile(path); err != nil {
+		t.Fatalf("LoadFromFile: %v", err)
+	}
+	if n := len(s.GetAllUsers()); n != 2 {
+		t.Errorf("expected 2 users from the latest save, got %d", n)
+	}
+
+	// The temporary file used for the atomic rename must not be left behind.
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected only store.json in the directory, got %d entries", len(entries))
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Match pair set tests
+// ---------------------------------------------------------------------------
+
+// assertPairsInSync fails the test unless matchPairs holds exactly the
+// pairs of the stored matches.
+func assertPairsInSync(t *testing.T, s *InMemoryStore) {
+	t.Helper()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	want := make(map[[2]uuid.UUID]struct{}, len(s.matches))
+	for _, match := range s.matches {
+		want[pairKey(match.User1ID, match.User2ID)] = struct{}{}
+	}
+	if len(want) != len(s.matches) {
+		t.Errorf("matches contain a duplicate pair: %d matches, %d pairs", len(s.matches), len(want))
+	}
+	if len(s.matchPairs) != len(want) {
+		t.Fatalf("expected %d pairs in the set, got %d", len(want), len(s.matchPairs))
+	}
+	for key := range want {
+		if _, ok := s.matchPairs[key]; !ok {
+			t.Errorf("pair %v is matched but missing from the set", key)
+		}
+	}
+}
+
+func TestMatchPairs_AddRejectsDuplicatePair(t *testing.T) {
+	s := resetStore(t)
+	alice, bob := uuid.New(), uuid.New()
+	now := time.Now().UTC()
+
+	if !s.AddMatch(models.Match{User1ID: alice, User2ID: bob, Timestamp: now}) {
+		t.Fatal("expected the first match to be added")
+	}
+	// Either order is the same pair.
+	if s.AddMatch(models.Match{User1ID: bob, User2ID: alice, Timestamp: now}) {
+		t.Error("expected the reversed pair to be refused")
+	}
+	if s.AddMatch(models.Match{User1ID: alice, User2ID: bob, Timestamp: now}) {
+		t.Error("expected the same pair to be refused")
+	}
+
+	if n := len(s.GetAllMatches()); n != 1 {
+		t.Errorf("expected 1 stored match, got %d", n)
+	}
+	if !s.AreMatched(alice, bob) || !s.AreMatched(bob, alice) {
+		t.Error("expected AreMatched to be true in both orders")
+	}
+	if s.AreMatched(alice, uuid.New()) {
+		t.Error("expected AreMatched to be false for an unmatched pair")
+	}
+	assertPairsInSync(t, s)
+}
+
+func TestMatchPairs_StayInSync(t *testing.T) {
+	s := resetStore(t)
+	alice := makeUser("Alice", "zone-a")
+	bob := makeUser("Bob", "zone-a")
+	carol := makeUser("Carol", "zone-a")
+	for _, u := range []models.User{alice, bob, carol} {
+		s.AddUser(u)
+	}
+	now := time.Now().UTC()
+
+	ab := models.Match{ID: uuid.New(), User1ID: alice.ID, User2ID: bob.ID, Timestamp: now}
+	s.AddMatch(ab)
+	s.AddMatch(models.Match{User1ID: alice.ID, User2ID: carol.ID, Timestamp: now})
+	s.AddMatch(models.Match{User1ID: bob.ID, User2ID: carol.ID, Timestamp: now})
+	assertPairsInSync(t, s)
+
+	t.Run("remove", func(t *testing.T) {
+		s.RemoveMatch(ab.ID)
+		assertPairsInSync(t, s)
+		if s.AreMatched(alice.ID, bob.ID) {
+			t.Error("expected the removed pair to be unmatched")
+		}
+		// Once unmatched, the pair can match again.
+		if !s.AddMatch(models.Match{User1ID: bob.ID, User2ID: alice.ID, Timestamp: now}) {
+			t.Error("expected a removed pair to be matchable again")
+		}
+		assertPairsInSync(t, s)
+	})
+
+	t.Run("delete user", func(t *testing.T) {
+		s.DeleteUser(carol.ID)
+		assertPairsInSync(t, s)
+		if s.AreMatched(alice.ID, carol.ID) || s.AreMatched(bob.ID, carol.ID) {
+			t.Error("expected the deleted user's pairs to be unmatched")
+		}
+	})
+
+	t.Run("undo swipe", func(t *testing.T) {
+		s.AddSwipe(models.Swipe{SwiperID: alice.ID, SwipedID: bob.ID, Action: models.SwipeActionLike, Timestamp: now})
+		s.UndoLastSwipe(alice.ID)
+		assertPairsInSync(t, s)
+		if s.AreMatched(alice.ID, bob.ID) {
+			t.Error("expected undoing the LIKE to unmatch the pair")
+		}
+	})
+
+	t.Run("reset", func(t *testing.T) {
+		s.AddMatch(models.Match{User1ID: alice.ID, User2ID: bob.ID, Timestamp: now})
+		s.Reset()
+		assertPairsInSync(t, s)
+		if s.AreMatched(alice.ID, bob.ID) {
+			t.Error("expected no pairs after Reset")
+		}
+	})
+}
+
+// TestMatchPairs_ConcurrentAddsAndRemoves has many goroutines race to match
+// and unmatch a small set of pairs. Run with -race to also check the locking.
+func TestMatchPairs_ConcurrentAddsAndRemoves(t *testing.T) {
+	s := resetStore(t)
+
+	users := make([]uuid.UUID, 4)
+	for i := range users {
+		users[i] = uuid.New()
+	}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				a, b := users[(g+i)%len(users)], users[(g+i+1)%len(users)]
+				match := models.Match{ID: uuid.New(), User1ID: a, User2ID: b, Timestamp: time.Now().UTC()}
+				if s.AddMatch(match) && i%3 == 0 {
+					s.RemoveMatch(match.ID)
+				}
+				s.AreMatched(b, a)
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	assertPairsInSync(t, s)
+}
+
+func TestMarkMatchSeen(t *testing.T) {
+	s := resetStore(t)
+	alice := makeUser("Alice", "zone-a")
+	bob := makeUser("Bob", "zone-a")
+	carol := makeUser("Carol", "zone-a")
+	for _, u := range []models.User{alice, bob, carol} {
+		s.AddUser(u)
+	}
+	match := models.Match{ID: uuid.New(), User1ID: alice.ID, User2ID: bob.ID, Timestamp: time.Now().UTC()}
+	s.AddMatch(match)
+
+	tests := []struct {
+		name    string
+		matchID uuid.UUID
+		userID  uuid.UUID
+		want    bool
+	}{
+		{"participant", match.ID, alice.ID, true},
+		{"again", match.ID, alice.ID, true},
+		{"not a participant", match.ID, carol.ID, false},
+		{"unknown match", uuid.New(), alice.ID, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.MarkMatchSeen(tt.matchID, tt.userID); got != tt.want {
+				t.Errorf("MarkMatchSeen: got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, ok := s.GetSeenMatches(alice.ID)[match.ID]; !ok {
+		t.Error("expected Alice to have seen the match")
+	}
+	if len(s.GetSeenMatches(bob.ID)) != 0 {
+		t.Error("expected Bob's receipts to be unaffected")
+	}
+	if len(s.GetSeenMatches(carol.ID)) != 0 {
+		t.Error("expected no receipt for a non-participant")
+	}
+
+	// Removing the match drops its receipts too.
+	s.RemoveMatch(match.ID)
+	if len(s.GetSeenMatches(alice.ID)) != 0 {
+		t.Error("expected the receipt to go with the removed match")
+	}
+}
+
+func TestBlocks(t *testing.T) {
+	s := resetStore(t)
+	alice := makeUser("Alice", "zone-a")
+	bob := makeUser("Bob", "zone-a")
+	carol := makeUser("Carol", "zone-a")
+	for _, u := range []models.User{alice, bob, carol} {
+		s.AddUser(u)
+	}
+	now := time.Now().UTC()
+	s.AddMatch(models.Match{User1ID: bob.ID, User2ID: alice.ID, Timestamp: now})
+
+	block, created := s.AddBlock(models.Block{BlockerID: alice.ID, BlockedID: bob.ID, Timestamp: now})
+	if !created {
+		t.Fatal("expected a new block")
+	}
+	if !s.IsBlocked(alice.ID, bob.ID) || !s.IsBlocked(bob.ID, alice.ID) {
+		t.Error("expected the block to apply in both directions")
+	}
+	if s.IsBlocked(alice.ID, carol.ID) {
+		t.Error("expected Alice and Carol not to be blocked")
+	}
+	if s.AreMatched(alice.ID, bob.ID) {
+		t.Error("expected blocking to remove the match")
+	}
+	assertPairsInSync(t, s)
+
+	// A repeat returns the original block.
+	again, created := s.AddBlock(models.Block{BlockerID: alice.ID, BlockedID: bob.ID, Timestamp: now.Add(time.Hour)})
+	if created || !again.Timestamp.Equal(block.Timestamp) {
+		t.Errorf("expected the original block back, got %+v (created=%v)", again, created)
+	}
+
+	if got := s.GetBlockedBy(alice.ID); len(got) != 1 || got[0].BlockedID != bob.ID {
+		t.Errorf("GetBlockedBy(Alice): got %+v", got)
+	}
+	if got := s.GetBlockedBy(bob.ID); len(got) != 0 {
+		t.Errorf("GetBlockedBy(Bob): expected none, got %+v", got)
+	}
+
+	snap, _ := s.SnapshotForFeed(bob.ID, time.Time{})
+	if _, ok := snap.Blocked[alice.ID]; !ok {
+		t.Error("expected Bob's feed snapshot to list Alice as blocked")
+	}
+
+	// Deleting either user drops the block.
+	s.DeleteUser(bob.ID)
+	if s.IsBlocked(alice.ID, bob.ID) || len(s.GetBlockedBy(alice.ID)) != 0 {
+		t.Error("expected the block to go with the deleted user")
+	}
+}
+
+func TestSaveAndLoad_KeepsBlocks(t *testing.T) {
+	s := resetStore(t)
+	alice := makeUser("Alice", "zone-a")
+	bob := makeUser("Bob", "zone-a")
+	s.AddUser(alice)
+	s.AddUser(bob)
+	s.AddBlock(models.Block{BlockerID: alice.ID, BlockedID: bob.ID, Timestamp: time.Now().UTC()})
+
+	path := filepath.Join(t.TempDir(), "store.json")
+	if err := s.SaveToFile(path); err != nil {
+		t.Fatalf("SaveToFile: %v", err)
+	}
+	s.Reset()
+	if err := s.LoadFromFile(path); err != nil {
+		t.Fatalf("LoadFromFile: %v", err)
+	}
+	if !s.IsBlocked(bob.ID, alice.ID) {
+		t.Error("expected the block to survive a save and load")
+	}
+}
+
+func TestSaveAndLoad_KeepsSeenReceipts(t *testing.T) {
+	s := resetStore(t)
+	alice := makeUser("Alice", "zone-a")
+	bob := makeUser("Bob", "zone-a")
+	carol := makeUser("Carol", "zone-a")
+	for _, u := range []models.User{alice, bob, carol} {
+		s.AddUser(u)
+	}
+	seen := models.Match{ID: uuid.New(), User1ID: alice.ID, User2ID: bob.ID, Timestamp: time.Now().UTC()}
+	unseen := models.Match{ID: uuid.New(), User1ID: alice.ID, User2ID: carol.ID, Timestamp: time.Now().UTC()}
+	s.AddMatch(seen)
+	s.AddMatch(unseen)
+	s.MarkMatchSeen(seen.ID, alice.ID)
+
+	path := filepath.Join(t.TempDir(), "store.json")
+	if err := s.SaveToFile(path); err != nil {
+		t.Fatalf("SaveToFile: %v", err)
+	}
+	s.Reset()
+	if err := s.LoadFromFile(path); err != nil {
+		t.Fatalf("LoadFromFile: %v", err)
+	}
+
+	// Alice's receipt survives; nobody else gains one.
+	want := map[uuid.UUID]struct{}{seen.ID: {}}
+	if got := s.GetSeenMatches(alice.ID); !maps.Equal(got, want) {
+		t.Errorf("Alice's seen matches: got %v, want %v", got, want)
+	}
+	if got := s.GetSeenMatches(bob.ID); len(got) != 0 {
+		t.Errorf("Bob's seen matches: got %v, want none", got)
+	}
+}
+
+func TestReports(t *testing.T) {
+	s := resetStore(t)
+	alice := makeUser("Alice", "zone-a")
+	bob := makeUser("Bob", "zone-a")
+	s.AddUser(alice)
+	s.AddUser(bob)
+
+	stored := s.AddReport(models.Report{ReporterID: alice.ID, ReportedID: bob.ID, Reason: "spam", Timestamp: time.Now().UTC()})
+	if stored.ID == uuid.Nil {
+		t.Error("expected AddReport to assign an ID")
+	}
+
+	// Reports outlive the users they mention.
+	s.DeleteUser(bob.ID)
+	reports := s.GetReports()
+	if len(reports) != 1 || reports[0].ID != stored.ID {
+		t.Fatalf("expected the report to be kept, got %+v", reports)
+	}
+
+	// The result is a copy.
+	reports[0].Reason = "changed"
+	if s.GetReports()[0].Reason != "spam" {
+		t.Error("expected GetReports to return a copy")
+	}
+}
+
+func TestGetUnswipedUsers(t *testing.T) {
+	s := resetStore(t)
+	alice := makeUser("Alice", "zone-a")
+	bob := makeUser("Bob", "zone-a")
+	carol := makeUser("Carol", "zone-a")
+	s.AddUser(alice)
+	s.AddUser(bob)
+	s.AddUser(carol)
+
+	// Bob received a LIKE and Carol a PASS; only Alice is still unswiped.
+	now := time.Now().UTC()
+	s.AddSwipe(models.Swipe{SwiperID: alice.ID, SwipedID: bob.ID, Action: models.SwipeActionLike, Timestamp: now})
+	s.AddSwipe(models.Swipe{SwiperID: bob.ID, SwipedID: carol.ID, Action: models.SwipeActionPass, Timestamp: now})
+
+	unswiped := s.GetUnswipedUsers()
+	if len(unswiped) != 1 || unswiped[0].ID != alice.ID {
+		t.Errorf("expected only Alice, got %+v", unswiped)
+	}
+}
+
+func TestStats(t *testing.T) {
+	s := resetStore(t)
+
+	// An empty store reports zeros and an empty (not nil) zone map.
+	if stats := s.Stats(); stats.TotalUsers != 0 || stats.TotalSwipes != 0 || stats.UsersByZone == nil {
+		t.Errorf("empty store: got %+v", stats)
+	}
+
+	alice := makeUser("Alice", "zone-a")
+	bob := makeUser("Bob", "zone-a")
+	carol := makeUser("Carol", "zone-b")
+	for _, user := range []models.User{alice, bob, carol} {
+		s.AddUser(user)
+	}
+	now := time.Now().UTC()
+	s.AddSwipe(models.Swipe{SwiperID: alice.ID, SwipedID: bob.ID, Action: models.SwipeActionLike, Timestamp: now})
+	s.AddSwipe(models.Swipe{SwiperID: bob.ID, SwipedID: alice.ID, Action: models.SwipeActionLike, Timestamp: now})
+	s.AddSwipe(models.Swipe{SwiperID: carol.ID, SwipedID: bob.ID, Action: models.SwipeActionPass, Timestamp: now})
+	s.AddMatch(models.Match{ID: uuid.New(), User1ID: alice.ID, User2ID: bob.ID, Timestamp: now})
+
+	stats := s.Stats()
+	if stats.TotalUsers != 3 || stats.TotalSwipes != 3 || stats.Likes != 2 || stats.Passes != 1 || stats.TotalMatches != 1 {
+		t.Errorf("totals: got %+v", stats)
+	}
+	if len(stats.UsersByZone) != 2 || stats.UsersByZone["zone-a"] != 2 || stats.UsersByZone["zone-b"] != 1 {
+		t.Errorf("users by zone: got %v", stats.UsersByZone)
+	}
+}
@@ -0,0 +1,85 @@
+// This file implements a small least-recently-used (LRU) cache for user
+// lookups, used by InMemoryStore.GetUser when enabled with
+// SetUserCacheSize.
+package store
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/dlfelps/tinder-go-claude/internal/models"
+	"github.com/google/uuid"
+)
+
+// userCache is a fixed-size LRU cache of users keyed by ID.
+//
+// Even a cache hit reorders the list, so every method takes the cache's own
+// mutex: GetUser holds only the store's read lock, and several readers may
+// use the cache at once. Keeping the cache in step with the users map is
+// still the store's job. Writers invalidate entries under the store's
+// write lock, which no reader can hold at the same time, so a reader can
+// never put back a value that a concurrent write has replaced.
+//
+// The classic LRU layout is a doubly linked list ordered by recency (front
+// is most recent) plus a map from key to list element, so lookups, moves to
+// the front, and evictions from the back are all O(1). Go's container/list
+// provides the linked list.
+type userCache struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List                  // Elements hold models.User values.
+	entries  map[uuid.UUID]*list.Element // ID -> element in order.
+}
+
+// newUserCache creates an empty cache holding at most capacity users.
+// capacity must be positive.
+func newUserCache(capacity int) *userCache {
+	return &userCache{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[uuid.UUID]*list.Element, capacity),
+	}
+}
+
+// get returns the cached user and marks it as most recently used.
+func (c *userCache) get(id uuid.UUID) (models.User, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[id]
+	if !ok {
+		return models.User{}, false
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(models.User), true
+}
+
+// put caches user, evicting the least recently used entry if full.
+func (c *userCache) put(user models.User) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[user.ID]; ok {
+		elem.Value = user
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.entries[user.ID] = c.order.PushFront(user)
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(models.User).ID)
+	}
+}
+
+// invalidate drops id from the cache, if present. The next lookup reads
+// through to the users map and sees the current value.
+func (c *userCache) invalidate(id uuid.UUID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[id]; ok {
+		c.order.Remove(elem)
+		delete(c.entries, id)
+	}
+}